@@ -19,6 +19,9 @@
 package account
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/ontio/ontology-crypto/keypair"
 	s "github.com/ontio/ontology-crypto/signature"
 	"github.com/ontio/ontology/common"
@@ -33,6 +36,23 @@ type Account struct {
 	SigScheme  s.SignatureScheme
 }
 
+type keyParams struct {
+	keyType keypair.KeyType
+	curve   byte
+	scheme  s.SignatureScheme
+}
+
+//keyTypes are the key type names which could be used as encrypt besides signature scheme names,
+//the key is generated with the curve and signed with the default scheme of the key type
+var keyTypes = map[string]keyParams{
+	"ecdsa":     {keypair.PK_ECDSA, keypair.P256, s.SHA256withECDSA},
+	"secp256k1": {keypair.PK_ECDSA, keypair.SECP256K1, s.SHA256withECDSA},
+	"sm2":       {keypair.PK_SM2, keypair.SM2P256V1, s.SM3withSM2},
+	"ed25519":   {keypair.PK_EDDSA, keypair.ED25519, s.SHA512withEDDSA},
+}
+
+//NewAccount creates account of the signature scheme or key type name encrypt,
+//SHA256withECDSA over P-256 is used if encrypt is empty or unknown
 func NewAccount(encrypt string) *Account {
 	// Determine the public key algorithm and parameters according to
 	// the config file.
@@ -41,37 +61,45 @@ func NewAccount(encrypt string) *Account {
 	var params interface{}
 	var scheme s.SignatureScheme
 	var err error
-	if "" != encrypt {
-		scheme, err = s.GetScheme(encrypt)
+	if kp, ok := keyTypes[strings.ToLower(encrypt)]; ok {
+		pkAlgorithm, params, scheme = kp.keyType, kp.curve, kp.scheme
 	} else {
-		scheme = s.SHA256withECDSA
+		if "" != encrypt {
+			scheme, err = s.GetScheme(encrypt)
+		} else {
+			scheme = s.SHA256withECDSA
+		}
+		if err != nil {
+			log.Warn("unknown signature scheme, use SHA256withECDSA as default.")
+			scheme = s.SHA256withECDSA
+		}
+		switch scheme {
+		case s.SHA224withECDSA, s.SHA3_224withECDSA:
+			pkAlgorithm = keypair.PK_ECDSA
+			params = keypair.P224
+		case s.SHA256withECDSA, s.SHA3_256withECDSA, s.RIPEMD160withECDSA:
+			pkAlgorithm = keypair.PK_ECDSA
+			params = keypair.P256
+		case s.SHA384withECDSA, s.SHA3_384withECDSA:
+			pkAlgorithm = keypair.PK_ECDSA
+			params = keypair.P384
+		case s.SHA512withECDSA, s.SHA3_512withECDSA:
+			pkAlgorithm = keypair.PK_ECDSA
+			params = keypair.P521
+		case s.SM3withSM2:
+			pkAlgorithm = keypair.PK_SM2
+			params = keypair.SM2P256V1
+		case s.SHA512withEDDSA:
+			pkAlgorithm = keypair.PK_EDDSA
+			params = keypair.ED25519
+		}
 	}
+
+	pri, pub, err := keypair.GenerateKeyPair(pkAlgorithm, params)
 	if err != nil {
-		log.Warn("unknown signature scheme, use SHA256withECDSA as default.")
-		scheme = s.SHA256withECDSA
+		log.Errorf("generate key pair error %s", err)
+		return nil
 	}
-	switch scheme {
-	case s.SHA224withECDSA, s.SHA3_224withECDSA:
-		pkAlgorithm = keypair.PK_ECDSA
-		params = keypair.P224
-	case s.SHA256withECDSA, s.SHA3_256withECDSA, s.RIPEMD160withECDSA:
-		pkAlgorithm = keypair.PK_ECDSA
-		params = keypair.P256
-	case s.SHA384withECDSA, s.SHA3_384withECDSA:
-		pkAlgorithm = keypair.PK_ECDSA
-		params = keypair.P384
-	case s.SHA512withECDSA, s.SHA3_512withECDSA:
-		pkAlgorithm = keypair.PK_ECDSA
-		params = keypair.P521
-	case s.SM3withSM2:
-		pkAlgorithm = keypair.PK_SM2
-		params = keypair.SM2P256V1
-	case s.SHA512withEDDSA:
-		pkAlgorithm = keypair.PK_EDDSA
-		params = keypair.ED25519
-	}
-
-	pri, pub, _ := keypair.GenerateKeyPair(pkAlgorithm, params)
 	address := types.AddressFromPubKey(pub)
 	return &Account{
 		PrivateKey: pri,
@@ -81,20 +109,40 @@ func NewAccount(encrypt string) *Account {
 	}
 }
 
+//NewAccountWithPrivatekey creates account of the serialized private key,
+//it is signed with the default scheme of the key type
 func NewAccountWithPrivatekey(privateKey []byte) (*Account, error) {
 	pri, err := keypair.DeserializePrivateKey(privateKey)
 	if err != nil {
 		return nil, err
 	}
 	pub := pri.Public()
+	scheme, err := DefaultScheme(keypair.GetKeyType(pub))
+	if err != nil {
+		return nil, err
+	}
 	address := types.AddressFromPubKey(pub)
 	return &Account{
 		PrivateKey: pri,
 		PublicKey:  pub,
 		Address:    address,
+		SigScheme:  scheme,
 	}, nil
 }
 
+//DefaultScheme returns the default signature scheme of the key type
+func DefaultScheme(keyType keypair.KeyType) (s.SignatureScheme, error) {
+	switch keyType {
+	case keypair.PK_ECDSA:
+		return s.SHA256withECDSA, nil
+	case keypair.PK_SM2:
+		return s.SM3withSM2, nil
+	case keypair.PK_EDDSA:
+		return s.SHA512withEDDSA, nil
+	}
+	return 0, fmt.Errorf("unsupported key type %d", keyType)
+}
+
 func (ac *Account) PrivKey() keypair.PrivateKey {
 	return ac.PrivateKey
 }
@@ -250,6 +250,9 @@ func (cl *ClientImpl) ContainsAccount(pubKey keypair.PublicKey) bool {
 
 func (cl *ClientImpl) CreateAccount(encrypt string) (*Account, error) {
 	ac := NewAccount(encrypt)
+	if ac == nil {
+		return nil, fmt.Errorf("create account of %s error", encrypt)
+	}
 
 	cl.mu.Lock()
 	cl.accounts[ac.Address] = ac
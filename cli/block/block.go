@@ -0,0 +1,184 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package block
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/urfave/cli"
+
+	"github.com/ontio/ontology/account"
+	clicommon "github.com/ontio/ontology/cli/common"
+	"github.com/ontio/ontology/core/genesis"
+	"github.com/ontio/ontology/core/store/ledgerstore"
+)
+
+//Progress is printed every PROGRESS_INTERVAL blocks
+const PROGRESS_INTERVAL = 1000
+
+func printProgress(action string) func(height, endHeight uint32) {
+	return func(height, endHeight uint32) {
+		if height%PROGRESS_INTERVAL == 0 || height == endHeight {
+			fmt.Printf("%s block height %d/%d\n", action, height, endHeight)
+		}
+	}
+}
+
+func exportAction(c *cli.Context) error {
+	file := c.String("file")
+	if file == "" {
+		fmt.Println("Invalid block file: ", file)
+		os.Exit(1)
+	}
+	//Ledger store is opened from the node's working directory, so the node must be stopped
+	ledgerStore, err := ledgerstore.NewLedgerStore()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Open ledger store error:", err)
+		os.Exit(1)
+	}
+	start := uint32(c.Uint("start"))
+	end := uint32(c.Uint("end"))
+	if end == 0 {
+		end = ledgerStore.GetCurrentBlockHeight()
+	}
+	err = exportBlocks(ledgerStore, start, end, file)
+	ledgerStore.Close()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Export blocks error:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Export blocks of height [%d, %d] to %s\n", start, end, file)
+	return nil
+}
+
+func exportBlocks(ledgerStore *ledgerstore.LedgerStoreImp, start, end uint32, file string) error {
+	f, err := os.OpenFile(file, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	writer := bufio.NewWriter(f)
+	err = ledgerStore.ExportBlocks(start, end, writer, printProgress("Export"))
+	if err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+func importAction(c *cli.Context) error {
+	file := c.String("file")
+	if file == "" {
+		fmt.Println("Invalid block file: ", file)
+		os.Exit(1)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Open block file error:", err)
+		os.Exit(1)
+	}
+	defer f.Close()
+	//Bookkeepers of genesis block are the same as the node starting
+	client := account.GetClient()
+	if client == nil {
+		fmt.Fprintln(os.Stderr, "Open wallet error")
+		os.Exit(1)
+	}
+	bookkeepers, err := client.GetBookkeepers()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "GetBookkeepers error:", err)
+		os.Exit(1)
+	}
+	ledgerStore, err := ledgerstore.NewLedgerStore()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Open ledger store error:", err)
+		os.Exit(1)
+	}
+	err = importBlocks(ledgerStore, bookkeepers, bufio.NewReader(f))
+	height := ledgerStore.GetCurrentBlockHeight()
+	ledgerStore.Close()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Import blocks error:", err)
+		fmt.Fprintf(os.Stderr, "Ledger is at block height %d, import the file again to resume\n", height)
+		os.Exit(1)
+	}
+	fmt.Printf("Import blocks from %s, current block height %d\n", file, height)
+	return nil
+}
+
+//importBlocks initialize the empty ledger with the genesis block of bookkeepers, or check the genesis block of ledger,
+//then import the blocks in block stream r
+func importBlocks(ledgerStore *ledgerstore.LedgerStoreImp, bookkeepers []keypair.PublicKey, r io.Reader) error {
+	sort.Sort(keypair.NewPublicList(bookkeepers))
+	genesisBlock, err := genesis.GenesisBlockInit(bookkeepers)
+	if err != nil {
+		return fmt.Errorf("GenesisBlockInit error %s", err)
+	}
+	err = ledgerStore.InitLedgerStoreWithGenesisBlock(genesisBlock, bookkeepers)
+	if err != nil {
+		return fmt.Errorf("InitLedgerStoreWithGenesisBlock error %s", err)
+	}
+	_, err = ledgerStore.ImportBlocks(r, printProgress("Import"))
+	return err
+}
+
+func NewCommand() *cli.Command {
+	fileFlag := cli.StringFlag{
+		Name:  "file, f",
+		Usage: "block stream file",
+	}
+	return &cli.Command{
+		Name:        "block",
+		Usage:       "export or import blocks",
+		Description: "With nodectl block, you could export blocks of a stopped node to a block stream file, or import the blocks in it with full verification. Empty ledger is initialized with the genesis block of config before importing. Run it in the node's working directory.",
+		ArgsUsage:   "[args]",
+		Subcommands: []cli.Command{
+			{
+				Name:  "export",
+				Usage: "export blocks to block stream file",
+				Flags: []cli.Flag{
+					fileFlag,
+					cli.UintFlag{
+						Name:  "start",
+						Usage: "start block height",
+					},
+					cli.UintFlag{
+						Name:  "end",
+						Usage: "end block height, current block height by default",
+					},
+				},
+				Action: exportAction,
+			},
+			{
+				Name:   "import",
+				Usage:  "import blocks from block stream file, blocks already in ledger are skipped",
+				Flags:  []cli.Flag{fileFlag},
+				Action: importAction,
+			},
+		},
+		OnUsageError: func(c *cli.Context, err error, isSubcommand bool) error {
+			clicommon.PrintError(c, err, "block")
+			return cli.NewExitError("", 1)
+		},
+	}
+}
@@ -0,0 +1,163 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package block
+
+import (
+	"bufio"
+	"os"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/core/genesis"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/ontio/ontology/core/store/ledgerstore"
+	"github.com/ontio/ontology/core/types"
+)
+
+func init() {
+	log.Init(log.Stdout)
+}
+
+func newTestLedgerStore(dir string) (*ledgerstore.LedgerStoreImp, error) {
+	ledgerstore.DBDirEvent = dir + "/ledgerevent"
+	ledgerstore.DBDirBlock = dir + "/block"
+	ledgerstore.DBDirState = dir + "/states"
+	ledgerstore.MerkleTreeStorePath = dir + "/merkle_tree.db"
+	ledgerstore.BlockCommitLogPath = dir + "/block_commit.log"
+	return ledgerstore.NewLedgerStore()
+}
+
+//newTestBlock make the next block of ledger signed by the only bookkeeper acc
+func newTestBlock(ledgerStore *ledgerstore.LedgerStoreImp, acc *account.Account) (*types.Block, error) {
+	bookkeepers := []keypair.PublicKey{acc.PublicKey}
+	nextBookkeeper, err := types.AddressFromBookkeepers(bookkeepers)
+	if err != nil {
+		return nil, err
+	}
+	prevHeader, err := ledgerStore.GetHeaderByHash(ledgerStore.GetCurrentBlockHash())
+	if err != nil {
+		return nil, err
+	}
+	tx := &types.Transaction{
+		TxType:     types.BookKeeping,
+		Payload:    &payload.BookKeeping{Nonce: uint64(prevHeader.Height + 1)},
+		Attributes: []*types.TxAttribute{},
+	}
+	txRoot, err := common.ComputeMerkleRoot([]common.Uint256{tx.Hash()})
+	if err != nil {
+		return nil, err
+	}
+	stateRoot, err := ledgerStore.GetCurrentStateRoot()
+	if err != nil {
+		return nil, err
+	}
+	block := &types.Block{
+		Header: &types.Header{
+			PrevBlockHash:    prevHeader.Hash(),
+			TransactionsRoot: txRoot,
+			BlockRoot:        ledgerStore.GetBlockRootWithNewTxRoot(txRoot),
+			StateRoot:        stateRoot,
+			Timestamp:        prevHeader.Timestamp + 1,
+			Height:           prevHeader.Height + 1,
+			NextBookkeeper:   nextBookkeeper,
+			Bookkeepers:      bookkeepers,
+		},
+		Transactions: []*types.Transaction{tx},
+	}
+	blockHash := block.Hash()
+	sig, err := signature.Sign(acc, blockHash[:])
+	if err != nil {
+		return nil, err
+	}
+	block.Header.SigData = [][]byte{sig}
+	return block, nil
+}
+
+func TestImportBlocksToEmptyLedger(t *testing.T) {
+	defer os.RemoveAll("test")
+	acc := account.NewAccount("")
+	bookkeepers := []keypair.PublicKey{acc.PublicKey}
+	blockCount := uint32(3)
+
+	srcStore, err := newTestLedgerStore("test/src")
+	if err != nil {
+		t.Errorf("NewLedgerStore error %s", err)
+		return
+	}
+	defer srcStore.Close()
+	genesisBlock, err := genesis.GenesisBlockInit(bookkeepers)
+	if err != nil {
+		t.Errorf("GenesisBlockInit error %s", err)
+		return
+	}
+	err = srcStore.InitLedgerStoreWithGenesisBlock(genesisBlock, bookkeepers)
+	if err != nil {
+		t.Errorf("InitLedgerStoreWithGenesisBlock error %s", err)
+		return
+	}
+	for i := uint32(0); i < blockCount; i++ {
+		block, err := newTestBlock(srcStore, acc)
+		if err != nil {
+			t.Errorf("newTestBlock error %s", err)
+			return
+		}
+		err = srcStore.AddBlock(block)
+		if err != nil {
+			t.Errorf("AddBlock error %s", err)
+			return
+		}
+	}
+	file := "test/blocks.dat"
+	err = exportBlocks(srcStore, 0, blockCount, file)
+	if err != nil {
+		t.Errorf("exportBlocks error %s", err)
+		return
+	}
+
+	dstStore, err := newTestLedgerStore("test/dst")
+	if err != nil {
+		t.Errorf("NewLedgerStore error %s", err)
+		return
+	}
+	defer dstStore.Close()
+	f, err := os.Open(file)
+	if err != nil {
+		t.Errorf("Open error %s", err)
+		return
+	}
+	defer f.Close()
+	err = importBlocks(dstStore, bookkeepers, bufio.NewReader(f))
+	if err != nil {
+		t.Errorf("importBlocks error %s", err)
+		return
+	}
+	if dstStore.GetCurrentBlockHeight() != blockCount {
+		t.Errorf("TestImportBlocksToEmptyLedger failed block height %d != %d", dstStore.GetCurrentBlockHeight(), blockCount)
+		return
+	}
+	if dstStore.GetCurrentBlockHash() != srcStore.GetCurrentBlockHash() {
+		t.Errorf("TestImportBlocksToEmptyLedger failed block hash %x != %x", dstStore.GetCurrentBlockHash(), srcStore.GetCurrentBlockHash())
+		return
+	}
+}
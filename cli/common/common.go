@@ -20,13 +20,19 @@ package common
 
 import (
 	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"strconv"
+	"strings"
 
+	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/password"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/http/base/rpc"
 
 	"github.com/urfave/cli"
 )
@@ -87,3 +93,68 @@ func WalletPassword(passwd string) []byte {
 		return []byte(passwd)
 	}
 }
+
+// WriteTransactionFile writes the hex string of the serialized transaction to file,
+// so that the transaction could be passed between co-signers offline
+func WriteTransactionFile(file string, tx *types.Transaction) error {
+	buf := new(bytes.Buffer)
+	if err := tx.Serialize(buf); err != nil {
+		return fmt.Errorf("serialize transaction error %s", err)
+	}
+	return ioutil.WriteFile(file, []byte(hex.EncodeToString(buf.Bytes())), 0644)
+}
+
+// ReadTransactionFile reads the transaction written by WriteTransactionFile
+func ReadTransactionFile(file string) (*types.Transaction, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("decode transaction error %s", err)
+	}
+	tx := new(types.Transaction)
+	if err := tx.Deserialize(bytes.NewReader(raw)); err != nil {
+		return nil, fmt.Errorf("deserialize transaction error %s", err)
+	}
+	return tx, nil
+}
+
+// SendRawTransaction sends the transaction to node by rpc, and returns the rpc result
+func SendRawTransaction(tx *types.Transaction) (interface{}, error) {
+	buf := new(bytes.Buffer)
+	if err := tx.Serialize(buf); err != nil {
+		return nil, fmt.Errorf("serialize transaction error %s", err)
+	}
+	resp, err := rpc.Call(RpcAddress(), "sendrawtransaction", 0,
+		[]interface{}{hex.EncodeToString(buf.Bytes())})
+	if err != nil {
+		return nil, err
+	}
+	r := make(map[string]interface{})
+	if err := json.Unmarshal(resp, &r); err != nil {
+		return nil, fmt.Errorf("unmarshal JSON error %s", err)
+	}
+	if errCode, ok := r["error"].(float64); ok && errCode != 0 {
+		return nil, fmt.Errorf("%v %v", r["desc"], r["result"])
+	}
+	return r["result"], nil
+}
+
+// ParsePublicKeys parses the comma separated hex strings of public keys
+func ParsePublicKeys(keys string) ([]keypair.PublicKey, error) {
+	var pubKeys []keypair.PublicKey
+	for _, key := range strings.Split(keys, ",") {
+		data, err := hex.DecodeString(strings.TrimSpace(key))
+		if err != nil {
+			return nil, fmt.Errorf("decode public key %s error %s", key, err)
+		}
+		pubKey, err := keypair.DeserializePublicKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("deserialize public key %s error %s", key, err)
+		}
+		pubKeys = append(pubKeys, pubKey)
+	}
+	return pubKeys, nil
+}
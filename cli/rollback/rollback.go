@@ -0,0 +1,73 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rollback
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli"
+
+	clicommon "github.com/ontio/ontology/cli/common"
+	"github.com/ontio/ontology/core/store/ledgerstore"
+)
+
+func rollbackAction(c *cli.Context) error {
+	if c.NumFlags() == 0 {
+		cli.ShowSubcommandHelp(c)
+		return nil
+	}
+	height := c.Uint("height")
+
+	//Ledger store is opened from the node's working directory, so the node must be stopped
+	ledgerStore, err := ledgerstore.NewLedgerStore()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Open ledger store error:", err)
+		os.Exit(1)
+	}
+	currHeight := ledgerStore.GetCurrentBlockHeight()
+	err = ledgerStore.RollbackTo(uint32(height))
+	ledgerStore.Close()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Rollback error:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Rollback ledger from block height %d to %d\n", currHeight, height)
+	return nil
+}
+
+func NewCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "rollback",
+		Usage:       "roll back ledger to block height",
+		Description: "With nodectl rollback, you could roll back the ledger of a stopped node to a block height. Run it in the node's working directory.",
+		ArgsUsage:   "[args]",
+		Flags: []cli.Flag{
+			cli.UintFlag{
+				Name:  "height",
+				Usage: "block height to roll back to",
+			},
+		},
+		Action: rollbackAction,
+		OnUsageError: func(c *cli.Context, err error, isSubcommand bool) error {
+			clicommon.PrintError(c, err, "rollback")
+			return cli.NewExitError("", 1)
+		},
+	}
+}
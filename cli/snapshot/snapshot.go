@@ -0,0 +1,136 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package snapshot
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli"
+
+	clicommon "github.com/ontio/ontology/cli/common"
+	"github.com/ontio/ontology/core/store/ledgerstore"
+)
+
+func exportAction(c *cli.Context) error {
+	file := c.String("file")
+	if file == "" {
+		fmt.Println("Invalid snapshot file: ", file)
+		os.Exit(1)
+	}
+	//Ledger store is opened from the node's working directory, so the node must be stopped
+	ledgerStore, err := ledgerstore.NewLedgerStore()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Open ledger store error:", err)
+		os.Exit(1)
+	}
+	height := uint32(c.Uint("height"))
+	if height == 0 {
+		//State root of snapshot height is committed in the next block
+		height = ledgerStore.GetCurrentBlockHeight() - 1
+	}
+	err = exportSnapshot(ledgerStore, height, file)
+	ledgerStore.Close()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Export snapshot error:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Export snapshot of block height %d to %s\n", height, file)
+	return nil
+}
+
+func exportSnapshot(ledgerStore *ledgerstore.LedgerStoreImp, height uint32, file string) error {
+	f, err := os.OpenFile(file, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	writer := bufio.NewWriter(f)
+	err = ledgerStore.ExportSnapshot(height, writer)
+	if err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+func importAction(c *cli.Context) error {
+	file := c.String("file")
+	if file == "" {
+		fmt.Println("Invalid snapshot file: ", file)
+		os.Exit(1)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Open snapshot file error:", err)
+		os.Exit(1)
+	}
+	defer f.Close()
+	ledgerStore, err := ledgerstore.NewLedgerStore()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Open ledger store error:", err)
+		os.Exit(1)
+	}
+	err = ledgerStore.ImportSnapshot(bufio.NewReader(f))
+	height := ledgerStore.GetCurrentBlockHeight()
+	ledgerStore.Close()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Import snapshot error:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Import snapshot of block height %d from %s\n", height, file)
+	return nil
+}
+
+func NewCommand() *cli.Command {
+	fileFlag := cli.StringFlag{
+		Name:  "file, f",
+		Usage: "snapshot file",
+	}
+	return &cli.Command{
+		Name:        "snapshot",
+		Usage:       "export or import ledger snapshot",
+		Description: "With nodectl snapshot, you could export the ledger state of a stopped node to a snapshot file, or bootstrap an empty node from it. Run it in the node's working directory.",
+		ArgsUsage:   "[args]",
+		Subcommands: []cli.Command{
+			{
+				Name:  "export",
+				Usage: "export ledger state to snapshot file",
+				Flags: []cli.Flag{
+					fileFlag,
+					cli.UintFlag{
+						Name:  "height",
+						Usage: "block height of snapshot, lower than current block height, current block height - 1 by default",
+					},
+				},
+				Action: exportAction,
+			},
+			{
+				Name:   "import",
+				Usage:  "initialize empty ledger with snapshot file",
+				Flags:  []cli.Flag{fileFlag},
+				Action: importAction,
+			},
+		},
+		OnUsageError: func(c *cli.Context, err error, isSubcommand bool) error {
+			clicommon.PrintError(c, err, "snapshot")
+			return cli.NewExitError("", 1)
+		},
+	}
+}
@@ -32,14 +32,12 @@ import (
 	"github.com/ontio/ontology/account"
 	clicommon "github.com/ontio/ontology/cli/common"
 	"github.com/ontio/ontology/common"
-	"github.com/ontio/ontology/core/signature"
 	ctypes "github.com/ontio/ontology/core/types"
 	cutils "github.com/ontio/ontology/core/utils"
 	"github.com/ontio/ontology/http/base/rpc"
 	"github.com/ontio/ontology/smartcontract/states"
 	nstates "github.com/ontio/ontology/smartcontract/service/native/states"
 	vmtypes "github.com/ontio/ontology/smartcontract/types"
-	"github.com/ontio/ontology-crypto/keypair"
 )
 
 func transferAction(c *cli.Context) error {
@@ -110,6 +108,19 @@ func transferAction(c *cli.Context) error {
 	})
 
 	tx.Nonce = uint32(time.Now().Unix())
+	if validUntil := c.Uint("validuntil"); validUntil > 0 {
+		tx.Attributes = append(tx.Attributes, ctypes.NewValidUntilAttribute(uint32(validUntil)))
+	}
+
+	//Unsigned transaction is written to file, co-signers of multi-signature sender sign it by nodectl tx sign
+	if file := c.String("file"); file != "" {
+		if err := clicommon.WriteTransactionFile(file, tx); err != nil {
+			fmt.Println("Write transaction file error:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Unsigned transaction %x is written to %s\n", tx.Hash(), file)
+		return nil
+	}
 
 	passwd := c.String("password")
 
@@ -120,7 +131,7 @@ func transferAction(c *cli.Context) error {
 		os.Exit(1)
 	}
 
-	if err := signTransaction(acc, tx); err != nil {
+	if err := cutils.SignTransaction(acc, tx); err != nil {
 		fmt.Println("signTransaction error:", err)
 		os.Exit(1)
 	}
@@ -153,17 +164,6 @@ func transferAction(c *cli.Context) error {
 	return nil
 }
 
-func signTransaction(signer *account.Account, tx *ctypes.Transaction) error {
-	hash := tx.Hash()
-	sign, _ := signature.Sign(signer, hash[:])
-	tx.Sigs = append(tx.Sigs, &ctypes.Sig{
-		PubKeys: []keypair.PublicKey{signer.PublicKey},
-		M:       1,
-		SigData: [][]byte{sign},
-	})
-	return nil
-}
-
 func NewCommand() *cli.Command {
 	return &cli.Command{
 		Name:        "transfer",
@@ -192,6 +192,14 @@ func NewCommand() *cli.Command {
 				Usage: "wallet password",
 				Value: "passwordtest",
 			},
+			cli.UintFlag{
+				Name:  "validuntil",
+				Usage: "last block height the transaction could be included in, never expires by default",
+			},
+			cli.StringFlag{
+				Name:  "file, o",
+				Usage: "write the unsigned transaction to file instead of signing and sending it",
+			},
 		},
 		Action: transferAction,
 		OnUsageError: func(c *cli.Context, err error, isSubcommand bool) error {
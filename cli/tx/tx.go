@@ -0,0 +1,186 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package tx
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli"
+
+	"github.com/ontio/ontology/account"
+	clicommon "github.com/ontio/ontology/cli/common"
+	ctypes "github.com/ontio/ontology/core/types"
+	cutils "github.com/ontio/ontology/core/utils"
+)
+
+func signAction(c *cli.Context) error {
+	file := c.String("file")
+	if file == "" {
+		fmt.Println("Invalid transaction file: ", file)
+		os.Exit(1)
+	}
+	tx, err := clicommon.ReadTransactionFile(file)
+	if err != nil {
+		fmt.Println("Read transaction file error:", err)
+		os.Exit(1)
+	}
+	wallet := account.Open(c.String("name"), clicommon.WalletPassword(c.String("password")))
+	if wallet == nil {
+		fmt.Println("Failed to open wallet: ", c.String("name"))
+		os.Exit(1)
+	}
+	acc := wallet.GetDefaultAccount()
+	if acc == nil {
+		fmt.Println(" can not get default account")
+		os.Exit(1)
+	}
+	if c.IsSet("pubkeys") {
+		pubKeys, err := clicommon.ParsePublicKeys(c.String("pubkeys"))
+		if err != nil {
+			fmt.Println("Invalid public keys:", err)
+			os.Exit(1)
+		}
+		err = cutils.MultiSignTransaction(acc, uint8(c.Uint("m")), pubKeys, tx)
+	} else {
+		err = cutils.SignTransaction(acc, tx)
+	}
+	if err != nil {
+		fmt.Println("Sign transaction error:", err)
+		os.Exit(1)
+	}
+	output := c.String("output")
+	if output == "" {
+		output = file
+	}
+	if err := clicommon.WriteTransactionFile(output, tx); err != nil {
+		fmt.Println("Write transaction file error:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Signed transaction %x is written to %s\n", tx.Hash(), output)
+	printSigs(tx)
+	return nil
+}
+
+func combineAction(c *cli.Context) error {
+	if c.NArg() == 0 {
+		fmt.Println("Missing signed transaction files")
+		os.Exit(1)
+	}
+	var txs []*ctypes.Transaction
+	for _, file := range c.Args() {
+		tx, err := clicommon.ReadTransactionFile(file)
+		if err != nil {
+			fmt.Printf("Read transaction file %s error: %s\n", file, err)
+			os.Exit(1)
+		}
+		txs = append(txs, tx)
+	}
+	tx, err := cutils.CombineTransactionSigs(txs)
+	if err != nil {
+		fmt.Println("Combine transaction error:", err)
+		os.Exit(1)
+	}
+	printSigs(tx)
+	if output := c.String("output"); output != "" {
+		if err := clicommon.WriteTransactionFile(output, tx); err != nil {
+			fmt.Println("Write transaction file error:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Combined transaction %x is written to %s\n", tx.Hash(), output)
+	}
+	if c.Bool("send") {
+		result, err := clicommon.SendRawTransaction(tx)
+		if err != nil {
+			fmt.Println("Send transaction error:", err)
+			os.Exit(1)
+		}
+		fmt.Println("Send transaction:", result)
+	}
+	return nil
+}
+
+func printSigs(tx *ctypes.Transaction) {
+	for _, sig := range tx.Sigs {
+		fmt.Printf("%d/%d signatures of %d-of-%d signer\n", len(sig.SigData), sig.M, sig.M, len(sig.PubKeys))
+	}
+}
+
+func NewCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "tx",
+		Usage:       "sign and combine transactions offline",
+		Description: "With nodectl tx, co-signers of multi-signature address could sign the unsigned transaction file offline, and combine their signatures to send it.",
+		ArgsUsage:   "[args]",
+		Subcommands: []cli.Command{
+			{
+				Name:  "sign",
+				Usage: "sign transaction file with the default account of wallet",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "file, f",
+						Usage: "transaction file",
+					},
+					cli.StringFlag{
+						Name:  "output, o",
+						Usage: "signed transaction file, the input file is overwritten by default",
+					},
+					cli.StringFlag{
+						Name:  "pubkeys",
+						Usage: "comma separated hex public keys of multi-signature co-signers",
+					},
+					cli.UintFlag{
+						Name:  "m",
+						Usage: "minimum number of signatures of multi-signature",
+					},
+					cli.StringFlag{
+						Name:  "name, n",
+						Usage: "wallet name",
+						Value: account.WALLET_FILENAME,
+					},
+					cli.StringFlag{
+						Name:  "password, p",
+						Usage: "wallet password",
+					},
+				},
+				Action: signAction,
+			},
+			{
+				Name:      "combine",
+				Usage:     "combine signatures of signed transaction files",
+				ArgsUsage: "<signed transaction files>",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "output, o",
+						Usage: "combined transaction file",
+					},
+					cli.BoolFlag{
+						Name:  "send, s",
+						Usage: "send the combined transaction to node",
+					},
+				},
+				Action: combineAction,
+			},
+		},
+		OnUsageError: func(c *cli.Context, err error, isSubcommand bool) error {
+			clicommon.PrintError(c, err, "tx")
+			return cli.NewExitError("", 1)
+		},
+	}
+}
@@ -28,6 +28,7 @@ import (
 	cliCommon "github.com/ontio/ontology/cli/common"
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/password"
+	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/http/base/rpc"
 	"github.com/urfave/cli"
 )
@@ -98,6 +99,7 @@ func walletAction(c *cli.Context) error {
 
 	pubKeyBytes := keypair.SerializePublicKey(pubKey)
 	fmt.Println("public key:   ", common.ToHexString(pubKeyBytes))
+	fmt.Println("signature scheme:", account.Scheme().Name())
 	fmt.Println("hex address: ", common.ToHexString(address[:]))
 	fmt.Println("base58 address:      ", address.ToBase58())
 	balance := c.Bool("balance")
@@ -129,6 +131,24 @@ func walletAction(c *cli.Context) error {
 	return nil
 }
 
+func multisigAction(c *cli.Context) error {
+	pubKeys, err := cliCommon.ParsePublicKeys(c.String("pubkeys"))
+	if err != nil {
+		fmt.Println("Invalid public keys:", err)
+		os.Exit(1)
+	}
+	m := c.Int("m")
+	address, err := types.AddressFromMultiPubKeys(pubKeys, m)
+	if err != nil {
+		fmt.Println("Create multi-signature address error:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("%d-of-%d multi-signature address\n", m, len(pubKeys))
+	fmt.Println("hex address: ", common.ToHexString(address[:]))
+	fmt.Println("base58 address:      ", address.ToBase58())
+	return nil
+}
+
 func NewCommand() *cli.Command {
 	return &cli.Command{
 		Name:        "wallet",
@@ -159,7 +179,8 @@ func NewCommand() *cli.Command {
 				SHA384withECDSA, SHA512withECDSA,
 				SHA3-224withECDSA, SHA3-256withECDSA,
 				SHA3-384withECDSA, SHA3-512withECDSA,
-				RIPEMD160withECDSA, SM3withSM2, SHA512withEdDSA`,
+				RIPEMD160withECDSA, SM3withSM2, SHA512withEdDSA,
+				or key type: ecdsa, secp256k1, sm2, ed25519`,
 			},
 			cli.StringFlag{
 				Name:  "name, n",
@@ -172,6 +193,23 @@ func NewCommand() *cli.Command {
 			},
 		},
 		Action: walletAction,
+		Subcommands: []cli.Command{
+			{
+				Name:  "multisig",
+				Usage: "create m-of-n multi-signature address",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "pubkeys",
+						Usage: "comma separated hex public keys of co-signers",
+					},
+					cli.IntFlag{
+						Name:  "m",
+						Usage: "minimum number of signatures required",
+					},
+				},
+				Action: multisigAction,
+			},
+		},
 		OnUsageError: func(c *cli.Context, err error, isSubcommand bool) error {
 			cliCommon.PrintError(c, err, "wallet")
 			return cli.NewExitError("", 1)
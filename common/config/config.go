@@ -34,6 +34,17 @@ const (
 	SOLO_MIN_NODE_NUM        = 1 //min node number of solo consensus
 )
 
+const (
+	STATE_RETENTION_ARCHIVE = "archive" //keep historical state of all blocks
+	STATE_RETENTION_PRUNED  = "pruned"  //keep historical state of the latest StateRetentionBlocks blocks only
+)
+
+const (
+	STORE_BACKEND_LEVELDB = "leveldb" //persist ledger in leveldb, the default backend
+	STORE_BACKEND_BOLTDB  = "boltdb"  //persist ledger in boltdb. Data is not migrated when switching backend
+	STORE_BACKEND_MEMORY  = "memory"  //keep ledger in memory only, data is lost after node stopped. Usually using in test
+)
+
 var Version string
 
 type Configuration struct {
@@ -65,6 +76,10 @@ type Configuration struct {
 	MaxHdrSyncReqs    int              `json:"MaxConcurrentSyncHeaderReqs"`
 	ConsensusType     string           `json:"ConsensusType"`
 	SystemFee         map[string]int64 `json:"SystemFee"`
+	StateRetention    string           `json:"StateRetention"`
+	StateKeepBlocks   uint32           `json:"StateRetentionBlocks"`
+	StoreBackend      string           `json:"StoreBackend"`
+	AddressIndex      bool             `json:"EnableAddressIndex"`
 }
 
 type ConfigFile struct {
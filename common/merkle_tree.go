@@ -122,3 +122,61 @@ func ComputeMerkleRoot(hashes []Uint256) (Uint256, error) {
 	tree, _ := newMerkleTree(hashes)
 	return tree.Root.Hash, nil
 }
+
+//ComputeMerkleProof return the audit path of hashes[index] in the merkle tree of hashes, from the leaf level up to the root
+func ComputeMerkleProof(hashes []Uint256, index uint32) ([]Uint256, error) {
+	if index >= uint32(len(hashes)) {
+		return nil, errors.New("ComputeMerkleProof index out of range.")
+	}
+	var proof []Uint256
+	level := hashes
+	for len(level) > 1 {
+		sibling := index ^ 1
+		if sibling >= uint32(len(level)) {
+			sibling = index
+		}
+		proof = append(proof, level[sibling])
+
+		next := make([]Uint256, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			right := level[i]
+			if i+1 < len(level) {
+				right = level[i+1]
+			}
+			next = append(next, doubleSha256([]Uint256{level[i], right}))
+		}
+		level = next
+		index /= 2
+	}
+	return proof, nil
+}
+
+//ComputeMerkleRootWithProof return the merkle root of count hashes, computed from the leaf at index and its audit path
+func ComputeMerkleRootWithProof(leaf Uint256, index, count uint32, proof []Uint256) (Uint256, error) {
+	if index >= count {
+		return Uint256{}, errors.New("ComputeMerkleRootWithProof index out of range.")
+	}
+	hash := leaf
+	pos := 0
+	for size := count; size > 1; size = (size + 1) / 2 {
+		if pos >= len(proof) {
+			return Uint256{}, errors.New("ComputeMerkleRootWithProof proof too short.")
+		}
+		sibling := proof[pos]
+		pos++
+		if index%2 == 1 {
+			hash = doubleSha256([]Uint256{sibling, hash})
+		} else {
+			//the last node of a level with odd size is paired with itself
+			if index == size-1 && sibling != hash {
+				return Uint256{}, errors.New("ComputeMerkleRootWithProof invalid sibling of last node.")
+			}
+			hash = doubleSha256([]Uint256{hash, sibling})
+		}
+		index /= 2
+	}
+	if pos != len(proof) {
+		return Uint256{}, errors.New("ComputeMerkleRootWithProof proof too long.")
+	}
+	return hash, nil
+}
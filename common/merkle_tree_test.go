@@ -40,3 +40,26 @@ func TestHash(t *testing.T) {
 	assert.Nil(t, err)
 
 }
+
+func TestMerkleProof(t *testing.T) {
+	var data []Uint256
+	for i := 0; i < 7; i++ {
+		data = append(data, Uint256(sha256.Sum256([]byte{byte(i)})))
+	}
+	for count := 1; count <= len(data); count++ {
+		root, err := ComputeMerkleRoot(data[:count])
+		assert.Nil(t, err)
+		for index := 0; index < count; index++ {
+			proof, err := ComputeMerkleProof(data[:count], uint32(index))
+			assert.Nil(t, err)
+			hash, err := ComputeMerkleRootWithProof(data[index], uint32(index), uint32(count), proof)
+			assert.Nil(t, err)
+			assert.Equal(t, root, hash)
+
+			hash, err = ComputeMerkleRootWithProof(data[(index+1)%len(data)], uint32(index), uint32(count), proof)
+			assert.True(t, err != nil || hash != root)
+		}
+	}
+	_, err := ComputeMerkleProof(data, uint32(len(data)))
+	assert.NotNil(t, err)
+}
@@ -109,11 +109,16 @@ func (ctx *ConsensusContext) MakeHeader() *types.Block {
 			return nil
 		}
 		blockRoot := ledger.DefLedger.GetBlockRootWithNewTxRoot(txRoot)
+		stateRoot, err := ledger.DefLedger.GetCurrentStateRoot()
+		if err != nil {
+			return nil
+		}
 		header := &types.Header{
 			Version:          ContextVersion,
 			PrevBlockHash:    ctx.PrevHash,
 			TransactionsRoot: txRoot,
 			BlockRoot:        blockRoot,
+			StateRoot:        stateRoot,
 			Timestamp:        ctx.Timestamp,
 			Height:           ctx.Height,
 			ConsensusData:    ctx.Nonce,
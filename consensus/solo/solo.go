@@ -215,11 +215,16 @@ func (self *SoloService) makeBlock() (*types.Block, error) {
 	}
 
 	blockRoot := ledger.DefLedger.GetBlockRootWithNewTxRoot(txRoot)
+	stateRoot, err := ledger.DefLedger.GetCurrentStateRoot()
+	if err != nil {
+		return nil, fmt.Errorf("GetCurrentStateRoot error:%s", err)
+	}
 	header := &types.Header{
 		Version:          ContextVersion,
 		PrevBlockHash:    prevHash,
 		TransactionsRoot: txRoot,
 		BlockRoot:        blockRoot,
+		StateRoot:        stateRoot,
 		Timestamp:        uint32(time.Now().Unix()),
 		Height:           height + 1,
 		ConsensusData:    nonce,
@@ -85,8 +85,16 @@ func (self *LedgerActor) Receive(ctx actor.Context) {
 		self.handleGetContractStateReq(ctx, msg)
 	case *GetMerkleProofReq:
 		self.handleGetMerkleProofReq(ctx, msg)
+	case *GetConsistencyProofReq:
+		self.handleGetConsistencyProofReq(ctx, msg)
+	case *GetTransactionProofReq:
+		self.handleGetTransactionProofReq(ctx, msg)
 	case *GetStorageItemReq:
 		self.handleGetStorageItemReq(ctx, msg)
+	case *GetStorageItemAtHeightReq:
+		self.handleGetStorageItemAtHeightReq(ctx, msg)
+	case *GetStorageProofReq:
+		self.handleGetStorageProofReq(ctx, msg)
 	case *GetBookkeeperStateReq:
 		self.handleGetBookkeeperStateReq(ctx, msg)
 	case *GetCurrentStateRootReq:
@@ -103,6 +111,12 @@ func (self *LedgerActor) Receive(ctx actor.Context) {
 		self.handleGetEventNotifyByTx(ctx, msg)
 	case *GetEventNotifyByBlockReq:
 		self.handleGetEventNotifyByBlock(ctx, msg)
+	case *GetEventNotifyByContractReq:
+		self.handleGetEventNotifyByContract(ctx, msg)
+	case *GetTransactionsByAddressReq:
+		self.handleGetTransactionsByAddress(ctx, msg)
+	case *GetContractHistoryReq:
+		self.handleGetContractHistory(ctx, msg)
 	default:
 		log.Warnf("LedgerActor cannot deal with type: %v %v", msg, reflect.TypeOf(msg))
 	}
@@ -265,6 +279,24 @@ func (self *LedgerActor) handleGetMerkleProofReq(ctx actor.Context, req *GetMerk
 	ctx.Sender().Request(resp, ctx.Self())
 }
 
+func (self *LedgerActor) handleGetConsistencyProofReq(ctx actor.Context, req *GetConsistencyProofReq) {
+	proof, err := ledger.DefLedger.GetConsistencyProof(req.OldHeight, req.NewHeight)
+	resp := &GetConsistencyProofRsp{
+		Proof: proof,
+		Error: err,
+	}
+	ctx.Sender().Request(resp, ctx.Self())
+}
+
+func (self *LedgerActor) handleGetTransactionProofReq(ctx actor.Context, req *GetTransactionProofReq) {
+	proof, err := ledger.DefLedger.GetTransactionProof(req.TxHash, req.RootHeight)
+	resp := &GetTransactionProofRsp{
+		Proof: proof,
+		Error: err,
+	}
+	ctx.Sender().Request(resp, ctx.Self())
+}
+
 func (self *LedgerActor) handleGetBlockRootWithNewTxRootReq(ctx actor.Context, req *GetBlockRootWithNewTxRootReq) {
 	newRoot := ledger.DefLedger.GetBlockRootWithNewTxRoot(req.TxRoot)
 	resp := &GetBlockRootWithNewTxRootRsp{
@@ -311,6 +343,24 @@ func (self *LedgerActor) handleGetStorageItemReq(ctx actor.Context, req *GetStor
 	ctx.Sender().Request(resp, ctx.Self())
 }
 
+func (self *LedgerActor) handleGetStorageItemAtHeightReq(ctx actor.Context, req *GetStorageItemAtHeightReq) {
+	value, err := ledger.DefLedger.GetStorageItemAtHeight(req.CodeHash, req.Key, req.Height)
+	resp := &GetStorageItemAtHeightRsp{
+		Value: value,
+		Error: err,
+	}
+	ctx.Sender().Request(resp, ctx.Self())
+}
+
+func (self *LedgerActor) handleGetStorageProofReq(ctx actor.Context, req *GetStorageProofReq) {
+	storageProof, err := ledger.DefLedger.GetStorageProof(req.CodeHash, req.Key, req.Height)
+	resp := &GetStorageProofRsp{
+		StorageProof: storageProof,
+		Error:        err,
+	}
+	ctx.Sender().Request(resp, ctx.Self())
+}
+
 func (self *LedgerActor) handleIsContainTransactionReq(ctx actor.Context, req *IsContainTransactionReq) {
 	isCon, err := ledger.DefLedger.IsContainTransaction(req.TxHash)
 	resp := &IsContainTransactionRsp{
@@ -346,3 +396,30 @@ func (self *LedgerActor) handleGetEventNotifyByBlock(ctx actor.Context, req *Get
 	}
 	ctx.Sender().Request(resp, ctx.Self())
 }
+
+func (self *LedgerActor) handleGetEventNotifyByContract(ctx actor.Context, req *GetEventNotifyByContractReq) {
+	result, err := ledger.DefLedger.GetEventNotifyByContract(req.Contract, req.StartHeight, req.EndHeight, req.EventName, req.Offset, req.Limit)
+	resp := &GetEventNotifyByContractRsp{
+		Notifies: result,
+		Error:    err,
+	}
+	ctx.Sender().Request(resp, ctx.Self())
+}
+
+func (self *LedgerActor) handleGetTransactionsByAddress(ctx actor.Context, req *GetTransactionsByAddressReq) {
+	result, err := ledger.DefLedger.GetTransactionsByAddress(req.Address, req.FromHeight, req.Limit)
+	resp := &GetTransactionsByAddressRsp{
+		Txs:   result,
+		Error: err,
+	}
+	ctx.Sender().Request(resp, ctx.Self())
+}
+
+func (self *LedgerActor) handleGetContractHistory(ctx actor.Context, req *GetContractHistoryReq) {
+	result, err := ledger.DefLedger.GetContractHistory(req.Contract)
+	resp := &GetContractHistoryRsp{
+		History: result,
+		Error:   err,
+	}
+	ctx.Sender().Request(resp, ctx.Self())
+}
@@ -21,7 +21,9 @@ package actor
 import (
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/proof"
 	"github.com/ontio/ontology/core/states"
+	scom "github.com/ontio/ontology/core/store/common"
 	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/smartcontract/event"
 )
@@ -196,6 +198,28 @@ type GetStorageItemRsp struct {
 	Error error
 }
 
+type GetStorageItemAtHeightReq struct {
+	CodeHash common.Address
+	Key      []byte
+	Height   uint32
+}
+
+type GetStorageItemAtHeightRsp struct {
+	Value []byte
+	Error error
+}
+
+type GetStorageProofReq struct {
+	CodeHash common.Address
+	Key      []byte
+	Height   uint32
+}
+
+type GetStorageProofRsp struct {
+	StorageProof *states.StorageProof
+	Error        error
+}
+
 type GetContractStateReq struct {
 	ContractHash common.Address
 }
@@ -210,6 +234,26 @@ type GetMerkleProofRsp struct {
 	Error error
 }
 
+type GetConsistencyProofReq struct {
+	OldHeight uint32
+	NewHeight uint32
+}
+
+type GetConsistencyProofRsp struct {
+	Proof []common.Uint256
+	Error error
+}
+
+type GetTransactionProofReq struct {
+	TxHash     common.Uint256
+	RootHeight uint32
+}
+
+type GetTransactionProofRsp struct {
+	Proof *proof.TxProof
+	Error error
+}
+
 type GetContractStateRsp struct {
 	ContractState *payload.DeployCode
 	Error         error
@@ -241,3 +285,37 @@ type GetEventNotifyByBlockRsp struct {
 	TxHashes []common.Uint256
 	Error    error
 }
+
+type GetEventNotifyByContractReq struct {
+	Contract    common.Address
+	StartHeight uint32
+	EndHeight   uint32
+	EventName   string
+	Offset      uint32
+	Limit       uint32
+}
+
+type GetEventNotifyByContractRsp struct {
+	Notifies []*event.ContractNotifyEventInfo
+	Error    error
+}
+
+type GetTransactionsByAddressReq struct {
+	Address    common.Address
+	FromHeight uint32
+	Limit      uint32
+}
+
+type GetTransactionsByAddressRsp struct {
+	Txs   []*scom.AddressTransaction
+	Error error
+}
+
+type GetContractHistoryReq struct {
+	Contract common.Address
+}
+
+type GetContractHistoryRsp struct {
+	History []*scom.ContractVersionItem
+	Error   error
+}
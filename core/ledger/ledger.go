@@ -26,8 +26,10 @@ import (
 	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/core/genesis"
 	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/proof"
 	"github.com/ontio/ontology/core/states"
 	"github.com/ontio/ontology/core/store"
+	scom "github.com/ontio/ontology/core/store/common"
 	"github.com/ontio/ontology/core/store/ledgerstore"
 	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/smartcontract/event"
@@ -135,7 +137,7 @@ func (self *Ledger) IsContainBlock(blockHash common.Uint256) (bool, error) {
 }
 
 func (self *Ledger) GetCurrentStateRoot() (common.Uint256, error) {
-	return common.Uint256{}, nil
+	return self.ldgStore.GetCurrentStateRoot()
 }
 
 func (self *Ledger) GetBookkeeperState() (*states.BookkeeperState, error) {
@@ -157,14 +159,53 @@ func (self *Ledger) GetStorageItem(codeHash common.Address, key []byte) ([]byte,
 	return storageItem.Value, nil
 }
 
+func (self *Ledger) GetStorageItemAtHeight(codeHash common.Address, key []byte, height uint32) ([]byte, error) {
+	storageKey := &states.StorageKey{
+		CodeHash: codeHash,
+		Key:      key,
+	}
+	storageItem, err := self.ldgStore.GetStorageItemAtHeight(storageKey, height)
+	if err != nil {
+		return nil, fmt.Errorf("GetStorageItemAtHeight error %s", err)
+	}
+	if storageItem == nil {
+		return nil, nil
+	}
+	return storageItem.Value, nil
+}
+
+func (self *Ledger) GetStorageProof(codeHash common.Address, key []byte, height uint32) (*states.StorageProof, error) {
+	storageKey := &states.StorageKey{
+		CodeHash: codeHash,
+		Key:      key,
+	}
+	return self.ldgStore.GetStorageProof(storageKey, height)
+}
+
+func (self *Ledger) GetStateHistoryRange() (uint32, uint32, error) {
+	return self.ldgStore.GetStateHistoryRange()
+}
+
 func (self *Ledger) GetContractState(contractHash common.Address) (*payload.DeployCode, error) {
 	return self.ldgStore.GetContractState(contractHash)
 }
 
+func (self *Ledger) GetContractHistory(contractHash common.Address) ([]*scom.ContractVersionItem, error) {
+	return self.ldgStore.GetContractHistory(contractHash)
+}
+
 func (self *Ledger) GetMerkleProof(proofHeight, rootHeight uint32) ([]common.Uint256, error) {
 	return self.ldgStore.GetMerkleProof(proofHeight, rootHeight)
 }
 
+func (self *Ledger) GetConsistencyProof(oldHeight, newHeight uint32) ([]common.Uint256, error) {
+	return self.ldgStore.GetConsistencyProof(oldHeight, newHeight)
+}
+
+func (self *Ledger) GetTransactionProof(txHash common.Uint256, rootHeight uint32) (*proof.TxProof, error) {
+	return self.ldgStore.GetTransactionProof(txHash, rootHeight)
+}
+
 func (self *Ledger) PreExecuteContract(tx *types.Transaction) (interface{}, error) {
 	return self.ldgStore.PreExecuteContract(tx)
 }
@@ -176,3 +217,11 @@ func (self *Ledger) GetEventNotifyByTx(tx common.Uint256) ([]*event.NotifyEventI
 func (self *Ledger) GetEventNotifyByBlock(height uint32) ([]common.Uint256, error) {
 	return self.ldgStore.GetEventNotifyByBlock(height)
 }
+
+func (self *Ledger) GetEventNotifyByContract(contract common.Address, startHeight, endHeight uint32, eventName string, offset, limit uint32) ([]*event.ContractNotifyEventInfo, error) {
+	return self.ldgStore.GetEventNotifyByContract(contract, startHeight, endHeight, eventName, offset, limit)
+}
+
+func (self *Ledger) GetTransactionsByAddress(address common.Address, fromHeight uint32, limit uint32) ([]*scom.AddressTransaction, error) {
+	return self.ldgStore.GetTransactionsByAddress(address, fromHeight, limit)
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package payload
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/common/serialization"
+)
+
+const (
+	MaxDataFileFieldLength = 1024 // The max length of IPFSPath, Filename and Note
+)
+
+// DataFile is an implementation of transaction payload for anchoring the IPFS file issued by Issuer
+type DataFile struct {
+	IPFSPath string
+	Filename string
+	Note     string
+	Issuer   keypair.PublicKey
+}
+
+// Check checks whether the data file is well formed
+func (self *DataFile) Check() error {
+	if len(self.IPFSPath) == 0 || len(self.IPFSPath) > MaxDataFileFieldLength {
+		return fmt.Errorf("invalid IPFS path length %d", len(self.IPFSPath))
+	}
+	if len(self.Filename) == 0 || len(self.Filename) > MaxDataFileFieldLength {
+		return fmt.Errorf("invalid file name length %d", len(self.Filename))
+	}
+	if len(self.Note) > MaxDataFileFieldLength {
+		return fmt.Errorf("invalid note length %d", len(self.Note))
+	}
+	if self.Issuer == nil {
+		return errors.New("missing issuer")
+	}
+	return nil
+}
+
+// Serialize serialize DataFile into io.Writer
+func (self *DataFile) Serialize(w io.Writer) error {
+	if err := serialization.WriteString(w, self.IPFSPath); err != nil {
+		return fmt.Errorf("[DataFile], serializing IPFSPath failed: %s", err)
+	}
+	if err := serialization.WriteString(w, self.Filename); err != nil {
+		return fmt.Errorf("[DataFile], serializing Filename failed: %s", err)
+	}
+	if err := serialization.WriteString(w, self.Note); err != nil {
+		return fmt.Errorf("[DataFile], serializing Note failed: %s", err)
+	}
+	if err := serialization.WriteVarBytes(w, serializePublicKey(self.Issuer)); err != nil {
+		return fmt.Errorf("[DataFile], serializing Issuer failed: %s", err)
+	}
+	return nil
+}
+
+// Deserialize deserialize DataFile from io.Reader
+func (self *DataFile) Deserialize(r io.Reader) error {
+	var err error
+	self.IPFSPath, err = serialization.ReadString(r)
+	if err != nil {
+		return fmt.Errorf("[DataFile], deserializing IPFSPath failed: %s", err)
+	}
+	self.Filename, err = serialization.ReadString(r)
+	if err != nil {
+		return fmt.Errorf("[DataFile], deserializing Filename failed: %s", err)
+	}
+	self.Note, err = serialization.ReadString(r)
+	if err != nil {
+		return fmt.Errorf("[DataFile], deserializing Note failed: %s", err)
+	}
+	self.Issuer, err = deserializePublicKey(r)
+	if err != nil {
+		return fmt.Errorf("[DataFile], deserializing Issuer failed: %s", err)
+	}
+	return nil
+}
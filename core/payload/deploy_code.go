@@ -20,13 +20,20 @@ package payload
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 
 	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/smartcontract/abi"
+	"github.com/ontio/ontology/smartcontract/permission"
 	stypes "github.com/ontio/ontology/smartcontract/types"
 )
 
+// DEPLOY_CODE_VERSION prefixes deploy code with ABI or permissions. Legacy deploy code
+// has neither of them and starts with the vm type of code, which is never this value.
+const DEPLOY_CODE_VERSION = byte(1)
+
 // DeployCode is an implementation of transaction payload for deploy smartcontract
 type DeployCode struct {
 	Code        stypes.VmCode
@@ -36,11 +43,22 @@ type DeployCode struct {
 	Author      string
 	Email       string
 	Description string
+	ABI         *abi.ABI                // Optional, serialized as empty bytes when nil
+	Permissions *permission.Permissions // Optional, contract calls are unrestricted when nil
 }
 
 func (dc *DeployCode) Serialize(w io.Writer) error {
 	var err error
 
+	// Deploy code without ABI and permissions keeps the legacy format, so its hash is unchanged
+	versioned := dc.ABI != nil || dc.Permissions != nil
+	if versioned {
+		err = serialization.WriteByte(w, DEPLOY_CODE_VERSION)
+		if err != nil {
+			return fmt.Errorf("DeployCode version Serialize failed: %s", err)
+		}
+	}
+
 	err = dc.Code.Serialize(w)
 	if err != nil {
 		return fmt.Errorf("DeployCode Code Serialize failed: %s", err)
@@ -76,11 +94,49 @@ func (dc *DeployCode) Serialize(w io.Writer) error {
 		return fmt.Errorf("DeployCode Description Serialize failed: %s", err)
 	}
 
+	if !versioned {
+		return nil
+	}
+
+	var abiData []byte
+	if dc.ABI != nil {
+		abiData, err = json.Marshal(dc.ABI)
+		if err != nil {
+			return fmt.Errorf("DeployCode ABI Serialize failed: %s", err)
+		}
+	}
+	err = serialization.WriteVarBytes(w, abiData)
+	if err != nil {
+		return fmt.Errorf("DeployCode ABI Serialize failed: %s", err)
+	}
+
+	var permData []byte
+	if dc.Permissions != nil {
+		permData, err = json.Marshal(dc.Permissions)
+		if err != nil {
+			return fmt.Errorf("DeployCode Permissions Serialize failed: %s", err)
+		}
+	}
+	err = serialization.WriteVarBytes(w, permData)
+	if err != nil {
+		return fmt.Errorf("DeployCode Permissions Serialize failed: %s", err)
+	}
+
 	return nil
 }
 
 func (dc *DeployCode) Deserialize(r io.Reader) error {
-	err := dc.Code.Deserialize(r)
+	version, err := serialization.ReadByte(r)
+	if err != nil {
+		return fmt.Errorf("DeployCode version Deserialize failed: %s", err)
+	}
+	versioned := version == DEPLOY_CODE_VERSION
+	if !versioned {
+		// The byte read is the vm type of legacy deploy code
+		r = io.MultiReader(bytes.NewReader([]byte{version}), r)
+	}
+
+	err = dc.Code.Deserialize(r)
 	if err != nil {
 		return fmt.Errorf("DeployCode Code Deserialize failed: %s", err)
 	}
@@ -115,6 +171,34 @@ func (dc *DeployCode) Deserialize(r io.Reader) error {
 		return fmt.Errorf("DeployCode Description Deserialize failed: %s", err)
 	}
 
+	dc.ABI = nil
+	dc.Permissions = nil
+	if !versioned {
+		return nil
+	}
+
+	abiData, err := serialization.ReadVarBytes(r)
+	if err != nil {
+		return fmt.Errorf("DeployCode ABI Deserialize failed: %s", err)
+	}
+	if len(abiData) > 0 {
+		dc.ABI, err = abi.NewABI(abiData)
+		if err != nil {
+			return fmt.Errorf("DeployCode ABI Deserialize failed: %s", err)
+		}
+	}
+
+	permData, err := serialization.ReadVarBytes(r)
+	if err != nil {
+		return fmt.Errorf("DeployCode Permissions Deserialize failed: %s", err)
+	}
+	if len(permData) > 0 {
+		dc.Permissions, err = permission.NewPermissions(permData)
+		if err != nil {
+			return fmt.Errorf("DeployCode Permissions Deserialize failed: %s", err)
+		}
+	}
+
 	return nil
 }
 
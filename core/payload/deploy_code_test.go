@@ -0,0 +1,125 @@
+package payload
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/smartcontract/abi"
+	"github.com/ontio/ontology/smartcontract/permission"
+	"github.com/ontio/ontology/smartcontract/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeployCode_Serialize(t *testing.T) {
+	code := DeployCode{
+		Code: types.VmCode{
+			VmType: types.NEOVM,
+			Code:   []byte{1, 2, 3},
+		},
+		NeedStorage: true,
+		Name:        "test",
+		Version:     "1.0",
+	}
+
+	buf := bytes.NewBuffer(nil)
+	code.Serialize(buf)
+	var code2 DeployCode
+	err := code2.Deserialize(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, code, code2)
+
+	code.ABI = &abi.ABI{
+		Functions: []abi.Method{{Name: "name", ReturnType: abi.String}},
+		Events: []abi.Event{{Name: "transfer", Parameters: []abi.Parameter{
+			{Name: "to", Type: abi.Hash160},
+			{Name: "amount", Type: abi.Integer},
+		}}},
+	}
+	buf = bytes.NewBuffer(nil)
+	code.Serialize(buf)
+	bs := buf.Bytes()
+	var code3 DeployCode
+	err = code3.Deserialize(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, code, code3)
+
+	buf = bytes.NewBuffer(bs[:len(bs)-2])
+	err = code3.Deserialize(buf)
+	assert.NotNil(t, err)
+
+	code.Permissions = &permission.Permissions{
+		Calls:   []permission.Permission{{Contract: permission.Wildcard, Methods: []string{"balanceOf"}}},
+		Callers: []string{permission.Wildcard},
+	}
+	buf = bytes.NewBuffer(nil)
+	code.Serialize(buf)
+	var code4 DeployCode
+	err = code4.Deserialize(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, code, code4)
+}
+
+func TestDeployCode_DeserializeLegacy(t *testing.T) {
+	// Deploy code in transactions and storage before ABI and permissions are introduced
+	buf := bytes.NewBuffer(nil)
+	buf.WriteByte(byte(types.NEOVM))
+	serialization.WriteVarBytes(buf, []byte{1, 2, 3})
+	serialization.WriteBool(buf, true)
+	serialization.WriteString(buf, "test")
+	serialization.WriteString(buf, "1.0")
+	serialization.WriteString(buf, "author")
+	serialization.WriteString(buf, "email")
+	serialization.WriteString(buf, "desc")
+	legacy := buf.Bytes()
+	// Following data of transaction must not be consumed
+	buf.WriteByte(0xab)
+
+	var code DeployCode
+	err := code.Deserialize(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, DeployCode{
+		Code: types.VmCode{
+			VmType: types.NEOVM,
+			Code:   []byte{1, 2, 3},
+		},
+		NeedStorage: true,
+		Name:        "test",
+		Version:     "1.0",
+		Author:      "author",
+		Email:       "email",
+		Description: "desc",
+	}, code)
+	assert.Equal(t, []byte{0xab}, buf.Bytes())
+
+	// Deploy code without ABI and permissions is serialized in legacy format
+	assert.Equal(t, legacy, code.ToArray())
+}
+
+func TestDeployCode_SerializePermissions(t *testing.T) {
+	code := DeployCode{
+		Code: types.VmCode{
+			VmType: types.WASMVM,
+			Code:   []byte{1, 2, 3},
+		},
+		Name: "test",
+		Permissions: &permission.Permissions{
+			Callers: []string{},
+		},
+	}
+	bs := code.ToArray()
+	assert.Equal(t, DEPLOY_CODE_VERSION, bs[0])
+	var code2 DeployCode
+	err := code2.Deserialize(bytes.NewBuffer(bs))
+	assert.Nil(t, err)
+	assert.Equal(t, code, code2)
+
+	// Permissions are dropped with the version byte, the rest is legacy deploy code without permissions
+	code.Permissions = nil
+	legacy := code.ToArray()
+	assert.Equal(t, legacy, bs[1:len(legacy)+1])
+	var code3 DeployCode
+	err = code3.Deserialize(bytes.NewBuffer(legacy))
+	assert.Nil(t, err)
+	assert.Nil(t, code3.Permissions)
+}
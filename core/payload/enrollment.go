@@ -0,0 +1,79 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package payload
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/common/serialization"
+)
+
+// Enrollment is an implementation of transaction payload for the candidacy of bookkeeper PublicKey
+type Enrollment struct {
+	PublicKey keypair.PublicKey
+}
+
+// Check checks whether the enrollment is well formed
+func (self *Enrollment) Check() error {
+	if self.PublicKey == nil {
+		return errors.New("missing enrolled public key")
+	}
+	return nil
+}
+
+// Serialize serialize Enrollment into io.Writer
+func (self *Enrollment) Serialize(w io.Writer) error {
+	if err := serialization.WriteVarBytes(w, serializePublicKey(self.PublicKey)); err != nil {
+		return fmt.Errorf("[Enrollment], serializing PublicKey failed: %s", err)
+	}
+	return nil
+}
+
+// Deserialize deserialize Enrollment from io.Reader
+func (self *Enrollment) Deserialize(r io.Reader) error {
+	var err error
+	self.PublicKey, err = deserializePublicKey(r)
+	if err != nil {
+		return fmt.Errorf("[Enrollment], deserializing PublicKey failed: %s", err)
+	}
+	return nil
+}
+
+// serializePublicKey returns the serialized public key, or empty bytes for nil key
+func serializePublicKey(key keypair.PublicKey) []byte {
+	if key == nil {
+		return nil
+	}
+	return keypair.SerializePublicKey(key)
+}
+
+// deserializePublicKey reads the public key written by serializePublicKey
+func deserializePublicKey(r io.Reader) (keypair.PublicKey, error) {
+	buf, err := serialization.ReadVarBytes(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(buf) == 0 {
+		return nil, nil
+	}
+	return keypair.DeserializePublicKey(buf)
+}
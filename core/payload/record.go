@@ -0,0 +1,73 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package payload
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ontio/ontology/common/serialization"
+)
+
+const (
+	MaxRecordTypeLength = 256       // The max length of record type
+	MaxRecordDataLength = 64 * 1024 // The max length of record data
+)
+
+// Record is an implementation of transaction payload for anchoring data on chain
+type Record struct {
+	RecordType string
+	RecordData []byte
+}
+
+// Check checks whether the record is well formed
+func (self *Record) Check() error {
+	if len(self.RecordType) == 0 || len(self.RecordType) > MaxRecordTypeLength {
+		return fmt.Errorf("invalid record type length %d", len(self.RecordType))
+	}
+	if len(self.RecordData) == 0 || len(self.RecordData) > MaxRecordDataLength {
+		return fmt.Errorf("invalid record data length %d", len(self.RecordData))
+	}
+	return nil
+}
+
+// Serialize serialize Record into io.Writer
+func (self *Record) Serialize(w io.Writer) error {
+	if err := serialization.WriteString(w, self.RecordType); err != nil {
+		return fmt.Errorf("[Record], serializing RecordType failed: %s", err)
+	}
+	if err := serialization.WriteVarBytes(w, self.RecordData); err != nil {
+		return fmt.Errorf("[Record], serializing RecordData failed: %s", err)
+	}
+	return nil
+}
+
+// Deserialize deserialize Record from io.Reader
+func (self *Record) Deserialize(r io.Reader) error {
+	var err error
+	self.RecordType, err = serialization.ReadString(r)
+	if err != nil {
+		return fmt.Errorf("[Record], deserializing RecordType failed: %s", err)
+	}
+	self.RecordData, err = serialization.ReadVarBytes(r)
+	if err != nil {
+		return fmt.Errorf("[Record], deserializing RecordData failed: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package payload
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecord_Serialize(t *testing.T) {
+	record := Record{
+		RecordType: "sha256",
+		RecordData: []byte{1, 2, 3},
+	}
+	assert.Nil(t, record.Check())
+
+	buf := bytes.NewBuffer(nil)
+	record.Serialize(buf)
+	bs := buf.Bytes()
+	var record2 Record
+	record2.Deserialize(buf)
+	assert.Equal(t, record, record2)
+
+	buf = bytes.NewBuffer(bs[:len(bs)-2])
+	err := record.Deserialize(buf)
+	assert.NotNil(t, err)
+
+	record.RecordData = make([]byte, MaxRecordDataLength+1)
+	assert.NotNil(t, record.Check())
+}
+
+func TestDataFile_Serialize(t *testing.T) {
+	_, pubKey, _ := keypair.GenerateKeyPair(keypair.PK_ECDSA, keypair.P256)
+	file := DataFile{
+		IPFSPath: "QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o",
+		Filename: "hello.txt",
+		Issuer:   pubKey,
+	}
+	assert.Nil(t, file.Check())
+
+	buf := bytes.NewBuffer(nil)
+	file.Serialize(buf)
+	var file2 DataFile
+	assert.Nil(t, file2.Deserialize(buf))
+	assert.Equal(t, keypair.SerializePublicKey(file.Issuer), keypair.SerializePublicKey(file2.Issuer))
+	file2.Issuer = file.Issuer
+	assert.Equal(t, file, file2)
+
+	file.Issuer = nil
+	assert.NotNil(t, file.Check())
+}
+
+func TestEnrollment_Serialize(t *testing.T) {
+	_, pubKey, _ := keypair.GenerateKeyPair(keypair.PK_ECDSA, keypair.P256)
+	enrollment := Enrollment{PublicKey: pubKey}
+	assert.Nil(t, enrollment.Check())
+
+	buf := bytes.NewBuffer(nil)
+	enrollment.Serialize(buf)
+	var enrollment2 Enrollment
+	assert.Nil(t, enrollment2.Deserialize(buf))
+	assert.Equal(t, keypair.SerializePublicKey(pubKey), keypair.SerializePublicKey(enrollment2.PublicKey))
+
+	enrollment.PublicKey = nil
+	assert.NotNil(t, enrollment.Check())
+	buf = bytes.NewBuffer(nil)
+	enrollment.Serialize(buf)
+	assert.Nil(t, enrollment2.Deserialize(buf))
+	assert.Nil(t, enrollment2.PublicKey)
+}
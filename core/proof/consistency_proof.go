@@ -0,0 +1,43 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package proof
+
+import (
+	"fmt"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/merkle"
+)
+
+//VerifyBlockRootConsistency check the block chain of the BlockRoot at newHeight is appended from the one of the BlockRoot at oldHeight,
+//so a client trusted the old BlockRoot can trust the new one with the consistency proof
+func VerifyBlockRootConsistency(oldHeight uint32, oldBlockRoot common.Uint256, newHeight uint32, newBlockRoot common.Uint256, proof []common.Uint256) error {
+	if oldHeight > newHeight {
+		return fmt.Errorf("old height %d is higher than new height %d", oldHeight, newHeight)
+	}
+	if oldHeight == newHeight && oldBlockRoot != newBlockRoot {
+		return fmt.Errorf("block root %x is inconsistent with %x at the same height", newBlockRoot, oldBlockRoot)
+	}
+	verifier := merkle.NewMerkleVerifier()
+	err := verifier.VerifyConsistency(oldHeight+1, newHeight+1, oldBlockRoot, newBlockRoot, proof)
+	if err != nil {
+		return fmt.Errorf("VerifyConsistency error %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package proof
+
+import (
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/merkle"
+)
+
+func TestVerifyBlockRootConsistency(t *testing.T) {
+	tree := merkle.NewTree(0, nil, merkle.NewMemHashStore())
+	blockRoots := make([]common.Uint256, 0)
+	for height := 0; height < 5; height++ {
+		txRoot := common.Uint256{byte(height + 1)}
+		blockRoots = append(blockRoots, tree.GetRootWithNewLeaf(txRoot))
+		tree.AppendHash(txRoot)
+	}
+	proof, err := tree.ConsistencyProof(2, 5)
+	if err != nil {
+		t.Errorf("ConsistencyProof error %s", err)
+		return
+	}
+	err = VerifyBlockRootConsistency(1, blockRoots[1], 4, blockRoots[4], proof)
+	if err != nil {
+		t.Errorf("VerifyBlockRootConsistency error %s", err)
+		return
+	}
+	err = VerifyBlockRootConsistency(1, blockRoots[2], 4, blockRoots[4], proof)
+	if err == nil {
+		t.Errorf("VerifyBlockRootConsistency should fail with wrong old block root")
+		return
+	}
+	err = VerifyBlockRootConsistency(4, blockRoots[4], 1, blockRoots[1], proof)
+	if err == nil {
+		t.Errorf("VerifyBlockRootConsistency should fail when old height is higher than new height")
+		return
+	}
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Package proof verify the proofs returned by node, with only a trusted block header
+package proof
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/merkle"
+)
+
+//TxProof prove a transaction is included in the block chain up to the block of RootHeight
+type TxProof struct {
+	Header     *types.Header    //Header of the block which includes the transaction
+	TxIndex    uint32           //Index of the transaction in block
+	TxCount    uint32           //Count of transactions in block
+	TxPath     []common.Uint256 //Audit path of the transaction in the TransactionsRoot of block
+	RootHeight uint32           //Height of the block whose BlockRoot the block position is proved in
+	BlockPath  []common.Uint256 //Audit path of the TransactionsRoot of block in the BlockRoot of block RootHeight
+}
+
+//Verify check the transaction of txHash is included in the block chain of trusted header, whose height should be RootHeight.
+//If the block of transaction is below the trusted header, only the TransactionsRoot and Height of Header are proved
+func (this *TxProof) Verify(txHash common.Uint256, trusted *types.Header) error {
+	if this.Header == nil {
+		return fmt.Errorf("header is empty")
+	}
+	if trusted.Height != this.RootHeight {
+		return fmt.Errorf("trusted header height %d is not root height %d", trusted.Height, this.RootHeight)
+	}
+	if this.Header.Height > this.RootHeight {
+		return fmt.Errorf("block height %d is higher than root height %d", this.Header.Height, this.RootHeight)
+	}
+	if this.Header.Height == this.RootHeight && this.Header.Hash() != trusted.Hash() {
+		return fmt.Errorf("header is inconsistent with trusted header")
+	}
+	txRoot, err := common.ComputeMerkleRootWithProof(txHash, this.TxIndex, this.TxCount, this.TxPath)
+	if err != nil {
+		return fmt.Errorf("ComputeMerkleRootWithProof error %s", err)
+	}
+	if txRoot != this.Header.TransactionsRoot {
+		return fmt.Errorf("transactions root %x is inconsistent with %x of header", txRoot, this.Header.TransactionsRoot)
+	}
+	verifier := merkle.NewMerkleVerifier()
+	err = verifier.VerifyLeafHashInclusion(this.Header.TransactionsRoot, this.Header.Height, this.BlockPath, trusted.BlockRoot, this.RootHeight+1)
+	if err != nil {
+		return fmt.Errorf("VerifyLeafHashInclusion error %s", err)
+	}
+	return nil
+}
+
+func (this *TxProof) Serialize(w io.Writer) error {
+	err := this.Header.Serialize(w)
+	if err != nil {
+		return err
+	}
+	err = serialization.WriteUint32(w, this.TxIndex)
+	if err != nil {
+		return err
+	}
+	err = serialization.WriteUint32(w, this.TxCount)
+	if err != nil {
+		return err
+	}
+	err = serializeHashes(w, this.TxPath)
+	if err != nil {
+		return err
+	}
+	err = serialization.WriteUint32(w, this.RootHeight)
+	if err != nil {
+		return err
+	}
+	return serializeHashes(w, this.BlockPath)
+}
+
+func (this *TxProof) Deserialize(r io.Reader) error {
+	this.Header = new(types.Header)
+	err := this.Header.Deserialize(r)
+	if err != nil {
+		return err
+	}
+	this.TxIndex, err = serialization.ReadUint32(r)
+	if err != nil {
+		return err
+	}
+	this.TxCount, err = serialization.ReadUint32(r)
+	if err != nil {
+		return err
+	}
+	this.TxPath, err = deserializeHashes(r)
+	if err != nil {
+		return err
+	}
+	this.RootHeight, err = serialization.ReadUint32(r)
+	if err != nil {
+		return err
+	}
+	this.BlockPath, err = deserializeHashes(r)
+	return err
+}
+
+func serializeHashes(w io.Writer, hashes []common.Uint256) error {
+	err := serialization.WriteVarUint(w, uint64(len(hashes)))
+	if err != nil {
+		return err
+	}
+	for _, hash := range hashes {
+		err = hash.Serialize(w)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deserializeHashes(r io.Reader) ([]common.Uint256, error) {
+	n, err := serialization.ReadVarUint(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]common.Uint256, 0)
+	for i := uint64(0); i < n; i++ {
+		var hash common.Uint256
+		err = hash.Deserialize(r)
+		if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, nil
+}
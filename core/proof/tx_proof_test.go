@@ -0,0 +1,110 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package proof
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/merkle"
+)
+
+func TestTxProof(t *testing.T) {
+	tree := merkle.NewTree(0, nil, merkle.NewMemHashStore())
+	headers := make([]*types.Header, 0)
+	txHashes := make([][]common.Uint256, 0)
+	for height := uint32(0); height < 6; height++ {
+		hashes := make([]common.Uint256, 0)
+		for i := uint32(0); i <= height; i++ {
+			hashes = append(hashes, common.Uint256{byte(height), byte(i)})
+		}
+		txRoot, err := common.ComputeMerkleRoot(hashes)
+		if err != nil {
+			t.Errorf("ComputeMerkleRoot error %s", err)
+			return
+		}
+		header := &types.Header{
+			Height:           height,
+			TransactionsRoot: txRoot,
+			BlockRoot:        tree.GetRootWithNewLeaf(txRoot),
+		}
+		tree.AppendHash(txRoot)
+		headers = append(headers, header)
+		txHashes = append(txHashes, hashes)
+	}
+
+	rootHeight := uint32(5)
+	height := uint32(3)
+	txIndex := uint32(2)
+	txPath, err := common.ComputeMerkleProof(txHashes[height], txIndex)
+	if err != nil {
+		t.Errorf("ComputeMerkleProof error %s", err)
+		return
+	}
+	blockPath, err := tree.InclusionProof(height, rootHeight+1)
+	if err != nil {
+		t.Errorf("InclusionProof error %s", err)
+		return
+	}
+	txProof := &TxProof{
+		Header:     headers[height],
+		TxIndex:    txIndex,
+		TxCount:    uint32(len(txHashes[height])),
+		TxPath:     txPath,
+		RootHeight: rootHeight,
+		BlockPath:  blockPath,
+	}
+	buf := bytes.NewBuffer(nil)
+	err = txProof.Serialize(buf)
+	if err != nil {
+		t.Errorf("TxProof Serialize error %s", err)
+		return
+	}
+	txProof = new(TxProof)
+	err = txProof.Deserialize(buf)
+	if err != nil {
+		t.Errorf("TxProof Deserialize error %s", err)
+		return
+	}
+
+	txHash := txHashes[height][txIndex]
+	err = txProof.Verify(txHash, headers[rootHeight])
+	if err != nil {
+		t.Errorf("TxProof Verify error %s", err)
+		return
+	}
+	err = txProof.Verify(txHashes[height][0], headers[rootHeight])
+	if err == nil {
+		t.Errorf("TxProof Verify should fail with other transaction")
+		return
+	}
+	err = txProof.Verify(txHash, headers[rootHeight-1])
+	if err == nil {
+		t.Errorf("TxProof Verify should fail with other trusted header")
+		return
+	}
+	txProof.Header.Height = height + 1
+	err = txProof.Verify(txHash, headers[rootHeight])
+	if err == nil {
+		t.Errorf("TxProof Verify should fail with wrong block height")
+		return
+	}
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package states
+
+import (
+	"io"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/serialization"
+)
+
+//ContractVersion record the migration of contract, stored with the key of contract address
+type ContractVersion struct {
+	StateBase
+	Version     string         //Version of the contract
+	Predecessor common.Address //Contract migrated to this contract, empty if none
+	Successor   common.Address //Contract this contract migrated to, empty if not migrated
+	Forward     bool           //Whether calls to this contract are forwarded to successor
+}
+
+func (this *ContractVersion) Serialize(w io.Writer) error {
+	this.StateBase.Serialize(w)
+	if err := serialization.WriteString(w, this.Version); err != nil {
+		return err
+	}
+	if err := this.Predecessor.Serialize(w); err != nil {
+		return err
+	}
+	if err := this.Successor.Serialize(w); err != nil {
+		return err
+	}
+	return serialization.WriteBool(w, this.Forward)
+}
+
+func (this *ContractVersion) Deserialize(r io.Reader) error {
+	err := this.StateBase.Deserialize(r)
+	if err != nil {
+		return err
+	}
+	this.Version, err = serialization.ReadString(r)
+	if err != nil {
+		return err
+	}
+	if err := this.Predecessor.Deserialize(r); err != nil {
+		return err
+	}
+	if err := this.Successor.Deserialize(r); err != nil {
+		return err
+	}
+	this.Forward, err = serialization.ReadBool(r)
+	return err
+}
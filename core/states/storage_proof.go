@@ -0,0 +1,100 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package states
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/merkle"
+)
+
+//StorageProof prove the storage value of key in the state root, or the key is absent if Value is nil
+type StorageProof struct {
+	StateRoot common.Uint256
+	Value     []byte
+	Proof     *merkle.SparseMerkleProof
+}
+
+//GetStorageTrieKey return the key of storage in state trie, storeKey is the contract address followed by storage key
+func GetStorageTrieKey(storeKey []byte) common.Uint256 {
+	return sha256.Sum256(storeKey)
+}
+
+//GetStorageTrieValue return the value hash of storage in state trie
+func GetStorageTrieValue(value []byte) common.Uint256 {
+	return sha256.Sum256(value)
+}
+
+//Verify check the proof of storage key in contract of codeHash
+func (this *StorageProof) Verify(codeHash common.Address, key []byte) error {
+	if this.Proof == nil {
+		return fmt.Errorf("proof is empty")
+	}
+	valueHash := merkle.EMPTY_HASH
+	if this.Value != nil {
+		valueHash = GetStorageTrieValue(this.Value)
+	}
+	trieKey := GetStorageTrieKey(append(codeHash[:], key...))
+	return merkle.VerifySparseMerkleProof(this.StateRoot, trieKey, valueHash, this.Proof)
+}
+
+func (this *StorageProof) Serialize(w io.Writer) error {
+	err := this.StateRoot.Serialize(w)
+	if err != nil {
+		return err
+	}
+	err = serialization.WriteBool(w, this.Value != nil)
+	if err != nil {
+		return err
+	}
+	if this.Value != nil {
+		err = serialization.WriteVarBytes(w, this.Value)
+		if err != nil {
+			return err
+		}
+	}
+	return this.Proof.Serialize(w)
+}
+
+func (this *StorageProof) Deserialize(r io.Reader) error {
+	err := this.StateRoot.Deserialize(r)
+	if err != nil {
+		return err
+	}
+	exist, err := serialization.ReadBool(r)
+	if err != nil {
+		return err
+	}
+	this.Value = nil
+	if exist {
+		this.Value, err = serialization.ReadVarBytes(r)
+		if err != nil {
+			return err
+		}
+		if this.Value == nil {
+			this.Value = []byte{}
+		}
+	}
+	this.Proof = new(merkle.SparseMerkleProof)
+	return this.Proof.Deserialize(r)
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package boltstore
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/ontio/ontology/core/store/common"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+const BOLT_OPEN_TIMEOUT = time.Second //Timeout of waiting for the file lock of bolt db
+
+var BoltBucket = []byte("ontology") //Bucket of all the key-value pairs in bolt db
+
+//BoltDB store
+type BoltStore struct {
+	db    *bolt.DB       //BoltDB instance
+	batch *leveldb.Batch //Operations of commit batch
+}
+
+//NewBoltStore return BoltStore instance
+func NewBoltStore(file string) (*BoltStore, error) {
+	err := os.MkdirAll(filepath.Dir(file), 0755)
+	if err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(file, 0600, &bolt.Options{Timeout: BOLT_OPEN_TIMEOUT})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(BoltBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltStore{
+		db: db,
+	}, nil
+}
+
+//Put a key-value pair to boltdb
+func (self *BoltStore) Put(key []byte, value []byte) error {
+	return self.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(BoltBucket).Put(key, value)
+	})
+}
+
+//Get the value of a key from boltdb, return leveldb.ErrNotFound if the key does not exist
+func (self *BoltStore) Get(key []byte) ([]byte, error) {
+	var value []byte
+	err := self.db.View(func(tx *bolt.Tx) error {
+		//Value of bolt is only valid in transaction, and empty value cannot be distinguished from missing key by Get
+		k, v := tx.Bucket(BoltBucket).Cursor().Seek(key)
+		if k == nil || !bytes.Equal(k, key) {
+			return leveldb.ErrNotFound
+		}
+		value = make([]byte, len(v))
+		copy(value, v)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+//Has return whether the key is exist in boltdb
+func (self *BoltStore) Has(key []byte) (bool, error) {
+	_, err := self.Get(key)
+	if err != nil {
+		if err == leveldb.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+//Delete the key in boltdb
+func (self *BoltStore) Delete(key []byte) error {
+	return self.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(BoltBucket).Delete(key)
+	})
+}
+
+//NewBatch start commit batch
+func (self *BoltStore) NewBatch() {
+	self.batch = new(leveldb.Batch)
+}
+
+//BatchPut put a key-value pair to batch
+func (self *BoltStore) BatchPut(key []byte, value []byte) {
+	self.batch.Put(key, value)
+}
+
+//BatchDelete delete a key to batch
+func (self *BoltStore) BatchDelete(key []byte) {
+	self.batch.Delete(key)
+}
+
+//BatchCommit commit batch to boltdb in one transaction
+func (self *BoltStore) BatchCommit() error {
+	err := self.commitBatch(self.batch)
+	if err != nil {
+		return err
+	}
+	self.batch = nil
+	return nil
+}
+
+//BatchData return the serialized data of commit batch
+func (self *BoltStore) BatchData() []byte {
+	return self.batch.Dump()
+}
+
+//CommitBatchData commit the batch data serialized by BatchData to boltdb
+func (self *BoltStore) CommitBatchData(data []byte) error {
+	batch := new(leveldb.Batch)
+	err := batch.Load(data)
+	if err != nil {
+		return err
+	}
+	return self.commitBatch(batch)
+}
+
+func (self *BoltStore) commitBatch(batch *leveldb.Batch) error {
+	return self.db.Update(func(tx *bolt.Tx) error {
+		replay := &boltBatchReplay{bucket: tx.Bucket(BoltBucket)}
+		err := batch.Replay(replay)
+		if err != nil {
+			return err
+		}
+		return replay.err
+	})
+}
+
+//Close boltdb
+func (self *BoltStore) Close() error {
+	return self.db.Close()
+}
+
+//NewIterator return a iterator of boltdb with the key perfix. Iterator holds a read transaction until released
+func (self *BoltStore) NewIterator(prefix []byte) common.StoreIterator {
+	tx, err := self.db.Begin(false)
+	if err != nil {
+		return &Iterator{}
+	}
+	return &Iterator{
+		tx:     tx,
+		cursor: tx.Bucket(BoltBucket).Cursor(),
+		prefix: prefix,
+	}
+}
+
+//boltBatchReplay apply the batch operation to bucket, and keep the first error
+type boltBatchReplay struct {
+	bucket *bolt.Bucket
+	err    error
+}
+
+func (this *boltBatchReplay) Put(key, value []byte) {
+	if this.err == nil {
+		this.err = this.bucket.Put(key, value)
+	}
+}
+
+func (this *boltBatchReplay) Delete(key []byte) {
+	if this.err == nil {
+		this.err = this.bucket.Delete(key)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package boltstore
+
+import (
+	"bytes"
+
+	"github.com/boltdb/bolt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const (
+	iterStart = iota //Before the first key, like a new iterator
+	iterValid        //At a key in range
+	iterEnd          //After the last key
+)
+
+//Iterator of boltdb in key prefix range. It follows the behavior of leveldb iterator
+type Iterator struct {
+	tx     *bolt.Tx
+	cursor *bolt.Cursor
+	prefix []byte
+	state  int
+	key    []byte
+	value  []byte
+}
+
+func (it *Iterator) Next() bool {
+	switch it.state {
+	case iterStart:
+		return it.First()
+	case iterEnd:
+		return false
+	}
+	key, value := it.cursor.Next()
+	return it.set(key, value, iterEnd)
+}
+
+func (it *Iterator) Prev() bool {
+	switch it.state {
+	case iterStart:
+		return false
+	case iterEnd:
+		return it.Last()
+	}
+	key, value := it.cursor.Prev()
+	return it.set(key, value, iterStart)
+}
+
+func (it *Iterator) First() bool {
+	if it.cursor == nil {
+		return false
+	}
+	key, value := it.cursor.Seek(it.prefix)
+	return it.set(key, value, iterEnd)
+}
+
+func (it *Iterator) Last() bool {
+	if it.cursor == nil {
+		return false
+	}
+	var key, value []byte
+	limit := util.BytesPrefix(it.prefix).Limit
+	if limit == nil {
+		key, value = it.cursor.Last()
+	} else if key, _ = it.cursor.Seek(limit); key == nil {
+		key, value = it.cursor.Last()
+	} else {
+		key, value = it.cursor.Prev()
+	}
+	return it.set(key, value, iterStart)
+}
+
+func (it *Iterator) Seek(key []byte) bool {
+	if it.cursor == nil {
+		return false
+	}
+	if bytes.Compare(key, it.prefix) < 0 {
+		key = it.prefix
+	}
+	k, v := it.cursor.Seek(key)
+	return it.set(k, v, iterEnd)
+}
+
+func (it *Iterator) Key() []byte {
+	return it.key
+}
+
+func (it *Iterator) Value() []byte {
+	return it.value
+}
+
+func (it *Iterator) Release() {
+	if it.tx != nil {
+		it.tx.Rollback()
+		it.tx = nil
+		it.cursor = nil
+	}
+}
+
+//set the position of iterator to key, or to state out of range if key not in prefix range
+func (it *Iterator) set(key, value []byte, outState int) bool {
+	if key == nil || !bytes.HasPrefix(key, it.prefix) {
+		it.state = outState
+		it.key = nil
+		it.value = nil
+		return false
+	}
+	it.state = iterValid
+	it.key = key
+	it.value = value
+	return true
+}
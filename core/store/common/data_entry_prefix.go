@@ -28,19 +28,27 @@ const (
 	DATA_TRANSACTION                 = 0x02
 
 	// Transaction
-	ST_BOOKKEEPER DataEntryPrefix = 0x03
-	ST_CONTRACT   DataEntryPrefix = 0x04
-	ST_STORAGE    DataEntryPrefix = 0x05
-	ST_VALIDATOR  DataEntryPrefix = 0x07
-	ST_VOTE       DataEntryPrefix = 0x08
+	ST_BOOKKEEPER       DataEntryPrefix = 0x03
+	ST_CONTRACT         DataEntryPrefix = 0x04
+	ST_STORAGE          DataEntryPrefix = 0x05
+	ST_CONTRACT_VERSION DataEntryPrefix = 0x06
+	ST_VALIDATOR        DataEntryPrefix = 0x07
+	ST_VOTE             DataEntryPrefix = 0x08
 
 	IX_HEADER_HASH_LIST DataEntryPrefix = 0x09
+	IX_STATE_HISTORY    DataEntryPrefix = 0x0a
+	IX_STATE_VERSION    DataEntryPrefix = 0x0b
+	IX_STATE_TRIE       DataEntryPrefix = 0x0c
+	IX_ADDRESS_TX       DataEntryPrefix = 0x0d
+	IX_ADDRESS_BLOCK    DataEntryPrefix = 0x0e
 
 	//SYSTEM
 	SYS_CURRENT_BLOCK      DataEntryPrefix = 0x10
 	SYS_VERSION            DataEntryPrefix = 0x11
 	SYS_CURRENT_STATE_ROOT DataEntryPrefix = 0x12
 	SYS_BLOCK_MERKLE_TREE  DataEntryPrefix = 0x13
+	SYS_STATE_HISTORY_FROM DataEntryPrefix = 0x15
 
-	EVENT_NOTIFY DataEntryPrefix = 0x14
+	EVENT_NOTIFY          DataEntryPrefix = 0x14
+	EVENT_NOTIFY_CONTRACT DataEntryPrefix = 0x16
 )
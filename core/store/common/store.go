@@ -44,6 +44,8 @@ type PersistStore interface {
 	BatchPut(key []byte, value []byte)
 	BatchDelete(key []byte)
 	BatchCommit() error
+	BatchData() []byte
+	CommitBatchData(data []byte) error
 	Close() error
 	NewIterator(prefix []byte) StoreIterator
 }
@@ -68,10 +70,23 @@ type MemoryCacheStore interface {
 type EventStore interface {
 	SaveEventNotifyByTx(txHash common.Uint256, notifies []*event.NotifyEventInfo) error
 	SaveEventNotifyByBlock(height uint32, txHashs []common.Uint256) error
+	SaveEventNotifyByContract(height uint32, txHash common.Uint256, notifies []*event.NotifyEventInfo) error
 	GetEventNotifyByTx(txHash common.Uint256) ([]*event.NotifyEventInfo, error)
 	CommitTo() error
 }
 
+//AddressTransaction is the transaction touched an address, indexed by the address index of event store
+type AddressTransaction struct {
+	Height uint32
+	TxHash common.Uint256
+}
+
+//ContractVersionItem is a contract in the migration history, with its version record
+type ContractVersionItem struct {
+	Contract common.Address
+	Version  *states.ContractVersion
+}
+
 type ItemState byte
 
 const (
@@ -0,0 +1,158 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/genesis"
+	scom "github.com/ontio/ontology/core/store/common"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/smartcontract/event"
+	"github.com/ontio/ontology/smartcontract/service/native"
+)
+
+//SetAddressIndex set whether to index transactions by the addresses they touched.
+//Only the blocks saved after the index is enabled are indexed
+func (this *EventStore) SetAddressIndex(enable bool) {
+	this.addressIndex = enable
+}
+
+//SaveAddressTransaction persist the index of transaction by the addresses it touched, including the signers,
+//the fee payers and the parties of ONT/ONG transfer notified by the execution of transaction
+func (this *EventStore) SaveAddressTransaction(height uint32, tx *types.Transaction, notifies []*event.NotifyEventInfo) error {
+	if !this.addressIndex {
+		return nil
+	}
+	txHash := tx.Hash()
+	for _, address := range getTransactionAddresses(tx, notifies) {
+		this.store.BatchPut(this.getAddressTransactionKey(address, height, txHash), nil)
+		this.store.BatchPut(this.getAddressBlockKey(height, address), nil)
+	}
+	return nil
+}
+
+//GetTransactionsByAddress return at most limit transactions touched the address from block height fromHeight, in ascending order of height.
+//Transactions of the same height are in the order of transaction hash
+func (this *EventStore) GetTransactionsByAddress(address common.Address, fromHeight uint32, limit uint32) ([]*scom.AddressTransaction, error) {
+	if !this.addressIndex {
+		return nil, fmt.Errorf("address index is disabled")
+	}
+	iter := this.store.NewIterator(this.getAddressTransactionPrefix(address))
+	defer iter.Release()
+
+	txs := make([]*scom.AddressTransaction, 0)
+	for ok := iter.Seek(this.getAddressTransactionKey(address, fromHeight, common.Uint256{})); ok && uint32(len(txs)) < limit; ok = iter.Next() {
+		key := iter.Key()
+		offset := 1 + common.ADDR_LEN
+		if len(key) != offset+4+common.UINT256_SIZE {
+			return nil, fmt.Errorf("invalid address index key %x", key)
+		}
+		tx := &scom.AddressTransaction{Height: binary.BigEndian.Uint32(key[offset:])}
+		copy(tx.TxHash[:], key[offset+4:])
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+//rollbackAddressTransaction delete the address index of block height in batch
+func (this *EventStore) rollbackAddressTransaction(height uint32) {
+	blockPrefix := this.getAddressBlockKey(height, common.Address{})[:5]
+	iter := this.store.NewIterator(blockPrefix)
+	for iter.Next() {
+		var address common.Address
+		copy(address[:], iter.Key()[len(blockPrefix):])
+		txPrefix := this.getAddressTransactionKey(address, height, common.Uint256{})[:1+common.ADDR_LEN+4]
+		txIter := this.store.NewIterator(txPrefix)
+		for txIter.Next() {
+			this.store.BatchDelete(txIter.Key())
+		}
+		txIter.Release()
+		this.store.BatchDelete(iter.Key())
+	}
+	iter.Release()
+}
+
+//getTransactionAddresses return the distinct addresses touched by transaction
+func getTransactionAddresses(tx *types.Transaction, notifies []*event.NotifyEventInfo) []common.Address {
+	addresses := make([]common.Address, 0)
+	exists := make(map[common.Address]bool)
+	add := func(address common.Address) {
+		if !exists[address] {
+			exists[address] = true
+			addresses = append(addresses, address)
+		}
+	}
+	for _, address := range tx.GetSignatureAddresses() {
+		add(address)
+	}
+	for _, fee := range tx.Fee {
+		add(fee.Payer)
+	}
+	for _, notify := range notifies {
+		if notify.ContractAddress != genesis.OntContractAddress && notify.ContractAddress != genesis.OngContractAddress {
+			continue
+		}
+		states, ok := notify.States.([]interface{})
+		if !ok || len(states) < 3 || event.GetNotifyEventName(states) != native.TRANSFER_NAME {
+			continue
+		}
+		for _, state := range states[1:3] {
+			base58, ok := state.(string)
+			if !ok {
+				continue
+			}
+			address, err := common.AddressFromBase58(base58)
+			if err != nil {
+				continue
+			}
+			add(address)
+		}
+	}
+	return addresses
+}
+
+func (this *EventStore) getAddressTransactionPrefix(address common.Address) []byte {
+	key := make([]byte, 1+common.ADDR_LEN)
+	key[0] = byte(scom.IX_ADDRESS_TX)
+	copy(key[1:], address[:])
+	return key
+}
+
+//getAddressTransactionKey return the index key prefix+address+height+txHash, height is in big endian so that
+//the keys of an address are ordered by height
+func (this *EventStore) getAddressTransactionKey(address common.Address, height uint32, txHash common.Uint256) []byte {
+	prefix := this.getAddressTransactionPrefix(address)
+	key := make([]byte, len(prefix)+4+common.UINT256_SIZE)
+	copy(key, prefix)
+	binary.BigEndian.PutUint32(key[len(prefix):], height)
+	copy(key[len(prefix)+4:], txHash[:])
+	return key
+}
+
+//getAddressBlockKey return the key prefix+height+address, which records the addresses indexed in block for rollback
+func (this *EventStore) getAddressBlockKey(height uint32, address common.Address) []byte {
+	key := make([]byte, 5+common.ADDR_LEN)
+	key[0] = byte(scom.IX_ADDRESS_BLOCK)
+	binary.BigEndian.PutUint32(key[1:], height)
+	copy(key[5:], address[:])
+	return key
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/genesis"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/smartcontract/event"
+)
+
+func TestAddressTransaction(t *testing.T) {
+	eventStore, err := NewEventStore("test/address")
+	if err != nil {
+		t.Errorf("NewEventStore error %s", err)
+		return
+	}
+	defer eventStore.Close()
+
+	_, err = eventStore.GetTransactionsByAddress(common.Address{}, 0, 10)
+	if err == nil {
+		t.Errorf("GetTransactionsByAddress should fail when address index is disabled")
+		return
+	}
+	eventStore.SetAddressIndex(true)
+
+	payer := common.Address{1}
+	from := common.Address{2}
+	to := common.Address{3}
+	other := common.Address{5}
+	eventStore.NewBatch()
+	for height := uint32(1); height <= 5; height++ {
+		tx := &types.Transaction{
+			TxType:     types.BookKeeping,
+			Payload:    &payload.BookKeeping{Nonce: uint64(height)},
+			Attributes: []*types.TxAttribute{},
+			Fee:        []*types.Fee{{Amount: 1, Payer: payer}},
+		}
+		notifies := []*event.NotifyEventInfo{
+			{TxHash: tx.Hash(), ContractAddress: genesis.OntContractAddress, States: []interface{}{"transfer", from.ToBase58(), to.ToBase58(), height}},
+			{TxHash: tx.Hash(), ContractAddress: common.Address{4}, States: []interface{}{"transfer", payer.ToBase58(), other.ToBase58(), height}},
+		}
+		if height%2 == 0 {
+			notifies = nil
+		}
+		err = eventStore.SaveAddressTransaction(height, tx, notifies)
+		if err != nil {
+			t.Errorf("SaveAddressTransaction error %s", err)
+			return
+		}
+	}
+	err = eventStore.SaveCurrentBlock(5, common.Uint256{5})
+	if err != nil {
+		t.Errorf("SaveCurrentBlock error %s", err)
+		return
+	}
+	err = eventStore.CommitTo()
+	if err != nil {
+		t.Errorf("CommitTo error %s", err)
+		return
+	}
+
+	txs, err := eventStore.GetTransactionsByAddress(payer, 2, 10)
+	if err != nil {
+		t.Errorf("GetTransactionsByAddress error %s", err)
+		return
+	}
+	if len(txs) != 4 || txs[0].Height != 2 || txs[3].Height != 5 {
+		t.Errorf("GetTransactionsByAddress payer txs %v unexpected", txs)
+		return
+	}
+	txs, err = eventStore.GetTransactionsByAddress(to, 0, 2)
+	if err != nil {
+		t.Errorf("GetTransactionsByAddress error %s", err)
+		return
+	}
+	if len(txs) != 2 || txs[0].Height != 1 || txs[1].Height != 3 {
+		t.Errorf("GetTransactionsByAddress to txs %v unexpected", txs)
+		return
+	}
+	txs, err = eventStore.GetTransactionsByAddress(other, 0, 10)
+	if err != nil {
+		t.Errorf("GetTransactionsByAddress error %s", err)
+		return
+	}
+	if len(txs) != 0 {
+		t.Errorf("transfer of non native contract should not be indexed")
+		return
+	}
+
+	err = eventStore.RollbackTo(2, common.Uint256{2})
+	if err != nil {
+		t.Errorf("RollbackTo error %s", err)
+		return
+	}
+	txs, err = eventStore.GetTransactionsByAddress(from, 0, 10)
+	if err != nil {
+		t.Errorf("GetTransactionsByAddress error %s", err)
+		return
+	}
+	if len(txs) != 1 || txs[0].Height != 1 {
+		t.Errorf("GetTransactionsByAddress after rollback txs %v unexpected", txs)
+		return
+	}
+}
@@ -26,17 +26,16 @@ import (
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/serialization"
 	scom "github.com/ontio/ontology/core/store/common"
-	"github.com/ontio/ontology/core/store/leveldbstore"
 	"github.com/ontio/ontology/core/types"
 	"github.com/syndtr/goleveldb/leveldb"
 )
 
 //Block store save the data of block & transaction
 type BlockStore struct {
-	enableCache bool                       //Is enable lru cache
-	dbDir       string                     //The path of store file
-	cache       *BlockCache                //The cache of block, if have.
-	store       *leveldbstore.LevelDBStore //block store handler
+	enableCache bool              //Is enable lru cache
+	dbDir       string            //The path of store file
+	cache       *BlockCache       //The cache of block, if have.
+	store       scom.PersistStore //block store handler
 }
 
 //NewBlockStore return the block store instance
@@ -50,7 +49,7 @@ func NewBlockStore(dbDir string, enableCache bool) (*BlockStore, error) {
 		}
 	}
 
-	store, err := leveldbstore.NewLevelDBStore(dbDir)
+	store, err := NewPersistStore(dbDir)
 	if err != nil {
 		return nil, err
 	}
@@ -88,6 +87,23 @@ func (this *BlockStore) SaveBlock(block *types.Block) error {
 	return nil
 }
 
+//RemoveBlock delete block header, transactions and block hash index of block from store
+func (this *BlockStore) RemoveBlock(block *types.Block) {
+	blockHash := block.Hash()
+	if this.enableCache {
+		this.cache.RemoveBlock(blockHash)
+	}
+	this.store.BatchDelete(this.getHeaderKey(blockHash))
+	this.store.BatchDelete(this.getBlockHashKey(block.Header.Height))
+	for _, tx := range block.Transactions {
+		txHash := tx.Hash()
+		if this.enableCache {
+			this.cache.RemoveTransaction(txHash)
+		}
+		this.store.BatchDelete(this.getTransactionKey(txHash))
+	}
+}
+
 //ContainBlock return the block specified by block hash save in store
 func (this *BlockStore) ContainBlock(blockHash common.Uint256) (bool, error) {
 	if this.enableCache {
@@ -326,6 +342,11 @@ func (this *BlockStore) SaveHeaderIndexList(startIndex uint32, indexList []commo
 	return nil
 }
 
+//RemoveHeaderIndexList delete header index list which start with startIndex from store
+func (this *BlockStore) RemoveHeaderIndexList(startIndex uint32) {
+	this.store.BatchDelete(this.getHeaderIndexListKey(startIndex))
+}
+
 //GetBlockHash return block hash by block height
 func (this *BlockStore) GetBlockHash(height uint32) (common.Uint256, error) {
 	key := this.getBlockHashKey(height)
@@ -370,6 +391,15 @@ func (this *BlockStore) putTransaction(tx *types.Transaction, height uint32) err
 	return nil
 }
 
+//SaveTransactionHeight persist the block height of transaction without transaction body.
+//Using in snapshot import, so transactions of blocks before snapshot can still be found by ContainTransaction
+func (this *BlockStore) SaveTransactionHeight(txHash common.Uint256, height uint32) {
+	key := this.getTransactionKey(txHash)
+	value := bytes.NewBuffer(nil)
+	serialization.WriteUint32(value, height)
+	this.store.BatchPut(key, value.Bytes())
+}
+
 //GetTransaction return transaction by transaction hash
 func (this *BlockStore) GetTransaction(txHash common.Uint256) (*types.Transaction, uint32, error) {
 	if this.enableCache {
@@ -405,6 +435,10 @@ func (this *BlockStore) loadTransaction(txHash common.Uint256) (*types.Transacti
 	if err != nil {
 		return nil, 0, fmt.Errorf("ReadUint32 error %s", err)
 	}
+	//Only height is saved for transaction before snapshot height
+	if reader.Len() == 0 {
+		return nil, height, nil
+	}
 	tx = new(types.Transaction)
 	err = tx.Deserialize(reader)
 	if err != nil {
@@ -469,6 +503,16 @@ func (this *BlockStore) CommitTo() error {
 	return this.store.BatchCommit()
 }
 
+//GetBatchData return the serialized data of commit batch
+func (this *BlockStore) GetBatchData() []byte {
+	return this.store.BatchData()
+}
+
+//CommitBatchData commit the batch data serialized by GetBatchData to store
+func (this *BlockStore) CommitBatchData(data []byte) error {
+	return this.store.CommitBatchData(data)
+}
+
 //Close block store
 func (this *BlockStore) Close() error {
 	return this.store.Close()
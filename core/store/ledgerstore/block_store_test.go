@@ -167,6 +167,42 @@ func TestSaveTransaction(t *testing.T) {
 	}
 }
 
+func TestSaveTransactionHeight(t *testing.T) {
+	txHash := common.Uint256{0xfe, 1}
+	blockHeight := uint32(2)
+
+	testBlockStore.NewBatch()
+	testBlockStore.SaveTransactionHeight(txHash, blockHeight)
+	err := testBlockStore.CommitTo()
+	if err != nil {
+		t.Errorf("CommitTo error %s", err)
+		return
+	}
+
+	exist, err := testBlockStore.ContainTransaction(txHash)
+	if err != nil {
+		t.Errorf("ContainTransaction error %s", err)
+		return
+	}
+	if !exist {
+		t.Errorf("TestSaveTransactionHeight ContainTransaction should be true.")
+		return
+	}
+	tx, height, err := testBlockStore.GetTransaction(txHash)
+	if err != nil {
+		t.Errorf("GetTransaction error %s", err)
+		return
+	}
+	if tx != nil {
+		t.Errorf("TestSaveTransactionHeight GetTransaction should return nil transaction")
+		return
+	}
+	if height != blockHeight {
+		t.Errorf("TestSaveTransactionHeight failed BlockHeight %d != %d", height, blockHeight)
+		return
+	}
+}
+
 func TestHeaderIndexList(t *testing.T) {
 	testBlockStore.NewBatch()
 	startHeight := uint32(0)
@@ -0,0 +1,218 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/core/types"
+)
+
+const (
+	BLOCK_STREAM_TAG            = "ONTBLOCK"       //Tag of block stream file
+	BLOCK_STREAM_VERSION        = byte(1)          //Version of block stream file format
+	BLOCK_STREAM_MAX_BLOCK_SIZE = 64 * 1024 * 1024 //Max size of block in block stream
+)
+
+//BlockStreamHeader is the head of block stream file, followed by BlockCount blocks
+//from StartHeight, each block is prefixed with its uint32 length.
+type BlockStreamHeader struct {
+	Version     byte
+	Magic       int64 //Network magic of the exporting node
+	StartHeight uint32
+	BlockCount  uint32
+}
+
+func (this *BlockStreamHeader) Serialize(w io.Writer) error {
+	_, err := w.Write([]byte(BLOCK_STREAM_TAG))
+	if err != nil {
+		return err
+	}
+	err = serialization.WriteByte(w, this.Version)
+	if err != nil {
+		return err
+	}
+	err = serialization.WriteUint64(w, uint64(this.Magic))
+	if err != nil {
+		return err
+	}
+	err = serialization.WriteUint32(w, this.StartHeight)
+	if err != nil {
+		return err
+	}
+	return serialization.WriteUint32(w, this.BlockCount)
+}
+
+func (this *BlockStreamHeader) Deserialize(r io.Reader) error {
+	tag := make([]byte, len(BLOCK_STREAM_TAG))
+	_, err := io.ReadFull(r, tag)
+	if err != nil || string(tag) != BLOCK_STREAM_TAG {
+		return fmt.Errorf("not a block stream file")
+	}
+	this.Version, err = serialization.ReadByte(r)
+	if err != nil {
+		return err
+	}
+	if this.Version != BLOCK_STREAM_VERSION {
+		return fmt.Errorf("unsupported block stream version %d", this.Version)
+	}
+	magic, err := serialization.ReadUint64(r)
+	if err != nil {
+		return err
+	}
+	this.Magic = int64(magic)
+	this.StartHeight, err = serialization.ReadUint32(r)
+	if err != nil {
+		return err
+	}
+	this.BlockCount, err = serialization.ReadUint32(r)
+	return err
+}
+
+//writeStreamBlock write block with length prefix
+func writeStreamBlock(w io.Writer, block *types.Block) error {
+	buf := bytes.NewBuffer(nil)
+	err := block.Serialize(buf)
+	if err != nil {
+		return err
+	}
+	err = serialization.WriteUint32(w, uint32(buf.Len()))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+//readStreamBlock read the length prefixed block. Block is skipped and nil is returned if skip is true
+func readStreamBlock(r io.Reader, skip bool) (*types.Block, error) {
+	size, err := serialization.ReadUint32(r)
+	if err != nil {
+		return nil, err
+	}
+	if size > BLOCK_STREAM_MAX_BLOCK_SIZE {
+		return nil, fmt.Errorf("block size %d exceeds limit", size)
+	}
+	if skip {
+		_, err = io.CopyN(ioutil.Discard, r, int64(size))
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	data := make([]byte, size)
+	_, err = io.ReadFull(r, data)
+	if err != nil {
+		return nil, err
+	}
+	block := new(types.Block)
+	err = block.Deserialize(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+//ExportBlocks write blocks of height [start, end] to w in block stream format.
+//progress is called with the height and the end height after each block is written if not nil.
+func (this *LedgerStoreImp) ExportBlocks(start, end uint32, w io.Writer, progress func(height, endHeight uint32)) error {
+	currHeight := this.GetCurrentBlockHeight()
+	if start > end || end > currHeight {
+		return fmt.Errorf("block range [%d, %d] out of range [0, %d]", start, end, currHeight)
+	}
+	header := &BlockStreamHeader{
+		Version:     BLOCK_STREAM_VERSION,
+		Magic:       config.Parameters.Magic,
+		StartHeight: start,
+		BlockCount:  end - start + 1,
+	}
+	err := header.Serialize(w)
+	if err != nil {
+		return fmt.Errorf("write block stream header error %s", err)
+	}
+	for height := start; height <= end; height++ {
+		block, err := this.GetBlockByHeight(height)
+		if err != nil {
+			return fmt.Errorf("GetBlockByHeight height:%d error %s", height, err)
+		}
+		if block == nil {
+			return fmt.Errorf("cannot find block of height %d", height)
+		}
+		err = writeStreamBlock(w, block)
+		if err != nil {
+			return fmt.Errorf("write block height:%d error %s", height, err)
+		}
+		if progress != nil {
+			progress(height, end)
+		}
+	}
+	return nil
+}
+
+//ImportBlocks add the blocks in block stream r to ledger by AddBlock, so every block is fully verified.
+//Blocks not higher than current block height are skipped, so an interrupted import can be resumed with the same file.
+//progress is called with the height and the end height of stream after each block is added if not nil. The header of stream is returned.
+func (this *LedgerStoreImp) ImportBlocks(r io.Reader, progress func(height, endHeight uint32)) (*BlockStreamHeader, error) {
+	hasInit, err := this.hasAlreadyInitGenesisBlock()
+	if err != nil {
+		return nil, fmt.Errorf("hasAlreadyInit error %s", err)
+	}
+	if !hasInit {
+		return nil, fmt.Errorf("ledger has not been initialized with genesis block")
+	}
+	header := new(BlockStreamHeader)
+	err = header.Deserialize(r)
+	if err != nil {
+		return nil, fmt.Errorf("read block stream header error %s", err)
+	}
+	if header.Magic != config.Parameters.Magic {
+		return header, fmt.Errorf("block stream magic %d is different from node magic %d", header.Magic, config.Parameters.Magic)
+	}
+	currHeight := this.GetCurrentBlockHeight()
+	if header.StartHeight > currHeight+1 {
+		return header, fmt.Errorf("block stream start height %d is higher than next block height %d", header.StartHeight, currHeight+1)
+	}
+	for i := uint32(0); i < header.BlockCount; i++ {
+		height := header.StartHeight + i
+		skip := height <= this.GetCurrentBlockHeight()
+		block, err := readStreamBlock(r, skip)
+		if err != nil {
+			return header, fmt.Errorf("read block height:%d error %s", height, err)
+		}
+		if skip {
+			continue
+		}
+		if block.Header.Height != height {
+			return header, fmt.Errorf("block height %d is inconsistent with stream height %d", block.Header.Height, height)
+		}
+		err = this.AddBlock(block)
+		if err != nil {
+			return header, fmt.Errorf("AddBlock height:%d error %s", height, err)
+		}
+		if progress != nil {
+			progress(height, header.StartHeight+header.BlockCount-1)
+		}
+	}
+	return header, nil
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/types"
+)
+
+func TestBlockStream(t *testing.T) {
+	header := &BlockStreamHeader{
+		Version:     BLOCK_STREAM_VERSION,
+		Magic:       7630401,
+		StartHeight: 10,
+		BlockCount:  2,
+	}
+	buf := bytes.NewBuffer(nil)
+	err := header.Serialize(buf)
+	if err != nil {
+		t.Errorf("BlockStreamHeader Serialize error %s", err)
+		return
+	}
+	blocks := make([]*types.Block, 0, header.BlockCount)
+	for i := uint32(0); i < header.BlockCount; i++ {
+		block := &types.Block{
+			Header: &types.Header{
+				Height:        header.StartHeight + i,
+				PrevBlockHash: common.Uint256{byte(i)},
+			},
+			Transactions: []*types.Transaction{
+				{
+					TxType:     types.BookKeeping,
+					Payload:    &payload.BookKeeping{Nonce: uint64(i)},
+					Attributes: []*types.TxAttribute{},
+				},
+			},
+		}
+		block.RebuildMerkleRoot()
+		blocks = append(blocks, block)
+		err = writeStreamBlock(buf, block)
+		if err != nil {
+			t.Errorf("writeStreamBlock error %s", err)
+			return
+		}
+	}
+
+	header1 := new(BlockStreamHeader)
+	err = header1.Deserialize(buf)
+	if err != nil {
+		t.Errorf("BlockStreamHeader Deserialize error %s", err)
+		return
+	}
+	if *header1 != *header {
+		t.Errorf("TestBlockStream header %+v != %+v", header1, header)
+		return
+	}
+	block, err := readStreamBlock(buf, true)
+	if err != nil || block != nil {
+		t.Errorf("readStreamBlock skip error %v", err)
+		return
+	}
+	block, err = readStreamBlock(buf, false)
+	if err != nil {
+		t.Errorf("readStreamBlock error %s", err)
+		return
+	}
+	if block.Hash() != blocks[1].Hash() {
+		t.Errorf("TestBlockStream block hash %x != %x", block.Hash(), blocks[1].Hash())
+		return
+	}
+	_, err = readStreamBlock(buf, false)
+	if err == nil {
+		t.Errorf("readStreamBlock should fail at end of stream")
+		return
+	}
+
+	buf.Reset()
+	serialization.WriteUint32(buf, BLOCK_STREAM_MAX_BLOCK_SIZE+1)
+	_, err = readStreamBlock(buf, false)
+	if err == nil {
+		t.Errorf("readStreamBlock should fail with oversize block")
+		return
+	}
+	err = header1.Deserialize(bytes.NewReader([]byte("NOTBLOCK")))
+	if err == nil {
+		t.Errorf("BlockStreamHeader Deserialize should fail with wrong tag")
+		return
+	}
+}
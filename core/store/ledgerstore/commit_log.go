@@ -0,0 +1,123 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/serialization"
+)
+
+//BlockCommitLog is the write-ahead log of block commit. The batches of block store, state store and event store are
+//persisted to the log before committed to the stores, so a block commit interrupted between stores can be replayed at startup.
+type BlockCommitLog struct {
+	path string //Log file path
+}
+
+//NewBlockCommitLog return BlockCommitLog instance
+func NewBlockCommitLog(path string) *BlockCommitLog {
+	return &BlockCommitLog{
+		path: path,
+	}
+}
+
+//Write persist the batches of block to log. Log file is replaced by rename, so it is either complete or the previous one
+func (this *BlockCommitLog) Write(height uint32, blockHash common.Uint256, batches [][]byte) error {
+	data := bytes.NewBuffer(nil)
+	serialization.WriteUint32(data, height)
+	blockHash.Serialize(data)
+	serialization.WriteVarUint(data, uint64(len(batches)))
+	for _, batch := range batches {
+		serialization.WriteVarBytes(data, batch)
+	}
+	checksum := sha256.Sum256(data.Bytes())
+	data.Write(checksum[:])
+
+	tmpPath := this.path + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data.Bytes())
+	if err == nil {
+		err = file.Sync()
+	}
+	file.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, this.path)
+}
+
+//Read return the block height, block hash and batches in log. Batches is nil if there is no log
+func (this *BlockCommitLog) Read() (uint32, common.Uint256, [][]byte, error) {
+	data, err := ioutil.ReadFile(this.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, common.Uint256{}, nil, nil
+		}
+		return 0, common.Uint256{}, nil, err
+	}
+	if len(data) < sha256.Size {
+		return 0, common.Uint256{}, nil, fmt.Errorf("commit log size %d too short", len(data))
+	}
+	payload := data[:len(data)-sha256.Size]
+	checksum := sha256.Sum256(payload)
+	if !bytes.Equal(checksum[:], data[len(payload):]) {
+		return 0, common.Uint256{}, nil, fmt.Errorf("commit log checksum mismatch")
+	}
+
+	reader := bytes.NewReader(payload)
+	height, err := serialization.ReadUint32(reader)
+	if err != nil {
+		return 0, common.Uint256{}, nil, fmt.Errorf("read height error %s", err)
+	}
+	var blockHash common.Uint256
+	err = blockHash.Deserialize(reader)
+	if err != nil {
+		return 0, common.Uint256{}, nil, fmt.Errorf("read block hash error %s", err)
+	}
+	count, err := serialization.ReadVarUint(reader, 0)
+	if err != nil {
+		return 0, common.Uint256{}, nil, fmt.Errorf("read batch count error %s", err)
+	}
+	batches := make([][]byte, 0, count)
+	for i := uint64(0); i < count; i++ {
+		batch, err := serialization.ReadVarBytes(reader)
+		if err != nil {
+			return 0, common.Uint256{}, nil, fmt.Errorf("read batch error %s", err)
+		}
+		batches = append(batches, batch)
+	}
+	return height, blockHash, batches, nil
+}
+
+//Clear delete the log after the batches committed to all stores
+func (this *BlockCommitLog) Clear() error {
+	err := os.Remove(this.path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
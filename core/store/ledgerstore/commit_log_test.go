@@ -0,0 +1,90 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+)
+
+func TestBlockCommitLog(t *testing.T) {
+	commitLog := NewBlockCommitLog("test/block_commit.log")
+	height := uint32(10)
+	blockHash := common.Uint256{1, 2, 3}
+	batches := [][]byte{[]byte("block"), []byte("state"), []byte("event")}
+	err := commitLog.Write(height, blockHash, batches)
+	if err != nil {
+		t.Errorf("Write error %s", err)
+		return
+	}
+
+	logHeight, logBlockHash, logBatches, err := commitLog.Read()
+	if err != nil {
+		t.Errorf("Read error %s", err)
+		return
+	}
+	if logHeight != height || logBlockHash != blockHash {
+		t.Errorf("TestBlockCommitLog block %d %x != %d %x", logHeight, logBlockHash, height, blockHash)
+		return
+	}
+	if len(logBatches) != len(batches) {
+		t.Errorf("TestBlockCommitLog batch count %d != %d", len(logBatches), len(batches))
+		return
+	}
+	for i, batch := range batches {
+		if !bytes.Equal(logBatches[i], batch) {
+			t.Errorf("TestBlockCommitLog batch %d %s != %s", i, logBatches[i], batch)
+			return
+		}
+	}
+
+	data, err := ioutil.ReadFile(commitLog.path)
+	if err != nil {
+		t.Errorf("ReadFile error %s", err)
+		return
+	}
+	err = ioutil.WriteFile(commitLog.path, data[:len(data)-1], 0644)
+	if err != nil {
+		t.Errorf("WriteFile error %s", err)
+		return
+	}
+	_, _, _, err = commitLog.Read()
+	if err == nil {
+		t.Errorf("TestBlockCommitLog broken log should fail")
+		return
+	}
+
+	err = commitLog.Clear()
+	if err != nil {
+		t.Errorf("Clear error %s", err)
+		return
+	}
+	_, _, logBatches, err = commitLog.Read()
+	if err != nil {
+		t.Errorf("Read error %s", err)
+		return
+	}
+	if logBatches != nil {
+		t.Errorf("TestBlockCommitLog log should be cleared")
+		return
+	}
+}
@@ -27,20 +27,20 @@ import (
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/serialization"
 	scom "github.com/ontio/ontology/core/store/common"
-	"github.com/ontio/ontology/core/store/leveldbstore"
 	"github.com/ontio/ontology/smartcontract/event"
 	"github.com/syndtr/goleveldb/leveldb"
 )
 
 //Saving event notifies gen by smart contract execution
 type EventStore struct {
-	dbDir string                     //Store path
-	store *leveldbstore.LevelDBStore //Store handler
+	dbDir        string            //Store path
+	store        scom.PersistStore //Store handler
+	addressIndex bool              //Whether to index transactions by address
 }
 
 //NewEventStore return event store instance
 func NewEventStore(dbDir string) (*EventStore, error) {
-	store, err := leveldbstore.NewLevelDBStore(dbDir)
+	store, err := NewPersistStore(dbDir)
 	if err != nil {
 		return nil, err
 	}
@@ -89,6 +89,77 @@ func (this *EventStore) SaveEventNotifyByBlock(height uint32, txHashs []common.U
 	return nil
 }
 
+//SaveEventNotifyByContract persist the index of event notify by contract address, block height and event name
+func (this *EventStore) SaveEventNotifyByContract(height uint32, txHash common.Uint256, notifies []*event.NotifyEventInfo) error {
+	for index, notify := range notifies {
+		key := this.getEventNotifyByContractKey(notify.ContractAddress, height, txHash, uint32(index))
+		value := bytes.NewBuffer(nil)
+		err := serialization.WriteString(value, event.GetNotifyEventName(notify.States))
+		if err != nil {
+			return fmt.Errorf("WriteString error %s", err)
+		}
+		this.store.BatchPut(key, value.Bytes())
+	}
+	return nil
+}
+
+//GetEventNotifyByContract return event notifies of contract between startHeight and endHeight (both inclusive),
+//in ascending order of height. If eventName is not empty, only notifies with the event name will be returned.
+//offset is the number of matched notifies to skip, and at most limit notifies will be returned.
+func (this *EventStore) GetEventNotifyByContract(contract common.Address, startHeight, endHeight uint32, eventName string, offset, limit uint32) ([]*event.ContractNotifyEventInfo, error) {
+	if startHeight > endHeight || limit == 0 {
+		return nil, nil
+	}
+	prefix := this.getEventNotifyByContractPrefix(contract)
+	iter := this.store.NewIterator(prefix)
+	defer iter.Release()
+
+	notifies := make([]*event.ContractNotifyEventInfo, 0)
+	txNotifies := make(map[common.Uint256][]*event.NotifyEventInfo)
+	matched := uint32(0)
+	for ok := iter.Seek(this.getEventNotifyByContractKey(contract, startHeight, common.Uint256{}, 0)); ok; ok = iter.Next() {
+		height, txHash, index, err := this.parseEventNotifyByContractKey(iter.Key())
+		if err != nil {
+			return nil, err
+		}
+		if height > endHeight {
+			break
+		}
+		if eventName != "" {
+			name, err := serialization.ReadString(bytes.NewReader(iter.Value()))
+			if err != nil {
+				return nil, fmt.Errorf("ReadString error %s", err)
+			}
+			if name != eventName {
+				continue
+			}
+		}
+		matched++
+		if matched <= offset {
+			continue
+		}
+		txNotify, ok := txNotifies[txHash]
+		if !ok {
+			txNotify, err = this.GetEventNotifyByTx(txHash)
+			if err != nil {
+				return nil, fmt.Errorf("GetEventNotifyByTx error %s", err)
+			}
+			txNotifies[txHash] = txNotify
+		}
+		if index >= uint32(len(txNotify)) {
+			return nil, fmt.Errorf("event notify index %d of tx %x out of range", index, txHash)
+		}
+		notifies = append(notifies, &event.ContractNotifyEventInfo{
+			Height: height,
+			Notify: txNotify[index],
+		})
+		if uint32(len(notifies)) >= limit {
+			break
+		}
+	}
+	return notifies, nil
+}
+
 //GetEventNotifyByTx return event notify by trasanction hash
 func (this *EventStore) GetEventNotifyByTx(txHash common.Uint256) ([]*event.NotifyEventInfo, error) {
 	key := this.getEventNotifyByTxKey(txHash)
@@ -141,6 +212,16 @@ func (this *EventStore) CommitTo() error {
 	return this.store.BatchCommit()
 }
 
+//GetBatchData return the serialized data of event store batch
+func (this *EventStore) GetBatchData() []byte {
+	return this.store.BatchData()
+}
+
+//CommitBatchData commit the batch data serialized by GetBatchData to store
+func (this *EventStore) CommitBatchData(data []byte) error {
+	return this.store.CommitBatchData(data)
+}
+
 //Close event store
 func (this *EventStore) Close() error {
 	return this.store.Close()
@@ -168,6 +249,45 @@ func (this *EventStore) SaveCurrentBlock(height uint32, blockHash common.Uint256
 	return nil
 }
 
+//RollbackTo delete the event notifies of blocks after height, and reset current block to height
+func (this *EventStore) RollbackTo(height uint32, blockHash common.Uint256) error {
+	_, currHeight, err := this.GetCurrentBlock()
+	if err != nil {
+		return fmt.Errorf("GetCurrentBlock error %s", err)
+	}
+	if height >= currHeight {
+		return nil
+	}
+	this.NewBatch()
+	for h := currHeight; h > height; h-- {
+		txHashs, err := this.GetEventNotifyByBlock(h)
+		if err != nil {
+			return fmt.Errorf("GetEventNotifyByBlock height %d error %s", h, err)
+		}
+		for _, txHash := range txHashs {
+			notifies, err := this.GetEventNotifyByTx(txHash)
+			if err != nil {
+				return fmt.Errorf("GetEventNotifyByTx error %s", err)
+			}
+			for index, notify := range notifies {
+				this.store.BatchDelete(this.getEventNotifyByContractKey(notify.ContractAddress, h, txHash, uint32(index)))
+			}
+			this.store.BatchDelete(this.getEventNotifyByTxKey(txHash))
+		}
+		this.rollbackAddressTransaction(h)
+		key, err := this.getEventNotifyByBlockKey(h)
+		if err != nil {
+			return err
+		}
+		this.store.BatchDelete(key)
+	}
+	err = this.SaveCurrentBlock(height, blockHash)
+	if err != nil {
+		return fmt.Errorf("SaveCurrentBlock error %s", err)
+	}
+	return this.CommitTo()
+}
+
 //GetCurrentBlock return current block hash, and block height
 func (this *EventStore) GetCurrentBlock() (common.Uint256, uint32, error) {
 	key := this.getCurrentBlockKey()
@@ -209,3 +329,34 @@ func (this *EventStore) getEventNotifyByTxKey(txHash common.Uint256) []byte {
 	copy(key[1:], data)
 	return key
 }
+
+func (this *EventStore) getEventNotifyByContractPrefix(contract common.Address) []byte {
+	key := make([]byte, 1+common.ADDR_LEN)
+	key[0] = byte(scom.EVENT_NOTIFY_CONTRACT)
+	copy(key[1:], contract[:])
+	return key
+}
+
+//getEventNotifyByContractKey return the index key prefix+contract+height+txHash+index, height and index
+//are in big endian so that the keys of a contract are ordered by height
+func (this *EventStore) getEventNotifyByContractKey(contract common.Address, height uint32, txHash common.Uint256, index uint32) []byte {
+	prefix := this.getEventNotifyByContractPrefix(contract)
+	key := make([]byte, len(prefix)+4+common.UINT256_SIZE+4)
+	copy(key, prefix)
+	binary.BigEndian.PutUint32(key[len(prefix):], height)
+	copy(key[len(prefix)+4:], txHash[:])
+	binary.BigEndian.PutUint32(key[len(prefix)+4+common.UINT256_SIZE:], index)
+	return key
+}
+
+func (this *EventStore) parseEventNotifyByContractKey(key []byte) (uint32, common.Uint256, uint32, error) {
+	offset := 1 + common.ADDR_LEN
+	if len(key) != offset+4+common.UINT256_SIZE+4 {
+		return 0, common.Uint256{}, 0, fmt.Errorf("invalid event notify index key %x", key)
+	}
+	height := binary.BigEndian.Uint32(key[offset:])
+	var txHash common.Uint256
+	copy(txHash[:], key[offset+4:])
+	index := binary.BigEndian.Uint32(key[offset+4+common.UINT256_SIZE:])
+	return height, txHash, index, nil
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/smartcontract/event"
+)
+
+func TestEventNotifyByContract(t *testing.T) {
+	eventStore, err := NewEventStore("test/event")
+	if err != nil {
+		t.Errorf("NewEventStore error %s", err)
+		return
+	}
+	defer eventStore.Close()
+
+	contract := common.Address{1, 2, 3}
+	other := common.Address{4, 5, 6}
+	eventStore.NewBatch()
+	for height := uint32(1); height <= 5; height++ {
+		txHash := common.Uint256{byte(height)}
+		notifies := []*event.NotifyEventInfo{
+			{TxHash: txHash, ContractAddress: contract, States: []interface{}{"transfer", float64(height)}},
+			{TxHash: txHash, ContractAddress: other, States: []interface{}{"transfer", float64(height)}},
+			{TxHash: txHash, ContractAddress: contract, States: []interface{}{"approve", float64(height)}},
+		}
+		err = eventStore.SaveEventNotifyByTx(txHash, notifies)
+		if err != nil {
+			t.Errorf("SaveEventNotifyByTx error %s", err)
+			return
+		}
+		err = eventStore.SaveEventNotifyByContract(height, txHash, notifies)
+		if err != nil {
+			t.Errorf("SaveEventNotifyByContract error %s", err)
+			return
+		}
+		err = eventStore.SaveEventNotifyByBlock(height, []common.Uint256{txHash})
+		if err != nil {
+			t.Errorf("SaveEventNotifyByBlock error %s", err)
+			return
+		}
+	}
+	err = eventStore.SaveCurrentBlock(5, common.Uint256{5})
+	if err != nil {
+		t.Errorf("SaveCurrentBlock error %s", err)
+		return
+	}
+	err = eventStore.CommitTo()
+	if err != nil {
+		t.Errorf("CommitTo error %s", err)
+		return
+	}
+
+	notifies, err := eventStore.GetEventNotifyByContract(contract, 2, 4, "", 0, 100)
+	if err != nil {
+		t.Errorf("GetEventNotifyByContract error %s", err)
+		return
+	}
+	if len(notifies) != 6 {
+		t.Errorf("GetEventNotifyByContract notifies count %d != 6", len(notifies))
+		return
+	}
+	for i, notify := range notifies {
+		if notify.Height != uint32(2+i/2) {
+			t.Errorf("notify %d height %d != %d", i, notify.Height, 2+i/2)
+			return
+		}
+		if notify.Notify.ContractAddress != contract {
+			t.Errorf("notify %d contract %x != %x", i, notify.Notify.ContractAddress, contract)
+			return
+		}
+	}
+
+	notifies, err = eventStore.GetEventNotifyByContract(contract, 1, 5, "approve", 1, 2)
+	if err != nil {
+		t.Errorf("GetEventNotifyByContract error %s", err)
+		return
+	}
+	if len(notifies) != 2 {
+		t.Errorf("GetEventNotifyByContract notifies count %d != 2", len(notifies))
+		return
+	}
+	for i, notify := range notifies {
+		if notify.Height != uint32(2+i) || event.GetNotifyEventName(notify.Notify.States) != "approve" {
+			t.Errorf("notify %d height %d name %s unexpected", i, notify.Height, event.GetNotifyEventName(notify.Notify.States))
+			return
+		}
+	}
+
+	err = eventStore.RollbackTo(3, common.Uint256{3})
+	if err != nil {
+		t.Errorf("RollbackTo error %s", err)
+		return
+	}
+	notifies, err = eventStore.GetEventNotifyByContract(contract, 1, 5, "transfer", 0, 100)
+	if err != nil {
+		t.Errorf("GetEventNotifyByContract error %s", err)
+		return
+	}
+	if len(notifies) != 3 || notifies[2].Height != 3 {
+		t.Errorf("GetEventNotifyByContract after rollback notifies count %d != 3", len(notifies))
+		return
+	}
+}
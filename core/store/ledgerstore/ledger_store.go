@@ -20,21 +20,31 @@ package ledgerstore
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"sort"
 	"sync"
 	"time"
 
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/proof"
 	"github.com/ontio/ontology/core/signature"
 	"github.com/ontio/ontology/core/states"
+	scom "github.com/ontio/ontology/core/store/common"
 	"github.com/ontio/ontology/core/store/statestore"
 	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/events"
 	"github.com/ontio/ontology/events/message"
+	"github.com/ontio/ontology/smartcontract"
+	smcom "github.com/ontio/ontology/smartcontract/common"
+	"github.com/ontio/ontology/smartcontract/context"
 	"github.com/ontio/ontology/smartcontract/event"
+	vmtypes "github.com/ontio/ontology/vm/neovm/types"
 )
 
 const (
@@ -43,6 +53,7 @@ const (
 	BLOCK_CACHE_TIMEOUT     = time.Minute * 15 //Cache time for block to save in sync block
 	MAX_HEADER_CACHE_SIZE   = 5000             //Max cache size of block header in sync block
 	MAX_BLOCK_CACHE_SIZE    = 500              //Max cache size of block in sync block
+	MAX_CONTRACT_HISTORY    = 1024             //Max number of versions returned by one contract history query
 )
 
 var (
@@ -51,6 +62,7 @@ var (
 	DBDirBlock          = "Chain/block"
 	DBDirState          = "Chain/states"
 	MerkleTreeStorePath = "Chain/merkle_tree.db"
+	BlockCommitLogPath  = "Chain/block_commit.log"
 )
 
 type ledgerCacheItem struct {
@@ -63,6 +75,8 @@ type LedgerStoreImp struct {
 	blockStore       *BlockStore                         //BlockStore for saving block & transaction data
 	stateStore       *StateStore                         //StateStore for saving state data, like balance, smart contract execution result, and so on.
 	eventStore       *EventStore                         //EventStore for saving log those gen after smart contract executed.
+	commitLog        *BlockCommitLog                     //Write-ahead log of block commit, keep the stores advance to a block together
+	tempDir          string                              //Dir of merkle tree and commit log files with memory backend, removed when closed
 	storedIndexCount uint32                              //record the count of have saved block index
 	currBlockHeight  uint32                              //Current block height
 	currBlockHash    common.Uint256                      //Current block hash
@@ -89,17 +103,40 @@ func NewLedgerStore() (*LedgerStoreImp, error) {
 	}
 	ledgerStore.blockStore = blockStore
 
-	stateStore, err := NewStateStore(DBDirState, MerkleTreeStorePath)
+	merklePath, commitLogPath := MerkleTreeStorePath, BlockCommitLogPath
+	if config.Parameters.StoreBackend == config.STORE_BACKEND_MEMORY {
+		//The ledger in memory is lost after node stopped, so the merkle tree and commit log files are saved in temp dir
+		//instead of Chain dir, otherwise they would be inconsistent with the empty ledger after restart.
+		ledgerStore.tempDir, err = ioutil.TempDir("", "ontology-ledger")
+		if err != nil {
+			return nil, fmt.Errorf("TempDir error %s", err)
+		}
+		merklePath = filepath.Join(ledgerStore.tempDir, filepath.Base(MerkleTreeStorePath))
+		commitLogPath = filepath.Join(ledgerStore.tempDir, filepath.Base(BlockCommitLogPath))
+	}
+	stateStore, err := NewStateStore(DBDirState, merklePath)
 	if err != nil {
 		return nil, fmt.Errorf("NewStateStore error %s", err)
 	}
+	retention, err := NewStateRetention(config.Parameters.StateRetention, config.Parameters.StateKeepBlocks)
+	if err != nil {
+		return nil, fmt.Errorf("NewStateRetention error %s", err)
+	}
+	stateStore.SetStateRetention(retention)
 	ledgerStore.stateStore = stateStore
 
 	eventState, err := NewEventStore(DBDirEvent)
 	if err != nil {
 		return nil, fmt.Errorf("NewEventStore error %s", err)
 	}
+	eventState.SetAddressIndex(config.Parameters.AddressIndex)
 	ledgerStore.eventStore = eventState
+	ledgerStore.commitLog = NewBlockCommitLog(commitLogPath)
+
+	err = ledgerStore.recoverBlockCommit()
+	if err != nil {
+		return nil, fmt.Errorf("recoverBlockCommit error %s", err)
+	}
 
 	err = ledgerStore.init()
 	if err != nil {
@@ -187,6 +224,43 @@ func (this *LedgerStoreImp) init() error {
 	return nil
 }
 
+//recoverBlockCommit replay the block commit which was interrupted between stores with the commit log
+func (this *LedgerStoreImp) recoverBlockCommit() error {
+	height, blockHash, batches, err := this.commitLog.Read()
+	if err != nil {
+		//No store is committed before the log is completely written, so a broken log can be discarded
+		log.Warnf("discard block commit log error %s", err)
+		return this.commitLog.Clear()
+	}
+	if batches == nil {
+		return nil
+	}
+	currBlockHash, currBlockHeight, err := this.blockStore.GetCurrentBlock()
+	if err != nil {
+		return fmt.Errorf("blockStore.GetCurrentBlock error %s", err)
+	}
+	//Block store has not committed the block, or the block is the current block
+	if currBlockHeight+1 == height || (currBlockHeight == height && currBlockHash == blockHash) {
+		if len(batches) != 3 {
+			return fmt.Errorf("commit log batch count %d error", len(batches))
+		}
+		log.Infof("replay block commit log height %d hash %x", height, blockHash)
+		err = this.blockStore.CommitBatchData(batches[0])
+		if err != nil {
+			return fmt.Errorf("blockStore.CommitBatchData error %s", err)
+		}
+		err = this.stateStore.CommitBatchData(batches[1])
+		if err != nil {
+			return fmt.Errorf("stateStore.CommitBatchData error %s", err)
+		}
+		err = this.eventStore.CommitBatchData(batches[2])
+		if err != nil {
+			return fmt.Errorf("eventStore.CommitBatchData error %s", err)
+		}
+	}
+	return this.commitLog.Clear()
+}
+
 func (this *LedgerStoreImp) initCurrentBlock() error {
 	currentBlockHash, currentBlockHeight, err := this.blockStore.GetCurrentBlock()
 	if err != nil {
@@ -226,14 +300,22 @@ func (this *LedgerStoreImp) initHeaderIndexList() error {
 	return nil
 }
 
+//initStore repair the state store and event store when they disagree with block store.
+//Missing blocks are replayed, and blocks after block store are rolled back with the state history.
 func (this *LedgerStoreImp) initStore() error {
-	blockHeight := this.GetCurrentBlockHeight()
+	blockHeight, blockHash := this.GetCurrentBlock()
 
 	_, stateHeight, err := this.stateStore.GetCurrentBlock()
 	if err != nil {
 		return fmt.Errorf("stateStore.GetCurrentBlock error %s", err)
 	}
-	for i := stateHeight; i < blockHeight; i++ {
+	if stateHeight > blockHeight {
+		err = this.stateStore.RollbackTo(blockHeight)
+		if err != nil {
+			return fmt.Errorf("stateStore.RollbackTo height:%d error %s", blockHeight, err)
+		}
+	}
+	for i := stateHeight + 1; i <= blockHeight; i++ {
 		blockHash, err := this.blockStore.GetBlockHash(i)
 		if err != nil {
 			return fmt.Errorf("blockStore.GetBlockHash height:%d error:%s", i, err)
@@ -257,7 +339,13 @@ func (this *LedgerStoreImp) initStore() error {
 	if err != nil {
 		return fmt.Errorf("eventStore.GetCurrentBlock error:%s", err)
 	}
-	for i := eventHeight; i < blockHeight; i++ {
+	if eventHeight > blockHeight {
+		err = this.eventStore.RollbackTo(blockHeight, blockHash)
+		if err != nil {
+			return fmt.Errorf("eventStore.RollbackTo height:%d error %s", blockHeight, err)
+		}
+	}
+	for i := eventHeight + 1; i <= blockHeight; i++ {
 		blockHash, err := this.blockStore.GetBlockHash(i)
 		if err != nil {
 			return fmt.Errorf("blockStore.GetBlockHash height:%d error:%s", i, err)
@@ -496,7 +584,14 @@ func (this *LedgerStoreImp) verifyHeader(header *types.Header) error {
 	if prevHeader == nil {
 		return fmt.Errorf("cannot find pre header by blockHash %x", prevHeaderHash)
 	}
+	return verifyHeaderWithPrevHeader(header, prevHeader)
+}
 
+//verifyHeaderWithPrevHeader check header is the next header of prevHeader, and signed by the bookkeepers prevHeader specified
+func verifyHeaderWithPrevHeader(header, prevHeader *types.Header) error {
+	if header.PrevBlockHash != prevHeader.Hash() {
+		return fmt.Errorf("prev block hash is incorrect")
+	}
 	if prevHeader.Height+1 != header.Height {
 		return fmt.Errorf("block height is incorrect")
 	}
@@ -641,6 +736,16 @@ func (this *LedgerStoreImp) saveBlockToStateStore(block *types.Block) error {
 	blockHash := block.Hash()
 	blockHeight := block.Header.Height
 
+	if blockHeight > 0 {
+		stateRoot, err := this.stateStore.GetCurrentStateRoot()
+		if err != nil {
+			return fmt.Errorf("GetCurrentStateRoot error %s", err)
+		}
+		if block.Header.StateRoot != stateRoot {
+			return fmt.Errorf("state root %x of block is inconsistent with %x", block.Header.StateRoot, stateRoot)
+		}
+	}
+
 	stateBatch := this.stateStore.NewStateBatch()
 
 	for _, tx := range block.Transactions {
@@ -650,7 +755,20 @@ func (this *LedgerStoreImp) saveBlockToStateStore(block *types.Block) error {
 		}
 	}
 
-	err := this.stateStore.AddMerkleTreeRoot(block.Header.TransactionsRoot)
+	err := this.stateStore.SaveStateHistory(blockHeight, stateBatch)
+	if err != nil {
+		return fmt.Errorf("SaveStateHistory error %s", err)
+	}
+	err = this.stateStore.UpdateStateRoot(stateBatch)
+	if err != nil {
+		return fmt.Errorf("UpdateStateRoot error %s", err)
+	}
+	err = this.stateStore.PruneStateHistory(blockHeight)
+	if err != nil {
+		return fmt.Errorf("PruneStateHistory error %s", err)
+	}
+
+	err = this.stateStore.AddMerkleTreeRoot(block.Header.TransactionsRoot)
 	if err != nil {
 		return fmt.Errorf("AddMerkleTreeRoot error %s", err)
 	}
@@ -669,15 +787,15 @@ func (this *LedgerStoreImp) saveBlockToStateStore(block *types.Block) error {
 func (this *LedgerStoreImp) saveBlockToEventStore(block *types.Block) error {
 	blockHash := block.Hash()
 	blockHeight := block.Header.Height
-	invokeTxs := make([]common.Uint256, 0)
+	notifyTxs := make([]common.Uint256, 0)
 	for _, tx := range block.Transactions {
-		txHash := tx.Hash()
-		if tx.TxType == types.Invoke {
-			invokeTxs = append(invokeTxs, txHash)
+		//Invoke transaction and transaction paying fee may have event notifies
+		if tx.TxType == types.Invoke || tx.GetTotalFee() > 0 {
+			notifyTxs = append(notifyTxs, tx.Hash())
 		}
 	}
-	if len(invokeTxs) > 0 {
-		err := this.eventStore.SaveEventNotifyByBlock(block.Header.Height, invokeTxs)
+	if len(notifyTxs) > 0 {
+		err := this.eventStore.SaveEventNotifyByBlock(block.Header.Height, notifyTxs)
 		if err != nil {
 			return fmt.Errorf("SaveEventNotifyByBlock error %s", err)
 		}
@@ -731,6 +849,11 @@ func (this *LedgerStoreImp) saveBlock(block *types.Block) error {
 	if err != nil {
 		return fmt.Errorf("save to event store height:%d error:%s", blockHeight, err)
 	}
+	batches := [][]byte{this.blockStore.GetBatchData(), this.stateStore.GetBatchData(), this.eventStore.GetBatchData()}
+	err = this.commitLog.Write(blockHeight, blockHash, batches)
+	if err != nil {
+		return fmt.Errorf("commitLog.Write height:%d error %s", blockHeight, err)
+	}
 	err = this.blockStore.CommitTo()
 	if err != nil {
 		return fmt.Errorf("blockStore.CommitTo height:%d error %s", blockHeight, err)
@@ -743,6 +866,11 @@ func (this *LedgerStoreImp) saveBlock(block *types.Block) error {
 	if err != nil {
 		return fmt.Errorf("eventStore.CommitTo height:%d error %s", blockHeight, err)
 	}
+	err = this.commitLog.Clear()
+	if err != nil {
+		//Stale log of the current block is ignored at startup
+		log.Warnf("commitLog.Clear height:%d error %s", blockHeight, err)
+	}
 	this.setCurrentBlock(blockHeight, blockHash)
 
 	if events.DefActorPublisher != nil {
@@ -755,9 +883,99 @@ func (this *LedgerStoreImp) saveBlock(block *types.Block) error {
 	return nil
 }
 
+//RollbackTo roll back the ledger to block height. Blocks after height, with the states and event notifies of them, are removed from store.
+//Block store is rolled back first, so the state store and event store left ahead by an interruption are repaired at next startup.
+func (this *LedgerStoreImp) RollbackTo(height uint32) error {
+	if this.isSavingBlock() {
+		return fmt.Errorf("ledger is saving block")
+	}
+	defer this.resetSavingBlock()
+
+	currHeight, _ := this.GetCurrentBlock()
+	if height >= currHeight {
+		return fmt.Errorf("height %d not lower than current block height %d", height, currHeight)
+	}
+	historyFrom, err := this.stateStore.GetStateHistoryFrom()
+	if err != nil {
+		return fmt.Errorf("GetStateHistoryFrom error %s", err)
+	}
+	if height < historyFrom {
+		return fmt.Errorf("state history of height %d is not available, can roll back to height %d at least", height, historyFrom)
+	}
+	blockHash := this.GetBlockHash(height)
+
+	//Commit log of the current block must not be replayed after rollback
+	err = this.commitLog.Clear()
+	if err != nil {
+		return fmt.Errorf("commitLog.Clear error %s", err)
+	}
+	err = this.rollbackBlockStore(height, currHeight)
+	if err != nil {
+		return fmt.Errorf("rollback block store height:%d error:%s", height, err)
+	}
+	err = this.stateStore.RollbackTo(height)
+	if err != nil {
+		return fmt.Errorf("stateStore.RollbackTo height:%d error %s", height, err)
+	}
+	err = this.eventStore.RollbackTo(height, blockHash)
+	if err != nil {
+		return fmt.Errorf("eventStore.RollbackTo height:%d error %s", height, err)
+	}
+	return nil
+}
+
+func (this *LedgerStoreImp) rollbackBlockStore(height, currHeight uint32) error {
+	this.blockStore.NewBatch()
+	for i := currHeight; i > height; i-- {
+		block, err := this.GetBlockByHeight(i)
+		if err != nil {
+			return fmt.Errorf("GetBlockByHeight height:%d error %s", i, err)
+		}
+		if block == nil {
+			return fmt.Errorf("cannot find block height:%d", i)
+		}
+		this.blockStore.RemoveBlock(block)
+	}
+	blockHash := this.GetBlockHash(height)
+	err := this.blockStore.SaveCurrentBlock(height, blockHash)
+	if err != nil {
+		return fmt.Errorf("SaveCurrentBlock error %s", err)
+	}
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	//Only keep the header index list whose blocks are all not after height
+	storedIndexCount := (height + 1) / HEADER_INDEX_BATCH_SIZE * HEADER_INDEX_BATCH_SIZE
+	for startIndex := storedIndexCount; startIndex < this.storedIndexCount; startIndex += HEADER_INDEX_BATCH_SIZE {
+		this.blockStore.RemoveHeaderIndexList(startIndex)
+	}
+	err = this.blockStore.CommitTo()
+	if err != nil {
+		return fmt.Errorf("blockStore.CommitTo error %s", err)
+	}
+
+	this.storedIndexCount = storedIndexCount
+	for i := range this.headerIndex {
+		if i > height {
+			delete(this.headerIndex, i)
+		}
+	}
+	this.headerCache = make(map[common.Uint256]*ledgerCacheItem)
+	this.blockCache = make(map[common.Uint256]*ledgerCacheItem)
+	this.currBlockHeight = height
+	this.currBlockHash = blockHash
+	return nil
+}
+
 func (this *LedgerStoreImp) handleTransaction(stateBatch *statestore.StateBatch, block *types.Block, tx *types.Transaction) error {
-	var err error
 	txHash := tx.Hash()
+	//The fee is charged before execution, and kept even if the execution failed.
+	//Block including transaction failed to pay fee is invalid.
+	notifies, err := this.stateStore.HandleTransactionFee(this, stateBatch, tx, block)
+	if err != nil {
+		return fmt.Errorf("HandleTransactionFee tx %x error %s", txHash, err)
+	}
+	var execNotifies []*event.NotifyEventInfo
 	switch tx.TxType {
 	case types.Deploy:
 		err = this.stateStore.HandleDeployTransaction(stateBatch, tx)
@@ -765,14 +983,32 @@ func (this *LedgerStoreImp) handleTransaction(stateBatch *statestore.StateBatch,
 			return fmt.Errorf("HandleDeployTransaction tx %x error %s", txHash, err)
 		}
 	case types.Invoke:
-		err = this.stateStore.HandleInvokeTransaction(this, stateBatch, tx, block, this.eventStore)
+		execNotifies, err = this.stateStore.HandleInvokeTransaction(this, stateBatch, tx, block)
 		if err != nil {
 			fmt.Printf("HandleInvokeTransaction tx %x error %s \n", txHash, err)
 		}
+	case types.Record, types.DataFile:
+		//data is anchored by the transaction in block, nothing to execute
 	case types.Claim:
 	case types.Enrollment:
 	case types.Vote:
 	}
+	notifies = append(notifies, execNotifies...)
+	if len(notifies) > 0 {
+		err = this.eventStore.SaveEventNotifyByTx(txHash, notifies)
+		if err != nil {
+			return fmt.Errorf("SaveEventNotifyByTx tx %x error %s", txHash, err)
+		}
+		err = this.eventStore.SaveEventNotifyByContract(block.Header.Height, txHash, notifies)
+		if err != nil {
+			return fmt.Errorf("SaveEventNotifyByContract tx %x error %s", txHash, err)
+		}
+		event.PushSmartCodeEvent(txHash, 0, event.EVENT_NOTIFY, notifies)
+	}
+	err = this.eventStore.SaveAddressTransaction(block.Header.Height, tx, notifies)
+	if err != nil {
+		return fmt.Errorf("SaveAddressTransaction tx %x error %s", txHash, err)
+	}
 	return nil
 }
 
@@ -885,6 +1121,60 @@ func (this *LedgerStoreImp) GetMerkleProof(proofHeight, rootHeight uint32) ([]co
 	return this.stateStore.GetMerkleProof(proofHeight, rootHeight)
 }
 
+//GetConsistencyProof return the consistency proof of block merkle tree between block oldHeight and newHeight. Wrap function of StateStore.GetConsistencyProof
+func (this *LedgerStoreImp) GetConsistencyProof(oldHeight, newHeight uint32) ([]common.Uint256, error) {
+	return this.stateStore.GetConsistencyProof(oldHeight, newHeight)
+}
+
+//GetTransactionProof return the proof of transaction included in block chain, whose block position is proved in the BlockRoot of block rootHeight
+func (this *LedgerStoreImp) GetTransactionProof(txHash common.Uint256, rootHeight uint32) (*proof.TxProof, error) {
+	tx, height, err := this.GetTransaction(txHash)
+	if err != nil {
+		return nil, fmt.Errorf("GetTransaction error %s", err)
+	}
+	if tx == nil {
+		return nil, fmt.Errorf("cannot find transaction %x", txHash)
+	}
+	if rootHeight < height {
+		return nil, fmt.Errorf("root height %d is lower than block height %d", rootHeight, height)
+	}
+	block, err := this.GetBlockByHeight(height)
+	if err != nil {
+		return nil, fmt.Errorf("GetBlockByHeight error %s", err)
+	}
+	if block == nil {
+		return nil, fmt.Errorf("cannot find block of height %d", height)
+	}
+	txHashes := make([]common.Uint256, 0, len(block.Transactions))
+	txIndex := -1
+	for i, t := range block.Transactions {
+		hash := t.Hash()
+		if hash == txHash {
+			txIndex = i
+		}
+		txHashes = append(txHashes, hash)
+	}
+	if txIndex < 0 {
+		return nil, fmt.Errorf("cannot find transaction %x in block of height %d", txHash, height)
+	}
+	txPath, err := common.ComputeMerkleProof(txHashes, uint32(txIndex))
+	if err != nil {
+		return nil, fmt.Errorf("ComputeMerkleProof error %s", err)
+	}
+	blockPath, err := this.GetMerkleProof(height, rootHeight)
+	if err != nil {
+		return nil, fmt.Errorf("GetMerkleProof error %s", err)
+	}
+	return &proof.TxProof{
+		Header:     block.Header,
+		TxIndex:    uint32(txIndex),
+		TxCount:    uint32(len(txHashes)),
+		TxPath:     txPath,
+		RootHeight: rootHeight,
+		BlockPath:  blockPath,
+	}, nil
+}
+
 //GetContractState return contract by contract address. Wrap function of StateStore.GetContractState
 func (this *LedgerStoreImp) GetContractState(contractHash common.Address) (*payload.DeployCode, error) {
 	return this.stateStore.GetContractState(contractHash)
@@ -895,6 +1185,26 @@ func (this *LedgerStoreImp) GetStorageItem(key *states.StorageKey) (*states.Stor
 	return this.stateStore.GetStorageState(key)
 }
 
+//GetStorageItemAtHeight return the storage value of the key in smart contract at the block height. Wrap function of StateStore.GetStorageStateAtHeight
+func (this *LedgerStoreImp) GetStorageItemAtHeight(key *states.StorageKey, height uint32) (*states.StorageItem, error) {
+	return this.stateStore.GetStorageStateAtHeight(key, height)
+}
+
+//GetCurrentStateRoot return the state root after current block saved, which is committed in the header of next block. Wrap function of StateStore.GetCurrentStateRoot
+func (this *LedgerStoreImp) GetCurrentStateRoot() (common.Uint256, error) {
+	return this.stateStore.GetCurrentStateRoot()
+}
+
+//GetStorageProof return the proof of the storage value of key in smart contract after the block of height saved. Wrap function of StateStore.GetStorageProof
+func (this *LedgerStoreImp) GetStorageProof(key *states.StorageKey, height uint32) (*states.StorageProof, error) {
+	return this.stateStore.GetStorageProof(key, height)
+}
+
+//GetStateHistoryRange return the range of block height whose state is still queryable. Wrap function of StateStore.GetStateHistoryRange
+func (this *LedgerStoreImp) GetStateHistoryRange() (uint32, uint32, error) {
+	return this.stateStore.GetStateHistoryRange()
+}
+
 //GetEventNotifyByTx return the events notify gen by executing of smart contract.  Wrap function of EventStore.GetEventNotifyByTx
 func (this *LedgerStoreImp) GetEventNotifyByTx(tx common.Uint256) ([]*event.NotifyEventInfo, error) {
 	return this.eventStore.GetEventNotifyByTx(tx)
@@ -905,32 +1215,92 @@ func (this *LedgerStoreImp) GetEventNotifyByBlock(height uint32) ([]common.Uint2
 	return this.eventStore.GetEventNotifyByBlock(height)
 }
 
+//GetEventNotifyByContract return the event notifies of contract between startHeight and endHeight. Wrap function of EventStore.GetEventNotifyByContract
+func (this *LedgerStoreImp) GetEventNotifyByContract(contract common.Address, startHeight, endHeight uint32, eventName string, offset, limit uint32) ([]*event.ContractNotifyEventInfo, error) {
+	return this.eventStore.GetEventNotifyByContract(contract, startHeight, endHeight, eventName, offset, limit)
+}
+
+//GetTransactionsByAddress return the transactions touched the address from block height fromHeight. Wrap function of EventStore.GetTransactionsByAddress
+func (this *LedgerStoreImp) GetTransactionsByAddress(address common.Address, fromHeight uint32, limit uint32) ([]*scom.AddressTransaction, error) {
+	return this.eventStore.GetTransactionsByAddress(address, fromHeight, limit)
+}
+
+//GetContractHistory return the migration history of contract from the first version to the latest one,
+//nil if contract has never been migrated
+func (this *LedgerStoreImp) GetContractHistory(contractHash common.Address) ([]*scom.ContractVersionItem, error) {
+	first := contractHash
+	for i := 0; i < MAX_CONTRACT_HISTORY; i++ {
+		version, err := this.stateStore.GetContractVersion(first)
+		if err != nil {
+			return nil, fmt.Errorf("GetContractVersion error %s", err)
+		}
+		if version == nil {
+			if first == contractHash {
+				return nil, nil
+			}
+			break
+		}
+		if version.Predecessor == (common.Address{}) {
+			break
+		}
+		first = version.Predecessor
+	}
+	history := make([]*scom.ContractVersionItem, 0)
+	for address := first; len(history) < MAX_CONTRACT_HISTORY; {
+		version, err := this.stateStore.GetContractVersion(address)
+		if err != nil {
+			return nil, fmt.Errorf("GetContractVersion error %s", err)
+		}
+		history = append(history, &scom.ContractVersionItem{Contract: address, Version: version})
+		if version == nil || version.Successor == (common.Address{}) {
+			break
+		}
+		address = version.Successor
+	}
+	return history, nil
+}
+
 //PreExecuteContract return the result of smart contract execution without commit to store
+//The gas of execution is the same as executing the transaction in block
 func (this *LedgerStoreImp) PreExecuteContract(tx *types.Transaction) (interface{}, error) {
-	//	if tx.TxType != types.Invoke {
-	//		return nil, fmt.Errorf("transaction type error")
-	//	}
-	//	invokeCode, ok := tx.Payload.(*payload.InvokeCode)
-	//	if !ok {
-	//		return nil, fmt.Errorf("transaction type error")
-	//	}
-	//
-	//	stateBatch := this.stateStore.NewStateBatch()
-	//
-	//	stateMachine := neoservice.NewStateMachine(this, stateBatch, stypes.Application, 0)
-	//	se := neovm.NewExecutionEngine(tx, new(neovm.ECDsaCrypto), &CacheCodeTable{stateBatch}, stateMachine)
-	//	se.LoadCode(invokeCode.Code.Code, false)
-	//	err := se.Execute()
-	//	if err != nil {
-	//		return nil, err
-	//	}
-	//	if se.GetEvaluationStackCount() == 0 {
-	//		return nil, err
-	//	}
-	//	if neovm.Peek(se).GetStackItem() == nil {
-	//		return nil, err
-	//	}
-	//	return scommon.ConvertReturnTypes(neovm.Peek(se).GetStackItem()), nil
+	if tx.TxType != types.Invoke {
+		return nil, fmt.Errorf("transaction type error")
+	}
+	invoke, ok := tx.Payload.(*payload.InvokeCode)
+	if !ok {
+		return nil, fmt.Errorf("transaction payload error")
+	}
+	header, err := this.GetHeaderByHash(this.GetCurrentBlockHash())
+	if err != nil {
+		return nil, fmt.Errorf("GetHeaderByHash error %s", err)
+	}
+
+	//execute on a new state batch which is never committed
+	config := &smartcontract.Config{
+		Time:    header.Timestamp,
+		Height:  header.Height,
+		Tx:      tx,
+		DBCache: this.stateStore.NewStateBatch(),
+		Store:   this,
+	}
+	sc := smartcontract.SmartContract{
+		Config: config,
+		Gas:    smartcontract.GetGasLimit(invoke.GasLimit),
+	}
+	sc.PushContext(&context.Context{
+		Code:            invoke.Code,
+		ContractAddress: invoke.Code.AddressFromVmCode(),
+	})
+	result, err := sc.Execute()
+	if err != nil {
+		return nil, err
+	}
+	switch v := result.(type) {
+	case vmtypes.StackItems:
+		return smcom.ConvertReturnTypes(v), nil
+	case []byte:
+		return common.ToHexString(v), nil
+	}
 	return nil, nil
 }
 
@@ -949,5 +1319,11 @@ func (this *LedgerStoreImp) Close() error {
 	if err != nil {
 		return fmt.Errorf("eventStore close error %s", err)
 	}
+	if this.tempDir != "" {
+		err = os.RemoveAll(this.tempDir)
+		if err != nil {
+			return fmt.Errorf("remove temp dir error %s", err)
+		}
+	}
 	return nil
 }
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/core/payload"
 	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology-crypto/keypair"
@@ -40,6 +41,7 @@ func TestMain(m *testing.M) {
 	DBDirBlock = "test/ledger/block"
 	DBDirState = "test/ledger/states"
 	MerkleTreeStorePath = "test/ledger/merkle_tree.db"
+	BlockCommitLogPath = "test/ledger/block_commit.log"
 	testLedgerStore, err = NewLedgerStore()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "NewLedgerStore error %s\n", err)
@@ -140,3 +142,40 @@ func TestInitLedgerStoreWithGenesisBlock(t *testing.T) {
 		return
 	}
 }
+
+func TestMemoryBackendFiles(t *testing.T) {
+	backend, merklePath, commitLogPath := config.Parameters.StoreBackend, MerkleTreeStorePath, BlockCommitLogPath
+	defer func() {
+		config.Parameters.StoreBackend, MerkleTreeStorePath, BlockCommitLogPath = backend, merklePath, commitLogPath
+	}()
+	config.Parameters.StoreBackend = config.STORE_BACKEND_MEMORY
+	MerkleTreeStorePath = "test/memory/merkle_tree.db"
+	BlockCommitLogPath = "test/memory/block_commit.log"
+
+	ledgerStore, err := NewLedgerStore()
+	if err != nil {
+		t.Errorf("NewLedgerStore error %s", err)
+		return
+	}
+	tempDir := ledgerStore.tempDir
+	_, err = os.Stat(tempDir)
+	if err != nil {
+		t.Errorf("Stat temp dir error %s", err)
+		return
+	}
+	_, err = os.Stat("test/memory")
+	if !os.IsNotExist(err) {
+		t.Errorf("merkle tree and commit log of memory backend should not be saved in store dir")
+		return
+	}
+	err = ledgerStore.Close()
+	if err != nil {
+		t.Errorf("Close error %s", err)
+		return
+	}
+	_, err = os.Stat(tempDir)
+	if !os.IsNotExist(err) {
+		t.Errorf("temp dir of memory backend should be removed after closed")
+		return
+	}
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"fmt"
+
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/core/store/boltstore"
+	scom "github.com/ontio/ontology/core/store/common"
+	"github.com/ontio/ontology/core/store/leveldbstore"
+	"github.com/ontio/ontology/core/store/memstore"
+)
+
+//NewPersistStore return the persist store of the backend in config. Default backend is leveldb
+func NewPersistStore(dbDir string) (scom.PersistStore, error) {
+	switch config.Parameters.StoreBackend {
+	case "", config.STORE_BACKEND_LEVELDB:
+		store, err := leveldbstore.NewLevelDBStore(dbDir)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	case config.STORE_BACKEND_BOLTDB:
+		store, err := boltstore.NewBoltStore(dbDir + ".bolt")
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	case config.STORE_BACKEND_MEMORY:
+		return memstore.NewMemStore(), nil
+	}
+	return nil, fmt.Errorf("unknown store backend %s", config.Parameters.StoreBackend)
+}
@@ -0,0 +1,526 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"math/bits"
+	"sort"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/core/states"
+	scom "github.com/ontio/ontology/core/store/common"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/merkle"
+)
+
+const (
+	SNAPSHOT_MAGIC   = "ONTSNAP" //Magic of snapshot file
+	SNAPSHOT_VERSION = byte(2)   //Version of snapshot file format
+)
+
+//State prefixes exported in snapshot
+var SnapshotStatePrefixes = []scom.DataEntryPrefix{scom.ST_BOOKKEEPER, scom.ST_CONTRACT, scom.ST_STORAGE, scom.ST_CONTRACT_VERSION, scom.ST_VALIDATOR, scom.ST_VOTE}
+
+//ExportSnapshot write the ledger state of block height to w. Snapshot contains the genesis block, the block of height,
+//the header of height+1 committing the state root, block hashes of all heights, transaction hashes of blocks between,
+//merkle tree of block roots and the states, ending with sha256 checksum of all the data before.
+//State root of height is committed in the next block, so height must be lower than current block height.
+func (this *LedgerStoreImp) ExportSnapshot(height uint32, w io.Writer) error {
+	currHeight := this.GetCurrentBlockHeight()
+	if height == 0 || height >= currHeight {
+		return fmt.Errorf("height %d out of range [1, %d)", height, currHeight)
+	}
+	genesisBlock, err := this.GetBlockByHeight(0)
+	if err != nil {
+		return fmt.Errorf("GetBlockByHeight height:0 error %s", err)
+	}
+	block, err := this.GetBlockByHeight(height)
+	if err != nil {
+		return fmt.Errorf("GetBlockByHeight height:%d error %s", height, err)
+	}
+	if genesisBlock == nil || block == nil {
+		return fmt.Errorf("cannot find block of height 0 or %d", height)
+	}
+	nextHeader, err := this.GetHeaderByHeight(height + 1)
+	if err != nil {
+		return fmt.Errorf("GetHeaderByHeight height:%d error %s", height+1, err)
+	}
+	if nextHeader == nil {
+		return fmt.Errorf("cannot find header of height %d", height+1)
+	}
+
+	checksum := sha256.New()
+	writer := io.MultiWriter(w, checksum)
+	_, err = writer.Write([]byte(SNAPSHOT_MAGIC))
+	if err != nil {
+		return err
+	}
+	serialization.WriteByte(writer, SNAPSHOT_VERSION)
+	serialization.WriteUint32(writer, height)
+	err = genesisBlock.Serialize(writer)
+	if err != nil {
+		return fmt.Errorf("genesis block Serialize error %s", err)
+	}
+	err = block.Serialize(writer)
+	if err != nil {
+		return fmt.Errorf("block Serialize error %s", err)
+	}
+	err = nextHeader.Serialize(writer)
+	if err != nil {
+		return fmt.Errorf("next header Serialize error %s", err)
+	}
+	for i := uint32(0); i <= height; i++ {
+		blockHash := this.GetBlockHash(i)
+		err = blockHash.Serialize(writer)
+		if err != nil {
+			return err
+		}
+	}
+	err = this.exportSnapshotTxHashes(height, writer)
+	if err != nil {
+		return err
+	}
+	err = this.stateStore.ExportSnapshot(height, writer)
+	if err != nil {
+		return fmt.Errorf("stateStore.ExportSnapshot error %s", err)
+	}
+	_, err = w.Write(checksum.Sum(nil))
+	return err
+}
+
+//ImportSnapshot initialize the empty ledger store with the snapshot in r.
+//Block hashes and merkle tree are checked against the block of snapshot height, and the rebuilt state root is checked
+//against the header of next block signed by the bookkeepers of snapshot block, before any store is written.
+func (this *LedgerStoreImp) ImportSnapshot(r io.Reader) error {
+	hasInit, err := this.hasAlreadyInitGenesisBlock()
+	if err != nil {
+		return fmt.Errorf("hasAlreadyInit error %s", err)
+	}
+	if hasInit {
+		return fmt.Errorf("ledger has been initialized, snapshot can only be imported to empty ledger")
+	}
+
+	checksum := sha256.New()
+	reader := io.TeeReader(r, checksum)
+	magic := make([]byte, len(SNAPSHOT_MAGIC))
+	_, err = io.ReadFull(reader, magic)
+	if err != nil || string(magic) != SNAPSHOT_MAGIC {
+		return fmt.Errorf("not a snapshot file")
+	}
+	version, err := serialization.ReadByte(reader)
+	if err != nil {
+		return fmt.Errorf("read version error %s", err)
+	}
+	if version != SNAPSHOT_VERSION {
+		return fmt.Errorf("unsupported snapshot version %d", version)
+	}
+	height, err := serialization.ReadUint32(reader)
+	if err != nil {
+		return fmt.Errorf("read height error %s", err)
+	}
+	if height == 0 {
+		return fmt.Errorf("snapshot height cannot be 0")
+	}
+	genesisBlock := new(types.Block)
+	err = genesisBlock.Deserialize(reader)
+	if err != nil {
+		return fmt.Errorf("genesis block Deserialize error %s", err)
+	}
+	block := new(types.Block)
+	err = block.Deserialize(reader)
+	if err != nil {
+		return fmt.Errorf("block Deserialize error %s", err)
+	}
+	nextHeader := new(types.Header)
+	err = nextHeader.Deserialize(reader)
+	if err != nil {
+		return fmt.Errorf("next header Deserialize error %s", err)
+	}
+	blockHashes := make([]common.Uint256, height+1)
+	for i := range blockHashes {
+		err = blockHashes[i].Deserialize(reader)
+		if err != nil {
+			return fmt.Errorf("read block hash error %s", err)
+		}
+	}
+	if genesisBlock.Header.Height != 0 || genesisBlock.Hash() != blockHashes[0] {
+		return fmt.Errorf("genesis block hash is inconsistent with block hashes")
+	}
+	if block.Header.Height != height || block.Hash() != blockHashes[height] || block.Header.PrevBlockHash != blockHashes[height-1] {
+		return fmt.Errorf("block hash of height %d is inconsistent with block hashes", height)
+	}
+	err = verifyHeaderWithPrevHeader(nextHeader, block.Header)
+	if err != nil {
+		return fmt.Errorf("verify header of height %d error %s", height+1, err)
+	}
+	txHashes, err := importSnapshotTxHashes(height, reader)
+	if err != nil {
+		return err
+	}
+
+	merkleTree, err := serialization.ReadVarBytes(reader)
+	if err != nil {
+		return fmt.Errorf("read merkle tree error %s", err)
+	}
+	treeSize, treeHashes, err := deserializeMerkleTree(merkleTree)
+	if err != nil {
+		return fmt.Errorf("deserializeMerkleTree error %s", err)
+	}
+	if treeSize != height+1 {
+		return fmt.Errorf("merkle tree size %d is inconsistent with height %d", treeSize, height)
+	}
+	hashCount, err := serialization.ReadVarUint(reader, uint64(merkle.GetStoredHashNum(treeSize)))
+	if err != nil {
+		return fmt.Errorf("read merkle hash count error %s", err)
+	}
+	storedHashes := make([]common.Uint256, hashCount)
+	for i := range storedHashes {
+		err = storedHashes[i].Deserialize(reader)
+		if err != nil {
+			return fmt.Errorf("read merkle hash error %s", err)
+		}
+	}
+	err = verifySnapshotMerkleTree(treeSize, treeHashes, storedHashes, block.Header.BlockRoot)
+	if err != nil {
+		return fmt.Errorf("verify merkle tree error %s", err)
+	}
+
+	err = this.stateStore.ClearAll()
+	if err != nil {
+		return fmt.Errorf("stateStore.ClearAll error %s", err)
+	}
+	this.stateStore.NewBatch()
+	//State trie is rebuilt from the storage, its root must be the one committed in the header of next block
+	stateTrie := merkle.NewSparseMerkleTree(merkle.EMPTY_HASH, this.stateStore)
+	for {
+		key, err := serialization.ReadVarBytes(reader)
+		if err != nil {
+			return fmt.Errorf("read state key error %s", err)
+		}
+		if len(key) == 0 {
+			break
+		}
+		if !isSnapshotStateKey(key) {
+			return fmt.Errorf("unexpected state key %x", key)
+		}
+		value, err := serialization.ReadVarBytes(reader)
+		if err != nil {
+			return fmt.Errorf("read state value error %s", err)
+		}
+		this.stateStore.store.BatchPut(key, value)
+		if key[0] == byte(scom.ST_STORAGE) {
+			storageItem := new(states.StorageItem)
+			err = storageItem.Deserialize(bytes.NewReader(value))
+			if err != nil {
+				return fmt.Errorf("storage item of key %x Deserialize error %s", key, err)
+			}
+			err = stateTrie.Update(states.GetStorageTrieKey(key[1:]), states.GetStorageTrieValue(storageItem.Value))
+			if err != nil {
+				return fmt.Errorf("update state trie error %s", err)
+			}
+		}
+	}
+	if stateTrie.Root() != nextHeader.StateRoot {
+		return fmt.Errorf("state root %x is inconsistent with %x in header of height %d", stateTrie.Root(), nextHeader.StateRoot, height+1)
+	}
+	this.stateStore.saveStateTrie(stateTrie)
+	expect := checksum.Sum(nil)
+	actual := make([]byte, sha256.Size)
+	_, err = io.ReadFull(r, actual)
+	if err != nil {
+		return fmt.Errorf("read checksum error %s", err)
+	}
+	if !bytes.Equal(expect, actual) {
+		return fmt.Errorf("snapshot checksum mismatch")
+	}
+
+	err = this.stateStore.ImportSnapshot(height, block.Hash(), merkleTree, storedHashes)
+	if err != nil {
+		return fmt.Errorf("stateStore.ImportSnapshot error %s", err)
+	}
+	err = this.eventStore.ClearAll()
+	if err != nil {
+		return fmt.Errorf("eventStore.ClearAll error %s", err)
+	}
+	this.eventStore.NewBatch()
+	err = this.eventStore.SaveCurrentBlock(height, block.Hash())
+	if err != nil {
+		return fmt.Errorf("eventStore.SaveCurrentBlock error %s", err)
+	}
+	err = this.eventStore.CommitTo()
+	if err != nil {
+		return fmt.Errorf("eventStore.CommitTo error %s", err)
+	}
+	err = this.importSnapshotBlocks(genesisBlock, block, blockHashes, txHashes)
+	if err != nil {
+		return fmt.Errorf("import blocks error %s", err)
+	}
+	return this.init()
+}
+
+//importSnapshotBlocks save the genesis block, the block of snapshot height, block hashes and the heights of transactions between to block store.
+//Version is saved at last, so an interrupted import is cleared when init ledger store with genesis block.
+func (this *LedgerStoreImp) importSnapshotBlocks(genesisBlock, block *types.Block, blockHashes []common.Uint256, txHashes [][]common.Uint256) error {
+	err := this.blockStore.ClearAll()
+	if err != nil {
+		return fmt.Errorf("blockStore.ClearAll error %s", err)
+	}
+	height := block.Header.Height
+	this.blockStore.NewBatch()
+	err = this.blockStore.SaveBlock(genesisBlock)
+	if err != nil {
+		return fmt.Errorf("SaveBlock height:0 error %s", err)
+	}
+	err = this.blockStore.SaveBlock(block)
+	if err != nil {
+		return fmt.Errorf("SaveBlock height:%d error %s", height, err)
+	}
+	for i, blockHash := range blockHashes {
+		this.blockStore.SaveBlockHash(uint32(i), blockHash)
+	}
+	//Transactions are kept to reject the replay of them
+	for i, hashes := range txHashes {
+		for _, txHash := range hashes {
+			this.blockStore.SaveTransactionHeight(txHash, uint32(i)+1)
+		}
+	}
+	for startIndex := uint32(0); height-startIndex >= HEADER_INDEX_BATCH_SIZE; startIndex += HEADER_INDEX_BATCH_SIZE {
+		err = this.blockStore.SaveHeaderIndexList(startIndex, blockHashes[startIndex:startIndex+HEADER_INDEX_BATCH_SIZE])
+		if err != nil {
+			return fmt.Errorf("SaveHeaderIndexList start %d error %s", startIndex, err)
+		}
+	}
+	err = this.blockStore.SaveCurrentBlock(height, block.Hash())
+	if err != nil {
+		return fmt.Errorf("SaveCurrentBlock error %s", err)
+	}
+	err = this.blockStore.CommitTo()
+	if err != nil {
+		return fmt.Errorf("blockStore.CommitTo error %s", err)
+	}
+	return this.initGenesisBlock()
+}
+
+//exportSnapshotTxHashes write the transaction hashes of blocks between genesis block and the block of height
+func (this *LedgerStoreImp) exportSnapshotTxHashes(height uint32, w io.Writer) error {
+	for i := uint32(1); i < height; i++ {
+		block, err := this.GetBlockByHeight(i)
+		if err != nil {
+			return fmt.Errorf("GetBlockByHeight height:%d error %s", i, err)
+		}
+		if block == nil {
+			return fmt.Errorf("cannot find block of height %d", i)
+		}
+		err = serialization.WriteVarUint(w, uint64(len(block.Transactions)))
+		if err != nil {
+			return err
+		}
+		for _, tx := range block.Transactions {
+			txHash := tx.Hash()
+			err = txHash.Serialize(w)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+//importSnapshotTxHashes read the transaction hashes of blocks between genesis block and the block of height
+func importSnapshotTxHashes(height uint32, r io.Reader) ([][]common.Uint256, error) {
+	txHashes := make([][]common.Uint256, 0, height)
+	for i := uint32(1); i < height; i++ {
+		count, err := serialization.ReadVarUint(r, 0)
+		if err != nil {
+			return nil, fmt.Errorf("read transaction count of height %d error %s", i, err)
+		}
+		hashes := make([]common.Uint256, 0)
+		for j := uint64(0); j < count; j++ {
+			var txHash common.Uint256
+			err = txHash.Deserialize(r)
+			if err != nil {
+				return nil, fmt.Errorf("read transaction hash of height %d error %s", i, err)
+			}
+			hashes = append(hashes, txHash)
+		}
+		txHashes = append(txHashes, hashes)
+	}
+	return txHashes, nil
+}
+
+//verifySnapshotMerkleTree rebuild the merkle tree with the leaves in stored hashes,
+//and check the stored hashes, tree nodes and merkle root are all consistent with the rebuilt one.
+func verifySnapshotMerkleTree(treeSize uint32, treeHashes, storedHashes []common.Uint256, blockRoot common.Uint256) error {
+	hashStore := merkle.NewMemHashStore()
+	tree := merkle.NewTree(0, nil, hashStore)
+	pos := 0
+	for size := uint32(0); size < treeSize; size++ {
+		if pos >= len(storedHashes) {
+			return fmt.Errorf("merkle hash count %d too few", len(storedHashes))
+		}
+		tree.AppendHash(storedHashes[pos])
+		//Appending leaf to tree of size stores the leaf, and a parent hash for each trailing one bit of size
+		pos += 1 + bits.TrailingZeros32(^size)
+	}
+	if pos != len(storedHashes) {
+		return fmt.Errorf("merkle hash count %d != %d", len(storedHashes), pos)
+	}
+	for i, hash := range storedHashes {
+		storedHash, err := hashStore.GetHash(uint32(i))
+		if err != nil {
+			return err
+		}
+		if storedHash != hash {
+			return fmt.Errorf("merkle hash of position %d is inconsistent", i)
+		}
+	}
+	hashes := tree.Hashes()
+	if len(hashes) != len(treeHashes) {
+		return fmt.Errorf("merkle tree node count %d != %d", len(treeHashes), len(hashes))
+	}
+	for i, hash := range hashes {
+		if hash != treeHashes[i] {
+			return fmt.Errorf("merkle tree node %d is inconsistent", i)
+		}
+	}
+	if tree.Root() != blockRoot {
+		return fmt.Errorf("merkle root %x != block root %x", tree.Root(), blockRoot)
+	}
+	return nil
+}
+
+func isSnapshotStateKey(key []byte) bool {
+	for _, prefix := range SnapshotStatePrefixes {
+		if key[0] == byte(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+//ExportSnapshot write the merkle tree and the states of block height to w
+func (self *StateStore) ExportSnapshot(height uint32, w io.Writer) error {
+	merkleTree, err := self.getStateAtHeight(self.getMerkleTreeKey(), height)
+	if err != nil {
+		return fmt.Errorf("get merkle tree error %s", err)
+	}
+	treeSize, _, err := deserializeMerkleTree(merkleTree)
+	if err != nil {
+		return fmt.Errorf("deserializeMerkleTree error %s", err)
+	}
+	if treeSize != height+1 {
+		return fmt.Errorf("merkle tree size %d is inconsistent with height %d", treeSize, height)
+	}
+	serialization.WriteVarBytes(w, merkleTree)
+	hashCount := merkle.GetStoredHashNum(treeSize)
+	serialization.WriteVarUint(w, uint64(hashCount))
+	for pos := int64(0); pos < hashCount; pos++ {
+		hash, err := self.merkleHashStore.GetHash(uint32(pos))
+		if err != nil {
+			return fmt.Errorf("get merkle hash of position %d error %s", pos, err)
+		}
+		err = hash.Serialize(w)
+		if err != nil {
+			return err
+		}
+	}
+
+	keys, err := self.getSnapshotStateKeys(height)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		value, err := self.getStateAtHeight([]byte(key), height)
+		if err != nil {
+			return fmt.Errorf("get state %x error %s", key, err)
+		}
+		if value == nil {
+			continue
+		}
+		serialization.WriteVarBytes(w, []byte(key))
+		err = serialization.WriteVarBytes(w, value)
+		if err != nil {
+			return err
+		}
+	}
+	//Empty key is the end of states
+	return serialization.WriteVarBytes(w, nil)
+}
+
+//getSnapshotStateKeys return the sorted keys of snapshot states which exist now, or have been changed after block height
+func (self *StateStore) getSnapshotStateKeys(height uint32) ([]string, error) {
+	keySet := make(map[string]bool)
+	for _, prefix := range SnapshotStatePrefixes {
+		iter := self.store.NewIterator([]byte{byte(prefix)})
+		for iter.Next() {
+			keySet[string(iter.Key())] = true
+		}
+		iter.Release()
+	}
+	iter := self.store.NewIterator([]byte{byte(scom.IX_STATE_HISTORY)})
+	for ok := iter.Seek(self.getStateHistoryKey(height+1, nil)); ok; ok = iter.Next() {
+		stateKey := iter.Key()[5:]
+		if isSnapshotStateKey(stateKey) {
+			keySet[string(stateKey)] = true
+		}
+	}
+	iter.Release()
+
+	keys := make([]string, 0, len(keySet))
+	for key := range keySet {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+//ImportSnapshot save the merkle tree and current block of snapshot with the states in batch, and reload the merkle tree.
+//State history before snapshot height is not available.
+func (self *StateStore) ImportSnapshot(height uint32, blockHash common.Uint256, merkleTree []byte, storedHashes []common.Uint256) error {
+	self.merkleHashStore.Close()
+	hashStore, err := merkle.NewFileHashStore(self.merklePath, 0)
+	if err != nil {
+		return fmt.Errorf("NewFileHashStore error %s", err)
+	}
+	err = hashStore.Append(storedHashes)
+	if err == nil {
+		err = hashStore.Flush()
+	}
+	hashStore.Close()
+	if err != nil {
+		return fmt.Errorf("save merkle hashes error %s", err)
+	}
+
+	self.store.BatchPut(self.getMerkleTreeKey(), merkleTree)
+	self.saveStateHistoryFrom(height)
+	err = self.SaveCurrentBlock(height, blockHash)
+	if err != nil {
+		return fmt.Errorf("SaveCurrentBlock error %s", err)
+	}
+	err = self.CommitTo()
+	if err != nil {
+		return fmt.Errorf("CommitTo error %s", err)
+	}
+	return self.init(height)
+}
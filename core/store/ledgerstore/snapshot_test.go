@@ -0,0 +1,164 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/merkle"
+)
+
+func TestVerifySnapshotMerkleTree(t *testing.T) {
+	treeSize := uint32(7)
+	hashStore := merkle.NewMemHashStore()
+	tree := merkle.NewTree(0, nil, hashStore)
+	for i := uint32(0); i < treeSize; i++ {
+		tree.AppendHash(common.Uint256{byte(i + 1)})
+	}
+	storedHashes := make([]common.Uint256, 0)
+	for i := int64(0); i < merkle.GetStoredHashNum(treeSize); i++ {
+		hash, err := hashStore.GetHash(uint32(i))
+		if err != nil {
+			t.Errorf("GetHash error %s", err)
+			return
+		}
+		storedHashes = append(storedHashes, hash)
+	}
+
+	err := verifySnapshotMerkleTree(treeSize, tree.Hashes(), storedHashes, tree.Root())
+	if err != nil {
+		t.Errorf("verifySnapshotMerkleTree error %s", err)
+		return
+	}
+
+	err = verifySnapshotMerkleTree(treeSize, tree.Hashes(), storedHashes[:len(storedHashes)-1], tree.Root())
+	if err == nil {
+		t.Errorf("verifySnapshotMerkleTree should fail with missing hash")
+		return
+	}
+	storedHashes[1] = common.Uint256{0xff}
+	err = verifySnapshotMerkleTree(treeSize, tree.Hashes(), storedHashes, tree.Root())
+	if err == nil {
+		t.Errorf("verifySnapshotMerkleTree should fail with tampered hash")
+		return
+	}
+	storedHashes[1], _ = hashStore.GetHash(1)
+	err = verifySnapshotMerkleTree(treeSize, tree.Hashes(), storedHashes, common.Uint256{})
+	if err == nil {
+		t.Errorf("verifySnapshotMerkleTree should fail with wrong block root")
+		return
+	}
+}
+
+func TestVerifySnapshotNextHeader(t *testing.T) {
+	acc := account.NewAccount("")
+	bookkeepers := []keypair.PublicKey{acc.PublicKey}
+	nextBookkeeper, err := types.AddressFromBookkeepers(bookkeepers)
+	if err != nil {
+		t.Errorf("AddressFromBookkeepers error %s", err)
+		return
+	}
+	header := &types.Header{
+		Height:         10,
+		Timestamp:      100,
+		NextBookkeeper: nextBookkeeper,
+	}
+	nextHeader := &types.Header{
+		PrevBlockHash:  header.Hash(),
+		StateRoot:      common.Uint256{1},
+		Height:         11,
+		Timestamp:      101,
+		NextBookkeeper: nextBookkeeper,
+		Bookkeepers:    bookkeepers,
+	}
+	hash := nextHeader.Hash()
+	sig, err := signature.Sign(acc, hash[:])
+	if err != nil {
+		t.Errorf("Sign error %s", err)
+		return
+	}
+	nextHeader.SigData = [][]byte{sig}
+	err = verifyHeaderWithPrevHeader(nextHeader, header)
+	if err != nil {
+		t.Errorf("verifyHeaderWithPrevHeader error %s", err)
+		return
+	}
+
+	//State root in header cannot be forged without the signatures of bookkeepers
+	nextHeader.StateRoot = common.Uint256{2}
+	err = verifyHeaderWithPrevHeader(nextHeader, header)
+	if err == nil {
+		t.Errorf("verifyHeaderWithPrevHeader should fail with tampered state root")
+		return
+	}
+	nextHeader.StateRoot = common.Uint256{1}
+	nextHeader.PrevBlockHash = common.Uint256{}
+	err = verifyHeaderWithPrevHeader(nextHeader, header)
+	if err == nil {
+		t.Errorf("verifyHeaderWithPrevHeader should fail with wrong prev block hash")
+		return
+	}
+}
+
+func TestImportSnapshotTxHashes(t *testing.T) {
+	height := uint32(4)
+	txHashes := [][]common.Uint256{{{1}, {2}}, {}, {{3}}}
+	buf := bytes.NewBuffer(nil)
+	for _, hashes := range txHashes {
+		serialization.WriteVarUint(buf, uint64(len(hashes)))
+		for _, txHash := range hashes {
+			txHash.Serialize(buf)
+		}
+	}
+	data := buf.Bytes()
+	hashes, err := importSnapshotTxHashes(height, bytes.NewReader(data))
+	if err != nil {
+		t.Errorf("importSnapshotTxHashes error %s", err)
+		return
+	}
+	if len(hashes) != len(txHashes) {
+		t.Errorf("TestImportSnapshotTxHashes failed height count %d != %d", len(hashes), len(txHashes))
+		return
+	}
+	for i := range txHashes {
+		if len(hashes[i]) != len(txHashes[i]) {
+			t.Errorf("TestImportSnapshotTxHashes failed tx count of height %d %d != %d", i+1, len(hashes[i]), len(txHashes[i]))
+			return
+		}
+		for j := range txHashes[i] {
+			if hashes[i][j] != txHashes[i][j] {
+				t.Errorf("TestImportSnapshotTxHashes failed tx hash %x != %x", hashes[i][j], txHashes[i][j])
+				return
+			}
+		}
+	}
+
+	_, err = importSnapshotTxHashes(height, bytes.NewReader(data[:len(data)-1]))
+	if err == nil {
+		t.Errorf("importSnapshotTxHashes should fail with truncated data")
+		return
+	}
+}
@@ -0,0 +1,303 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/core/states"
+	scom "github.com/ontio/ontology/core/store/common"
+	"github.com/ontio/ontology/core/store/statestore"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+//StateRetention is the retention policy of historical state in state store
+type StateRetention struct {
+	Mode       string //config.STATE_RETENTION_ARCHIVE or config.STATE_RETENTION_PRUNED
+	KeepBlocks uint32 //Count of latest blocks whose state is kept queryable in pruned mode
+}
+
+//NewStateRetention return the retention policy. Empty mode means archive
+func NewStateRetention(mode string, keepBlocks uint32) (*StateRetention, error) {
+	switch mode {
+	case "", config.STATE_RETENTION_ARCHIVE:
+		return &StateRetention{Mode: config.STATE_RETENTION_ARCHIVE}, nil
+	case config.STATE_RETENTION_PRUNED:
+		return &StateRetention{Mode: mode, KeepBlocks: keepBlocks}, nil
+	}
+	return nil, fmt.Errorf("unknown state retention mode %s", mode)
+}
+
+//historyFrom return the lowest block height whose state should be queryable when current block height is height
+func (this *StateRetention) historyFrom(height uint32) uint32 {
+	if this.Mode != config.STATE_RETENTION_PRUNED || height < this.KeepBlocks {
+		return 0
+	}
+	return height - this.KeepBlocks
+}
+
+//SetStateRetention set the retention policy of historical state
+func (self *StateStore) SetStateRetention(retention *StateRetention) {
+	self.retention = retention
+}
+
+//SaveStateHistory persist the state which will be overwritten or deleted by the state batch of block, with the merkle tree, current block and state root of state store.
+//History of block height N keeps the state of block height N-1, so the state of height N-1 can be rebuilt after block N saved,
+//and it is also the undo journal to roll back block N.
+func (self *StateStore) SaveStateHistory(height uint32, stateBatch *statestore.StateBatch) error {
+	if self.retention.historyFrom(height) >= height {
+		return nil
+	}
+	keys := [][]byte{self.getMerkleTreeKey(), self.getCurrentBlockKey(), self.getCurrentStateRootKey()}
+	for k := range stateBatch.GetChangeSet() {
+		keys = append(keys, []byte(k))
+	}
+	for _, key := range keys {
+		value, err := self.store.Get(key)
+		if err != nil && err != leveldb.ErrNotFound {
+			return fmt.Errorf("get state %x error %s", key, err)
+		}
+		history := bytes.NewBuffer(nil)
+		if err == leveldb.ErrNotFound {
+			history.WriteByte(0)
+		} else {
+			history.WriteByte(1)
+			history.Write(value)
+		}
+		self.store.BatchPut(self.getStateHistoryKey(height, key), history.Bytes())
+		self.store.BatchPut(self.getStateVersionKey(key, height), nil)
+	}
+	return nil
+}
+
+//PruneStateHistory delete the historical state out of retention when current block height is height
+func (self *StateStore) PruneStateHistory(height uint32) error {
+	historyFrom, err := self.GetStateHistoryFrom()
+	if err != nil {
+		return err
+	}
+	pruneTo := self.retention.historyFrom(height)
+	if pruneTo <= historyFrom {
+		return nil
+	}
+	iter := self.store.NewIterator([]byte{byte(scom.IX_STATE_HISTORY)})
+	for iter.Next() {
+		key := iter.Key()
+		height := self.getHeightByStateHistoryKey(key)
+		if height > pruneTo {
+			break
+		}
+		self.store.BatchDelete(key)
+		self.store.BatchDelete(self.getStateVersionKey(key[5:], height))
+	}
+	iter.Release()
+
+	self.saveStateHistoryFrom(pruneTo)
+	return nil
+}
+
+//saveStateHistoryFrom persist the lowest block height whose state is still queryable to batch
+func (self *StateStore) saveStateHistoryFrom(height uint32) {
+	value := bytes.NewBuffer(nil)
+	serialization.WriteUint32(value, height)
+	self.store.BatchPut(self.getStateHistoryFromKey(), value.Bytes())
+}
+
+//initStateHistoryFrom persist current block height as the lowest queryable height if it has not been saved,
+//which means the store is saved without state history before, and the state before current block is not available
+func (self *StateStore) initStateHistoryFrom(height uint32) error {
+	_, err := self.store.Get(self.getStateHistoryFromKey())
+	if err == nil {
+		return nil
+	}
+	if err != leveldb.ErrNotFound {
+		return err
+	}
+	value := bytes.NewBuffer(nil)
+	serialization.WriteUint32(value, height)
+	return self.store.Put(self.getStateHistoryFromKey(), value.Bytes())
+}
+
+//RollbackTo undo the state history of the blocks after height, and reload the merkle tree of block height.
+//Return error if the history of those blocks has been pruned.
+func (self *StateStore) RollbackTo(height uint32) error {
+	historyFrom, currHeight, err := self.GetStateHistoryRange()
+	if err != nil {
+		return err
+	}
+	if height >= currHeight {
+		return nil
+	}
+	if height < historyFrom {
+		return fmt.Errorf("state history of height %d is not available, can roll back to height %d at least", height, historyFrom)
+	}
+
+	self.NewBatch()
+	//History is iterated in order of block height, so the first history of a key keeps its state of block height
+	restored := make(map[string]bool)
+	iter := self.store.NewIterator([]byte{byte(scom.IX_STATE_HISTORY)})
+	if !iter.Seek(self.getStateHistoryKey(height+1, nil)) || self.getHeightByStateHistoryKey(iter.Key()) != height+1 {
+		iter.Release()
+		return fmt.Errorf("state history of height %d not found", height+1)
+	}
+	for ok := true; ok; ok = iter.Next() {
+		key := iter.Key()
+		stateKey := key[5:]
+		history := iter.Value()
+		if !restored[string(stateKey)] {
+			restored[string(stateKey)] = true
+			if len(history) == 0 || history[0] == 0 {
+				self.store.BatchDelete(stateKey)
+			} else {
+				self.store.BatchPut(stateKey, history[1:])
+			}
+		}
+		self.store.BatchDelete(key)
+		self.store.BatchDelete(self.getStateVersionKey(stateKey, self.getHeightByStateHistoryKey(key)))
+	}
+	iter.Release()
+	err = self.CommitTo()
+	if err != nil {
+		return fmt.Errorf("CommitTo error %s", err)
+	}
+
+	self.merkleHashStore.Close()
+	return self.init(height)
+}
+
+//GetStorageStateAtHeight return the storage value of the key in smart contract after the block of height saved
+func (self *StateStore) GetStorageStateAtHeight(key *states.StorageKey, height uint32) (*states.StorageItem, error) {
+	storeKey, err := self.getStorageKey(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := self.getStateAtHeight(storeKey, height)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+	storageState := new(states.StorageItem)
+	err = storageState.Deserialize(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return storageState, nil
+}
+
+//getStateAtHeight return the raw state value of the key after the block of height saved, nil if the key did not exist
+func (self *StateStore) getStateAtHeight(stateKey []byte, height uint32) ([]byte, error) {
+	historyFrom, currHeight, err := self.GetStateHistoryRange()
+	if err != nil {
+		return nil, err
+	}
+	if height > currHeight {
+		return nil, fmt.Errorf("height %d larger than current block height %d", height, currHeight)
+	}
+	if height < historyFrom {
+		return nil, fmt.Errorf("state of height %d is not available, queryable from height %d", height, historyFrom)
+	}
+
+	//The first history after height keeps the state of height. If no such history, the state is not changed since height.
+	prefix := self.getStateVersionPrefix(stateKey)
+	iter := self.store.NewIterator(prefix)
+	defer iter.Release()
+	if iter.Seek(self.getStateVersionKey(stateKey, height+1)) {
+		changeHeight := binary.BigEndian.Uint32(iter.Key()[len(prefix):])
+		history, err := self.store.Get(self.getStateHistoryKey(changeHeight, stateKey))
+		if err != nil {
+			return nil, fmt.Errorf("get state history height %d error %s", changeHeight, err)
+		}
+		if len(history) == 0 || history[0] == 0 {
+			return nil, nil
+		}
+		return history[1:], nil
+	}
+
+	value, err := self.store.Get(stateKey)
+	if err != nil {
+		if err == leveldb.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return value, nil
+}
+
+//GetStateHistoryFrom return the lowest block height whose state is still queryable
+func (self *StateStore) GetStateHistoryFrom() (uint32, error) {
+	data, err := self.store.Get(self.getStateHistoryFromKey())
+	if err != nil {
+		if err == leveldb.ErrNotFound {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return serialization.ReadUint32(bytes.NewReader(data))
+}
+
+//GetStateHistoryRange return the range of block height whose state is still queryable
+func (self *StateStore) GetStateHistoryRange() (uint32, uint32, error) {
+	historyFrom, err := self.GetStateHistoryFrom()
+	if err != nil {
+		return 0, 0, err
+	}
+	_, height, err := self.GetCurrentBlock()
+	if err != nil {
+		return 0, 0, err
+	}
+	return historyFrom, height, nil
+}
+
+//getStateHistoryKey return the key of historical state. Height is big endian, so history is iterated in order of block height
+func (self *StateStore) getStateHistoryKey(height uint32, stateKey []byte) []byte {
+	key := make([]byte, 5+len(stateKey))
+	key[0] = byte(scom.IX_STATE_HISTORY)
+	binary.BigEndian.PutUint32(key[1:5], height)
+	copy(key[5:], stateKey)
+	return key
+}
+
+//getStateVersionPrefix return the key prefix of all versions of the state. State key is var bytes, so a key is never the prefix of another one
+func (self *StateStore) getStateVersionPrefix(stateKey []byte) []byte {
+	key := bytes.NewBuffer(nil)
+	key.WriteByte(byte(scom.IX_STATE_VERSION))
+	serialization.WriteVarBytes(key, stateKey)
+	return key.Bytes()
+}
+
+func (self *StateStore) getStateVersionKey(stateKey []byte, height uint32) []byte {
+	key := self.getStateVersionPrefix(stateKey)
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], height)
+	return append(key, buf[:]...)
+}
+
+func (self *StateStore) getHeightByStateHistoryKey(key []byte) uint32 {
+	return binary.BigEndian.Uint32(key[1:5])
+}
+
+func (self *StateStore) getStateHistoryFromKey() []byte {
+	return []byte{byte(scom.SYS_STATE_HISTORY_FROM)}
+}
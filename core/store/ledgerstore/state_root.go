@@ -0,0 +1,135 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"fmt"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/states"
+	scom "github.com/ontio/ontology/core/store/common"
+	"github.com/ontio/ontology/core/store/statestore"
+	"github.com/ontio/ontology/merkle"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+//GetNode return the state trie node of hash, implement merkle.SparseNodeStore.
+//Trie nodes are never deleted, so the storage of any queryable height can be proved.
+func (self *StateStore) GetNode(hash common.Uint256) ([]byte, error) {
+	data, err := self.store.Get(self.getStateTrieNodeKey(hash))
+	if err != nil {
+		if err == leveldb.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+//GetCurrentStateRoot return the root of state trie over the storage of smart contracts
+func (self *StateStore) GetCurrentStateRoot() (common.Uint256, error) {
+	data, err := self.store.Get(self.getCurrentStateRootKey())
+	if err != nil {
+		if err == leveldb.ErrNotFound {
+			return merkle.EMPTY_HASH, nil
+		}
+		return merkle.EMPTY_HASH, err
+	}
+	return common.Uint256ParseFromBytes(data)
+}
+
+//UpdateStateRoot apply the storage changes in state batch to state trie, and save the new trie nodes and state root in batch
+func (self *StateStore) UpdateStateRoot(stateBatch *statestore.StateBatch) error {
+	root, err := self.GetCurrentStateRoot()
+	if err != nil {
+		return fmt.Errorf("GetCurrentStateRoot error %s", err)
+	}
+	trie := merkle.NewSparseMerkleTree(root, self)
+	for k, v := range stateBatch.GetChangeSet() {
+		if k[0] != byte(scom.ST_STORAGE) {
+			continue
+		}
+		valueHash := merkle.EMPTY_HASH
+		if v.State != scom.Deleted {
+			storageItem, ok := v.Value.(*states.StorageItem)
+			if !ok {
+				return fmt.Errorf("invalid storage item of key %x", k)
+			}
+			valueHash = states.GetStorageTrieValue(storageItem.Value)
+		}
+		err = trie.Update(states.GetStorageTrieKey([]byte(k[1:])), valueHash)
+		if err != nil {
+			return fmt.Errorf("update state trie error %s", err)
+		}
+	}
+	self.saveStateTrie(trie)
+	return nil
+}
+
+func (self *StateStore) saveStateTrie(trie *merkle.SparseMerkleTree) {
+	for hash, node := range trie.NewNodes() {
+		self.store.BatchPut(self.getStateTrieNodeKey(hash), node)
+	}
+	root := trie.Root()
+	self.store.BatchPut(self.getCurrentStateRootKey(), root[:])
+}
+
+//GetStorageProof return the proof of the storage value of key in smart contract after the block of height saved.
+//The state root of proof is committed in the header of height+1.
+func (self *StateStore) GetStorageProof(key *states.StorageKey, height uint32) (*states.StorageProof, error) {
+	data, err := self.getStateAtHeight(self.getCurrentStateRootKey(), height)
+	if err != nil {
+		return nil, err
+	}
+	root := merkle.EMPTY_HASH
+	if data != nil {
+		root, err = common.Uint256ParseFromBytes(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	storageItem, err := self.GetStorageStateAtHeight(key, height)
+	if err != nil {
+		return nil, err
+	}
+	storeKey, err := self.getStorageKey(key)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := merkle.NewSparseMerkleTree(root, self).Prove(states.GetStorageTrieKey(storeKey[1:]))
+	if err != nil {
+		return nil, fmt.Errorf("prove storage error %s", err)
+	}
+	storageProof := &states.StorageProof{
+		StateRoot: root,
+		Proof:     proof,
+	}
+	if storageItem != nil {
+		storageProof.Value = storageItem.Value
+	}
+	return storageProof, nil
+}
+
+func (self *StateStore) getCurrentStateRootKey() []byte {
+	return []byte{byte(scom.SYS_CURRENT_STATE_ROOT)}
+}
+
+func (self *StateStore) getStateTrieNodeKey(hash common.Uint256) []byte {
+	return append([]byte{byte(scom.IX_STATE_TRIE)}, hash[:]...)
+}
@@ -23,11 +23,11 @@ import (
 	"fmt"
 
 	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/serialization"
 	"github.com/ontio/ontology/core/payload"
 	"github.com/ontio/ontology/core/states"
 	scom "github.com/ontio/ontology/core/store/common"
-	"github.com/ontio/ontology/core/store/leveldbstore"
 	"github.com/ontio/ontology/core/store/statestore"
 	"github.com/ontio/ontology/merkle"
 	"github.com/syndtr/goleveldb/leveldb"
@@ -45,12 +45,13 @@ type StateStore struct {
 	merklePath      string                    //Merkle tree store path
 	merkleTree      *merkle.CompactMerkleTree //Merkle tree of block root
 	merkleHashStore merkle.HashStore
+	retention       *StateRetention //Retention policy of historical state
 }
 
 //NewStateStore return state store instance
 func NewStateStore(dbDir, merklePath string) (*StateStore, error) {
 	var err error
-	store, err := leveldbstore.NewLevelDBStore(dbDir)
+	store, err := NewPersistStore(dbDir)
 	if err != nil {
 		return nil, err
 	}
@@ -58,6 +59,7 @@ func NewStateStore(dbDir, merklePath string) (*StateStore, error) {
 		dbDir:      dbDir,
 		store:      store,
 		merklePath: merklePath,
+		retention:  &StateRetention{Mode: config.STATE_RETENTION_ARCHIVE},
 	}
 	_, height, err := stateStore.GetCurrentBlock()
 	if err != nil {
@@ -67,6 +69,10 @@ func NewStateStore(dbDir, merklePath string) (*StateStore, error) {
 	if err != nil {
 		return nil, fmt.Errorf("init error %s", err)
 	}
+	err = stateStore.initStateHistoryFrom(height)
+	if err != nil {
+		return nil, fmt.Errorf("initStateHistoryFrom error %s", err)
+	}
 	return stateStore, nil
 }
 
@@ -101,6 +107,11 @@ func (self *StateStore) GetMerkleTree() (uint32, []common.Uint256, error) {
 		}
 		return 0, nil, err
 	}
+	return deserializeMerkleTree(data)
+}
+
+//deserializeMerkleTree return the merkle tree size and tree node in data
+func deserializeMerkleTree(data []byte) (uint32, []common.Uint256, error) {
 	value := bytes.NewBuffer(data)
 	treeSize, err := serialization.ReadUint32(value)
 	if err != nil {
@@ -150,6 +161,18 @@ func (self *StateStore) GetMerkleProof(proofHeight, rootHeight uint32) ([]common
 	return self.merkleTree.InclusionProof(proofHeight, rootHeight+1)
 }
 
+//GetConsistencyProof return the proof that the block merkle tree of block newHeight is appended from the one of block oldHeight,
+//the roots of the trees are the BlockRoot in headers of the blocks
+func (self *StateStore) GetConsistencyProof(oldHeight, newHeight uint32) ([]common.Uint256, error) {
+	if oldHeight > newHeight {
+		return nil, fmt.Errorf("old height %d is higher than new height %d", oldHeight, newHeight)
+	}
+	if newHeight >= self.merkleTree.TreeSize() {
+		return nil, fmt.Errorf("new height %d is higher than current block height", newHeight)
+	}
+	return self.merkleTree.ConsistencyProof(oldHeight+1, newHeight+1)
+}
+
 //NewStateBatch return state commit bathe. Usually using in smart contract execution
 func (self *StateStore) NewStateBatch() *statestore.StateBatch {
 	return statestore.NewStateStoreBatch(statestore.NewMemDatabase(), self.store)
@@ -160,6 +183,25 @@ func (self *StateStore) CommitTo() error {
 	return self.store.BatchCommit()
 }
 
+//GetBatchData return the serialized data of state store batch
+func (self *StateStore) GetBatchData() []byte {
+	return self.store.BatchData()
+}
+
+//CommitBatchData commit the batch data serialized by GetBatchData to store, and reload the merkle tree
+func (self *StateStore) CommitBatchData(data []byte) error {
+	err := self.store.CommitBatchData(data)
+	if err != nil {
+		return err
+	}
+	_, height, err := self.GetCurrentBlock()
+	if err != nil {
+		return fmt.Errorf("GetCurrentBlock error %s", err)
+	}
+	self.merkleHashStore.Close()
+	return self.init(height)
+}
+
 //GetContractState return contract by contract address
 func (self *StateStore) GetContractState(contractHash common.Address) (*payload.DeployCode, error) {
 	key, err := self.getContractStateKey(contractHash)
@@ -183,6 +225,24 @@ func (self *StateStore) GetContractState(contractHash common.Address) (*payload.
 	return contractState, nil
 }
 
+//GetContractVersion return the migration record of contract, nil if contract has never been migrated
+func (self *StateStore) GetContractVersion(contractHash common.Address) (*states.ContractVersion, error) {
+	key := append([]byte{byte(scom.ST_CONTRACT_VERSION)}, contractHash[:]...)
+	value, err := self.store.Get(key)
+	if err != nil {
+		if err == leveldb.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	version := new(states.ContractVersion)
+	err = version.Deserialize(bytes.NewReader(value))
+	if err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
 //GetBookkeeperState return current book keeper states
 func (self *StateStore) GetBookkeeperState() (*states.BookkeeperState, error) {
 	key, err := self.getBookkeeperKey()
@@ -19,12 +19,17 @@
 package ledgerstore
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/core/payload"
 	"github.com/ontio/ontology/core/states"
 	scommon "github.com/ontio/ontology/core/store/common"
 	"github.com/ontio/ontology/core/store/statestore"
+	"github.com/ontio/ontology/merkle"
+	"github.com/ontio/ontology/smartcontract/storage"
 	vmtypes "github.com/ontio/ontology/smartcontract/types"
 	"github.com/ontio/ontology-crypto/keypair"
 )
@@ -144,8 +149,512 @@ func TestBookkeeperState(t *testing.T) {
 	}
 }
 
+func TestStateHistory(t *testing.T) {
+	testStateStore.SetStateRetention(&StateRetention{Mode: config.STATE_RETENTION_PRUNED, KeepBlocks: 1})
+	defer testStateStore.SetStateRetention(&StateRetention{Mode: config.STATE_RETENTION_ARCHIVE})
+
+	storageKey := &states.StorageKey{Key: []byte("history")}
+	key, _ := testStateStore.getStorageKey(storageKey)
+	for height, value := range []string{"v1", "v2", "v3"} {
+		batch, err := getStateBatch()
+		if err != nil {
+			t.Errorf("NewStateBatch error %s", err)
+			return
+		}
+		batch.TryAdd(scommon.ST_STORAGE, key[1:], &states.StorageItem{Value: []byte(value)}, false)
+		err = testStateStore.SaveStateHistory(uint32(height+1), batch)
+		if err != nil {
+			t.Errorf("SaveStateHistory error %s", err)
+			return
+		}
+		err = testStateStore.PruneStateHistory(uint32(height + 1))
+		if err != nil {
+			t.Errorf("PruneStateHistory error %s", err)
+			return
+		}
+		testStateStore.SaveCurrentBlock(uint32(height+1), common.Uint256{})
+		err = batch.CommitTo()
+		if err != nil {
+			t.Errorf("batch.CommitTo error %s", err)
+			return
+		}
+		err = testStateStore.CommitTo()
+		if err != nil {
+			t.Errorf("testStateStore.CommitTo error %s", err)
+			return
+		}
+	}
+
+	historyFrom, err := testStateStore.GetStateHistoryFrom()
+	if err != nil {
+		t.Errorf("GetStateHistoryFrom error %s", err)
+		return
+	}
+	if historyFrom != 2 {
+		t.Errorf("TestStateHistory history from %d != 2", historyFrom)
+		return
+	}
+	_, err = testStateStore.store.Get(testStateStore.getStateHistoryKey(2, key))
+	if err == nil {
+		t.Errorf("TestStateHistory history of height 2 should be pruned")
+		return
+	}
+	data, err := testStateStore.store.Get(testStateStore.getStateHistoryKey(3, key))
+	if err != nil {
+		t.Errorf("TestStateHistory get history of height 3 error %s", err)
+		return
+	}
+	item := new(states.StorageItem)
+	err = item.Deserialize(bytes.NewReader(data[1:]))
+	if err != nil {
+		t.Errorf("StorageItem.Deserialize error %s", err)
+		return
+	}
+	if string(item.Value) != "v2" {
+		t.Errorf("TestStateHistory history of height 3 %s != v2", item.Value)
+		return
+	}
+
+	for height, value := range map[uint32]string{2: "v2", 3: "v3"} {
+		item, err := testStateStore.GetStorageStateAtHeight(storageKey, height)
+		if err != nil {
+			t.Errorf("GetStorageStateAtHeight height %d error %s", height, err)
+			return
+		}
+		if item == nil || string(item.Value) != value {
+			t.Errorf("TestStateHistory storage of height %d %v != %s", height, item, value)
+			return
+		}
+	}
+	_, err = testStateStore.GetStorageStateAtHeight(storageKey, 1)
+	if err == nil {
+		t.Errorf("TestStateHistory storage of height 1 should be pruned")
+		return
+	}
+}
+
+func TestInitStateHistoryFrom(t *testing.T) {
+	historyFrom, err := testStateStore.GetStateHistoryFrom()
+	if err != nil {
+		t.Errorf("GetStateHistoryFrom error %s", err)
+		return
+	}
+	err = testStateStore.initStateHistoryFrom(historyFrom + 10)
+	if err != nil {
+		t.Errorf("initStateHistoryFrom error %s", err)
+		return
+	}
+	from, err := testStateStore.GetStateHistoryFrom()
+	if err != nil {
+		t.Errorf("GetStateHistoryFrom error %s", err)
+		return
+	}
+	if from != historyFrom {
+		t.Errorf("TestInitStateHistoryFrom history from %d != %d", from, historyFrom)
+		return
+	}
+
+	err = testStateStore.store.Delete(testStateStore.getStateHistoryFromKey())
+	if err != nil {
+		t.Errorf("Delete error %s", err)
+		return
+	}
+	defer func() {
+		testStateStore.store.Delete(testStateStore.getStateHistoryFromKey())
+		testStateStore.initStateHistoryFrom(historyFrom)
+	}()
+	err = testStateStore.initStateHistoryFrom(historyFrom + 10)
+	if err != nil {
+		t.Errorf("initStateHistoryFrom error %s", err)
+		return
+	}
+	from, err = testStateStore.GetStateHistoryFrom()
+	if err != nil {
+		t.Errorf("GetStateHistoryFrom error %s", err)
+		return
+	}
+	if from != historyFrom+10 {
+		t.Errorf("TestInitStateHistoryFrom history from %d != %d", from, historyFrom+10)
+		return
+	}
+}
+
+func TestStateRollback(t *testing.T) {
+	_, currHeight, err := testStateStore.GetCurrentBlock()
+	if err != nil {
+		t.Errorf("GetCurrentBlock error %s", err)
+		return
+	}
+	storageKey := &states.StorageKey{Key: []byte("rollback")}
+	key, _ := testStateStore.getStorageKey(storageKey)
+	for i, value := range []string{"r1", "r2"} {
+		height := currHeight + uint32(i) + 1
+		batch, err := getStateBatch()
+		if err != nil {
+			t.Errorf("NewStateBatch error %s", err)
+			return
+		}
+		batch.TryAdd(scommon.ST_STORAGE, key[1:], &states.StorageItem{Value: []byte(value)}, false)
+		err = testStateStore.SaveStateHistory(height, batch)
+		if err != nil {
+			t.Errorf("SaveStateHistory error %s", err)
+			return
+		}
+		testStateStore.SaveCurrentBlock(height, common.Uint256{})
+		err = batch.CommitTo()
+		if err != nil {
+			t.Errorf("batch.CommitTo error %s", err)
+			return
+		}
+		err = testStateStore.CommitTo()
+		if err != nil {
+			t.Errorf("testStateStore.CommitTo error %s", err)
+			return
+		}
+	}
+
+	err = testStateStore.RollbackTo(currHeight + 1)
+	if err != nil {
+		t.Errorf("RollbackTo height %d error %s", currHeight+1, err)
+		return
+	}
+	item, err := testStateStore.GetStorageState(storageKey)
+	if err != nil {
+		t.Errorf("GetStorageState error %s", err)
+		return
+	}
+	if item == nil || string(item.Value) != "r1" {
+		t.Errorf("TestStateRollback storage %v != r1", item)
+		return
+	}
+
+	err = testStateStore.RollbackTo(currHeight)
+	if err != nil {
+		t.Errorf("RollbackTo height %d error %s", currHeight, err)
+		return
+	}
+	item, err = testStateStore.GetStorageState(storageKey)
+	if err != nil {
+		t.Errorf("GetStorageState error %s", err)
+		return
+	}
+	if item != nil {
+		t.Errorf("TestStateRollback storage %v should be deleted", item)
+		return
+	}
+	_, height, err := testStateStore.GetCurrentBlock()
+	if err != nil {
+		t.Errorf("GetCurrentBlock error %s", err)
+		return
+	}
+	if height != currHeight {
+		t.Errorf("TestStateRollback current block height %d != %d", height, currHeight)
+		return
+	}
+	_, err = testStateStore.store.Get(testStateStore.getStateHistoryKey(currHeight+1, key))
+	if err == nil {
+		t.Errorf("TestStateRollback history of height %d should be deleted", currHeight+1)
+		return
+	}
+
+	historyFrom, err := testStateStore.GetStateHistoryFrom()
+	if err != nil {
+		t.Errorf("GetStateHistoryFrom error %s", err)
+		return
+	}
+	if historyFrom > 0 {
+		err = testStateStore.RollbackTo(historyFrom - 1)
+		if err == nil {
+			t.Errorf("TestStateRollback rollback to height %d should fail", historyFrom-1)
+			return
+		}
+	}
+}
+
 func getStateBatch() (*statestore.StateBatch, error) {
 	testStateStore.NewBatch()
 	batch := testStateStore.NewStateBatch()
 	return batch, nil
 }
+
+func TestStorageProof(t *testing.T) {
+	_, currHeight, err := testStateStore.GetCurrentBlock()
+	if err != nil {
+		t.Errorf("GetCurrentBlock error %s", err)
+		return
+	}
+	keyA := &states.StorageKey{CodeHash: common.Address{1}, Key: []byte("proofA")}
+	keyB := &states.StorageKey{CodeHash: common.Address{1}, Key: []byte("proofB")}
+	changes := []map[*states.StorageKey]string{
+		{keyA: "a1"},
+		{keyA: "a2", keyB: "b2"},
+	}
+	roots := make([]common.Uint256, 0, len(changes))
+	for i, change := range changes {
+		height := currHeight + uint32(i) + 1
+		batch, err := getStateBatch()
+		if err != nil {
+			t.Errorf("NewStateBatch error %s", err)
+			return
+		}
+		for storageKey, value := range change {
+			key, _ := testStateStore.getStorageKey(storageKey)
+			batch.TryAdd(scommon.ST_STORAGE, key[1:], &states.StorageItem{Value: []byte(value)}, false)
+		}
+		err = testStateStore.SaveStateHistory(height, batch)
+		if err != nil {
+			t.Errorf("SaveStateHistory error %s", err)
+			return
+		}
+		err = testStateStore.UpdateStateRoot(batch)
+		if err != nil {
+			t.Errorf("UpdateStateRoot error %s", err)
+			return
+		}
+		testStateStore.SaveCurrentBlock(height, common.Uint256{})
+		err = batch.CommitTo()
+		if err != nil {
+			t.Errorf("batch.CommitTo error %s", err)
+			return
+		}
+		err = testStateStore.CommitTo()
+		if err != nil {
+			t.Errorf("testStateStore.CommitTo error %s", err)
+			return
+		}
+		root, err := testStateStore.GetCurrentStateRoot()
+		if err != nil {
+			t.Errorf("GetCurrentStateRoot error %s", err)
+			return
+		}
+		roots = append(roots, root)
+	}
+	if roots[0] == roots[1] {
+		t.Errorf("TestStorageProof state root should change")
+		return
+	}
+
+	for i, change := range changes {
+		height := currHeight + uint32(i) + 1
+		for _, storageKey := range []*states.StorageKey{keyA, keyB} {
+			proof, err := testStateStore.GetStorageProof(storageKey, height)
+			if err != nil {
+				t.Errorf("GetStorageProof error %s", err)
+				return
+			}
+			if proof.StateRoot != roots[i] {
+				t.Errorf("TestStorageProof state root of height %d %x != %x", height, proof.StateRoot, roots[i])
+				return
+			}
+			value, ok := change[storageKey]
+			if ok != (proof.Value != nil) || string(proof.Value) != value {
+				t.Errorf("TestStorageProof value of height %d %s != %s", height, proof.Value, value)
+				return
+			}
+			err = proof.Verify(storageKey.CodeHash, storageKey.Key)
+			if err != nil {
+				t.Errorf("TestStorageProof verify error %s", err)
+				return
+			}
+			proof.Value = []byte("fake")
+			err = proof.Verify(storageKey.CodeHash, storageKey.Key)
+			if err == nil {
+				t.Errorf("TestStorageProof verify should fail with fake value")
+				return
+			}
+		}
+	}
+
+	err = testStateStore.RollbackTo(currHeight + 1)
+	if err != nil {
+		t.Errorf("RollbackTo height %d error %s", currHeight+1, err)
+		return
+	}
+	root, err := testStateStore.GetCurrentStateRoot()
+	if err != nil {
+		t.Errorf("GetCurrentStateRoot error %s", err)
+		return
+	}
+	if root != roots[0] {
+		t.Errorf("TestStorageProof state root after rollback %x != %x", root, roots[0])
+		return
+	}
+}
+
+func TestConsistencyProof(t *testing.T) {
+	stateStore, err := NewStateStore("test/consistency/state", "test/consistency/merkle_tree.db")
+	if err != nil {
+		t.Errorf("NewStateStore error %s", err)
+		return
+	}
+	defer stateStore.Close()
+	stateStore.NewBatch()
+
+	blockRoots := make([]common.Uint256, 0)
+	for height := 0; height < 7; height++ {
+		txRoot := common.Uint256{byte(height + 1)}
+		blockRoots = append(blockRoots, stateStore.GetBlockRootWithNewTxRoot(txRoot))
+		err = stateStore.AddMerkleTreeRoot(txRoot)
+		if err != nil {
+			t.Errorf("AddMerkleTreeRoot error %s", err)
+			return
+		}
+	}
+
+	verifier := merkle.NewMerkleVerifier()
+	for oldHeight := uint32(0); oldHeight < 7; oldHeight++ {
+		for newHeight := oldHeight + 1; newHeight < 7; newHeight++ {
+			proof, err := stateStore.GetConsistencyProof(oldHeight, newHeight)
+			if err != nil {
+				t.Errorf("GetConsistencyProof %d %d error %s", oldHeight, newHeight, err)
+				return
+			}
+			err = verifier.VerifyConsistency(oldHeight+1, newHeight+1, blockRoots[oldHeight], blockRoots[newHeight], proof)
+			if err != nil {
+				t.Errorf("VerifyConsistency %d %d error %s", oldHeight, newHeight, err)
+				return
+			}
+		}
+	}
+
+	proof, err := stateStore.GetConsistencyProof(2, 5)
+	if err != nil {
+		t.Errorf("GetConsistencyProof error %s", err)
+		return
+	}
+	err = verifier.VerifyConsistency(3, 6, blockRoots[1], blockRoots[5], proof)
+	if err == nil {
+		t.Errorf("VerifyConsistency should fail with wrong old root")
+		return
+	}
+	_, err = stateStore.GetConsistencyProof(5, 2)
+	if err == nil {
+		t.Errorf("GetConsistencyProof should fail when old height is higher than new height")
+		return
+	}
+	_, err = stateStore.GetConsistencyProof(2, 7)
+	if err == nil {
+		t.Errorf("GetConsistencyProof should fail when new height is higher than current block height")
+		return
+	}
+}
+
+func TestStorageFind(t *testing.T) {
+	codeHash := common.Address{2}
+	getKey := func(key string) []byte {
+		storageKey, _ := testStateStore.getStorageKey(&states.StorageKey{CodeHash: codeHash, Key: []byte(key)})
+		return storageKey[1:]
+	}
+	batch, err := getStateBatch()
+	if err != nil {
+		t.Errorf("NewStateBatch error %s", err)
+		return
+	}
+	batch.TryAdd(scommon.ST_STORAGE, getKey("findA"), &states.StorageItem{Value: []byte("a1")}, false)
+	batch.TryAdd(scommon.ST_STORAGE, getKey("findB"), &states.StorageItem{Value: []byte("b1")}, false)
+	batch.TryAdd(scommon.ST_STORAGE, getKey("other"), &states.StorageItem{Value: []byte("o1")}, false)
+	err = batch.CommitTo()
+	if err != nil {
+		t.Errorf("batch.CommitTo error %s", err)
+		return
+	}
+	err = testStateStore.CommitTo()
+	if err != nil {
+		t.Errorf("testStateStore.CommitTo error %s", err)
+		return
+	}
+
+	batch, err = getStateBatch()
+	if err != nil {
+		t.Errorf("NewStateBatch error %s", err)
+		return
+	}
+	batch.TryDelete(scommon.ST_STORAGE, getKey("findA"))
+	batch.TryAdd(scommon.ST_STORAGE, getKey("findB"), &states.StorageItem{Value: []byte("b2")}, false)
+	batch.TryAdd(scommon.ST_STORAGE, getKey("findC"), &states.StorageItem{Value: []byte("c2")}, false)
+	checkFind := func(find func(scommon.DataEntryPrefix, []byte) ([]*scommon.StateItem, error), expect []string) {
+		items, err := find(scommon.ST_STORAGE, getKey("find"))
+		if err != nil {
+			t.Errorf("Find error %s", err)
+			return
+		}
+		if len(items) != len(expect) {
+			t.Errorf("TestStorageFind find %d items != %d", len(items), len(expect))
+			return
+		}
+		for i, item := range items {
+			value := string(item.Value.(*states.StorageItem).Value)
+			if value != expect[i] {
+				t.Errorf("TestStorageFind item %d value %s != %s", i, value, expect[i])
+				return
+			}
+		}
+	}
+	checkFind(batch.Find, []string{"b2", "c2"})
+
+	cache := storage.NewCloneCache(batch)
+	cache.Delete(scommon.ST_STORAGE, getKey("findB"))
+	cache.Add(scommon.ST_STORAGE, getKey("findD"), &states.StorageItem{Value: []byte("d3")})
+	cache.Add(scommon.ST_STORAGE, getKey("findA"), &states.StorageItem{Value: []byte("a3")})
+	checkFind(cache.Find, []string{"a3", "c2", "d3"})
+}
+
+func TestContractHistory(t *testing.T) {
+	v1, v2, v3 := common.Address{11}, common.Address{12}, common.Address{13}
+	batch, err := getStateBatch()
+	if err != nil {
+		t.Errorf("NewStateBatch error %s", err)
+		return
+	}
+	batch.TryAdd(scommon.ST_CONTRACT_VERSION, v1[:], &states.ContractVersion{Version: "1", Successor: v2, Forward: true}, false)
+	batch.TryAdd(scommon.ST_CONTRACT_VERSION, v2[:], &states.ContractVersion{Version: "2", Predecessor: v1, Successor: v3}, false)
+	batch.TryAdd(scommon.ST_CONTRACT_VERSION, v3[:], &states.ContractVersion{Version: "3", Predecessor: v2}, false)
+	err = batch.CommitTo()
+	if err != nil {
+		t.Errorf("batch.CommitTo error %s", err)
+		return
+	}
+	err = testStateStore.CommitTo()
+	if err != nil {
+		t.Errorf("testStateStore.CommitTo error %s", err)
+		return
+	}
+
+	version, err := testStateStore.GetContractVersion(v1)
+	if err != nil {
+		t.Errorf("GetContractVersion error %s", err)
+		return
+	}
+	if version == nil || version.Successor != v2 || !version.Forward {
+		t.Errorf("TestContractHistory version %+v error", version)
+		return
+	}
+
+	ledgerStore := &LedgerStoreImp{stateStore: testStateStore}
+	for _, contract := range []common.Address{v1, v2, v3} {
+		history, err := ledgerStore.GetContractHistory(contract)
+		if err != nil {
+			t.Errorf("GetContractHistory error %s", err)
+			return
+		}
+		if len(history) != 3 {
+			t.Errorf("TestContractHistory history length %d != 3", len(history))
+			return
+		}
+		for i, expect := range []common.Address{v1, v2, v3} {
+			if history[i].Contract != expect {
+				t.Errorf("TestContractHistory history %d contract %x != %x", i, history[i].Contract, expect)
+				return
+			}
+		}
+	}
+
+	history, err := ledgerStore.GetContractHistory(common.Address{14})
+	if err != nil {
+		t.Errorf("GetContractHistory error %s", err)
+		return
+	}
+	if history != nil {
+		t.Errorf("TestContractHistory unexpected history for unknown contract")
+	}
+}
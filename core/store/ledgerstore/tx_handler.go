@@ -21,8 +21,11 @@ package ledgerstore
 import (
 	"bytes"
 	"fmt"
+	"math/big"
 
+	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/genesis"
 	"github.com/ontio/ontology/core/payload"
 	"github.com/ontio/ontology/core/states"
 	"github.com/ontio/ontology/core/store"
@@ -32,6 +35,9 @@ import (
 	"github.com/ontio/ontology/smartcontract"
 	"github.com/ontio/ontology/smartcontract/context"
 	"github.com/ontio/ontology/smartcontract/event"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	nstates "github.com/ontio/ontology/smartcontract/service/native/states"
+	sstates "github.com/ontio/ontology/smartcontract/states"
 	stypes "github.com/ontio/ontology/smartcontract/types"
 )
 
@@ -62,17 +68,111 @@ func (self *StateStore) HandleDeployTransaction(stateBatch *statestore.StateBatc
 	return nil
 }
 
-//HandleInvokeTransaction deal with smart contract invoke transaction
-func (self *StateStore) HandleInvokeTransaction(store store.LedgerStore, stateBatch *statestore.StateBatch, tx *types.Transaction, block *types.Block, eventStore scommon.EventStore) error {
+//HandleTransactionFee charge the fee of transaction from the payers through ONG native contract, and credit it to the bookkeepers of block.
+//Return the event notifies of the ONG transfer
+func (self *StateStore) HandleTransactionFee(store store.LedgerStore, stateBatch *statestore.StateBatch, tx *types.Transaction, block *types.Block) ([]*event.NotifyEventInfo, error) {
+	if len(tx.Fee) == 0 {
+		return nil, nil
+	}
+	signers := make(map[common.Address]bool)
+	for _, addr := range tx.GetSignatureAddresses() {
+		signers[addr] = true
+	}
+	for _, fee := range tx.Fee {
+		if !signers[fee.Payer] {
+			return nil, fmt.Errorf("signature missing for payer %s", fee.Payer.ToBase58())
+		}
+	}
+	transfers, err := getFeeTransfers(tx.Fee, block.Header.Bookkeepers)
+	if err != nil {
+		return nil, err
+	}
+	if len(transfers.States) == 0 {
+		return nil, nil
+	}
+	args := new(bytes.Buffer)
+	err = transfers.Serialize(args)
+	if err != nil {
+		return nil, fmt.Errorf("Transfers Serialize error %s", err)
+	}
+	contract := &sstates.Contract{
+		Address: genesis.OngContractAddress,
+		Method:  native.TRANSFER_NAME,
+		Args:    args.Bytes(),
+	}
+	code := new(bytes.Buffer)
+	err = contract.Serialize(code)
+	if err != nil {
+		return nil, fmt.Errorf("Contract Serialize error %s", err)
+	}
+
+	sc := smartcontract.SmartContract{
+		Config: &smartcontract.Config{
+			Time:    block.Header.Timestamp,
+			Height:  block.Header.Height,
+			Tx:      tx,
+			DBCache: stateBatch,
+			Store:   store,
+		},
+	}
+	sc.PushContext(&context.Context{
+		Code:            stypes.VmCode{VmType: stypes.Native, Code: code.Bytes()},
+		ContractAddress: genesis.OngContractAddress,
+	})
+	if _, err := sc.Execute(); err != nil {
+		return nil, fmt.Errorf("ONG transfer error %s", err)
+	}
+	return sc.Notifications, nil
+}
+
+//getFeeTransfers split the fee of each payer equally to the bookkeepers, the remainder goes to the first bookkeeper
+func getFeeTransfers(fees []*types.Fee, bookkeepers []keypair.PublicKey) (*nstates.Transfers, error) {
+	transfers := &nstates.Transfers{}
+	for _, fee := range fees {
+		if fee.Amount < 0 {
+			return nil, fmt.Errorf("invalid fee amount %d of payer %s", fee.Amount, fee.Payer.ToBase58())
+		}
+		if fee.Amount == 0 {
+			continue
+		}
+		if len(bookkeepers) == 0 {
+			return nil, fmt.Errorf("no bookkeeper to receive fee")
+		}
+		count := int64(len(bookkeepers))
+		share := int64(fee.Amount) / count
+		remainder := int64(fee.Amount) % count
+		for i, bookkeeper := range bookkeepers {
+			value := share
+			if i == 0 {
+				value += remainder
+			}
+			if value == 0 {
+				continue
+			}
+			transfers.States = append(transfers.States, &nstates.State{
+				From:  fee.Payer,
+				To:    types.AddressFromPubKey(bookkeeper),
+				Value: big.NewInt(value),
+			})
+		}
+	}
+	return transfers, nil
+}
+
+//HandleInvokeTransaction deal with smart contract invoke transaction, and return the event notifies of execution
+func (self *StateStore) HandleInvokeTransaction(store store.LedgerStore, stateBatch *statestore.StateBatch, tx *types.Transaction, block *types.Block) ([]*event.NotifyEventInfo, error) {
 	invoke := tx.Payload.(*payload.InvokeCode)
-	txHash := tx.Hash()
+
+	//the changes of execution are kept in transaction cache, and dropped if execution failed, eg. out of gas.
+	//The fee has been charged to block state batch before execution.
+	txBatch := statestore.NewTxStateBatch(stateBatch)
 
 	// init smart contract configuration info
 	config := &smartcontract.Config{
 		Time:    block.Header.Timestamp,
 		Height:  block.Header.Height,
 		Tx:      tx,
-		DBCache: stateBatch,
+		DBCache: txBatch,
 		Store:   store,
 	}
 
@@ -82,9 +182,10 @@ func (self *StateStore) HandleInvokeTransaction(store store.LedgerStore, stateBa
 		ContractAddress: invoke.Code.AddressFromVmCode(),
 	}
 
-	//init smart contract info
+	//init smart contract info, the gas of execution is limited by GasLimit of transaction
 	sc := smartcontract.SmartContract{
 		Config: config,
+		Gas:    smartcontract.GetGasLimit(invoke.GasLimit),
 	}
 
 	//load current context to smart contract
@@ -92,16 +193,10 @@ func (self *StateStore) HandleInvokeTransaction(store store.LedgerStore, stateBa
 
 	//start the smart contract executive function
 	if _, err := sc.Execute(); err != nil {
-		return err
+		return nil, err
 	}
-
-	if len(sc.Notifications) > 0 {
-		if err := eventStore.SaveEventNotifyByTx(txHash, sc.Notifications); err != nil {
-			return fmt.Errorf("SaveEventNotifyByTx error %s", err)
-		}
-		event.PushSmartCodeEvent(txHash, 0, event.EVENT_NOTIFY, sc.Notifications)
-	}
-	return nil
+	txBatch.Commit()
+	return sc.Notifications, nil
 }
 
 //HandleClaimTransaction deal with ong claim transaction
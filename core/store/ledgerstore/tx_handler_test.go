@@ -0,0 +1,345 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/core/genesis"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/states"
+	scommon "github.com/ontio/ontology/core/store/common"
+	"github.com/ontio/ontology/core/store/statestore"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/core/utils"
+	"github.com/ontio/ontology/events"
+	sstates "github.com/ontio/ontology/smartcontract/states"
+	vmtypes "github.com/ontio/ontology/smartcontract/types"
+	"github.com/ontio/ontology/vm/neovm"
+	vmerr "github.com/ontio/ontology/vm/neovm/errors"
+)
+
+func TestHandleTransactionFee(t *testing.T) {
+	batch, err := getStateBatch()
+	if err != nil {
+		t.Errorf("NewStateBatch error %s", err)
+		return
+	}
+	_, payerKey, _ := keypair.GenerateKeyPair(keypair.PK_ECDSA, keypair.P256)
+	_, bookkeeper1, _ := keypair.GenerateKeyPair(keypair.PK_ECDSA, keypair.P256)
+	_, bookkeeper2, _ := keypair.GenerateKeyPair(keypair.PK_ECDSA, keypair.P256)
+	payer := types.AddressFromPubKey(payerKey)
+	batch.TryAdd(scommon.ST_STORAGE, getOngBalanceKey(payer), &states.StorageItem{Value: big.NewInt(100).Bytes()}, false)
+
+	block := &types.Block{
+		Header: &types.Header{
+			Height:      1,
+			Bookkeepers: []keypair.PublicKey{bookkeeper1, bookkeeper2},
+		},
+	}
+	newTx := func(fee common.Fixed64, feePayer common.Address) *types.Transaction {
+		return &types.Transaction{
+			TxType:  types.Invoke,
+			Payload: &payload.InvokeCode{Code: vmtypes.VmCode{VmType: vmtypes.NEOVM, Code: []byte{1}}},
+			Fee:     []*types.Fee{{Amount: fee, Payer: feePayer}},
+			Sigs:    []*types.Sig{{PubKeys: []keypair.PublicKey{payerKey}, M: 1}},
+		}
+	}
+
+	notifies, err := testStateStore.HandleTransactionFee(nil, batch, newTx(11, payer), block)
+	if err != nil {
+		t.Errorf("HandleTransactionFee error %s", err)
+		return
+	}
+	if len(notifies) != 2 {
+		t.Errorf("HandleTransactionFee notifies count %d != 2", len(notifies))
+		return
+	}
+	expects := map[common.Address]int64{
+		payer:                                89,
+		types.AddressFromPubKey(bookkeeper1): 6,
+		types.AddressFromPubKey(bookkeeper2): 5,
+	}
+	for addr, expect := range expects {
+		balance, err := getOngBalance(batch, addr)
+		if err != nil {
+			t.Errorf("getOngBalance error %s", err)
+			return
+		}
+		if balance != expect {
+			t.Errorf("balance of %s %d != %d", addr.ToBase58(), balance, expect)
+			return
+		}
+	}
+
+	_, err = testStateStore.HandleTransactionFee(nil, batch, newTx(90, payer), block)
+	if err == nil {
+		t.Errorf("HandleTransactionFee should fail with insufficient balance")
+		return
+	}
+	_, err = testStateStore.HandleTransactionFee(nil, batch, newTx(1, common.Address{9}), block)
+	if err == nil {
+		t.Errorf("HandleTransactionFee should fail when payer doesn't sign")
+		return
+	}
+	balance, err := getOngBalance(batch, payer)
+	if err != nil {
+		t.Errorf("getOngBalance error %s", err)
+		return
+	}
+	if balance != 89 {
+		t.Errorf("balance of payer %d != 89 after failed fee", balance)
+		return
+	}
+}
+
+func TestTransactionFeeEventNotify(t *testing.T) {
+	events.Init()
+	eventStore, err := NewEventStore("test/feeevent")
+	if err != nil {
+		t.Errorf("NewEventStore error %s", err)
+		return
+	}
+	defer eventStore.Close()
+	ledgerStore := &LedgerStoreImp{stateStore: testStateStore, eventStore: eventStore}
+
+	batch, err := getStateBatch()
+	if err != nil {
+		t.Errorf("NewStateBatch error %s", err)
+		return
+	}
+	_, payerKey, _ := keypair.GenerateKeyPair(keypair.PK_ECDSA, keypair.P256)
+	_, bookkeeper, _ := keypair.GenerateKeyPair(keypair.PK_ECDSA, keypair.P256)
+	payer := types.AddressFromPubKey(payerKey)
+	batch.TryAdd(scommon.ST_STORAGE, getOngBalanceKey(payer), &states.StorageItem{Value: big.NewInt(100).Bytes()}, false)
+
+	newTx := func(fee common.Fixed64) *types.Transaction {
+		return &types.Transaction{
+			TxType:  types.Deploy,
+			Payload: &payload.DeployCode{Code: vmtypes.VmCode{VmType: vmtypes.NEOVM, Code: []byte{byte(fee)}}},
+			Fee:     []*types.Fee{{Amount: fee, Payer: payer}},
+			Sigs:    []*types.Sig{{PubKeys: []keypair.PublicKey{payerKey}, M: 1}},
+		}
+	}
+	tx := newTx(10)
+	block := &types.Block{
+		Header:       &types.Header{Height: 1, Bookkeepers: []keypair.PublicKey{bookkeeper}},
+		Transactions: []*types.Transaction{tx},
+	}
+	eventStore.NewBatch()
+	err = ledgerStore.handleTransaction(batch, block, tx)
+	if err != nil {
+		t.Errorf("handleTransaction error %s", err)
+		return
+	}
+	err = ledgerStore.saveBlockToEventStore(block)
+	if err != nil {
+		t.Errorf("saveBlockToEventStore error %s", err)
+		return
+	}
+	err = eventStore.CommitTo()
+	if err != nil {
+		t.Errorf("CommitTo error %s", err)
+		return
+	}
+	txHashes, err := eventStore.GetEventNotifyByBlock(1)
+	if err != nil {
+		t.Errorf("GetEventNotifyByBlock error %s", err)
+		return
+	}
+	if len(txHashes) != 1 || txHashes[0] != tx.Hash() {
+		t.Errorf("GetEventNotifyByBlock should return the deploy transaction paying fee")
+		return
+	}
+
+	err = eventStore.RollbackTo(0, common.Uint256{})
+	if err != nil {
+		t.Errorf("RollbackTo error %s", err)
+		return
+	}
+	notifies, err := eventStore.GetEventNotifyByTx(tx.Hash())
+	if err != nil {
+		t.Errorf("GetEventNotifyByTx error %s", err)
+		return
+	}
+	if len(notifies) != 0 {
+		t.Errorf("notifies of transaction should be removed by rollback")
+		return
+	}
+
+	eventStore.NewBatch()
+	err = ledgerStore.handleTransaction(batch, block, newTx(100))
+	if err == nil {
+		t.Errorf("handleTransaction should fail with insufficient balance for fee")
+		return
+	}
+}
+
+func getOngBalanceKey(addr common.Address) []byte {
+	return append(genesis.OngContractAddress[:], addr[:]...)
+}
+
+func getOngBalance(batch *statestore.StateBatch, addr common.Address) (int64, error) {
+	item, err := batch.TryGet(scommon.ST_STORAGE, getOngBalanceKey(addr))
+	if err != nil {
+		return 0, err
+	}
+	if item == nil {
+		return 0, nil
+	}
+	return new(big.Int).SetBytes(item.Value.(*states.StorageItem).Value).Int64(), nil
+}
+
+func TestInvokeGas(t *testing.T) {
+	putCode := []byte{byte(neovm.PUSHBYTES1), 'v', byte(neovm.PUSHBYTES1), 'k'}
+	putCode = append(putCode, getSyscallCode("Neo.Storage.GetContext")...)
+	putCode = append(putCode, getSyscallCode("Neo.Storage.Put")...)
+	loopCode := append(putCode, byte(neovm.JMP), 0, 0)
+
+	block := &types.Block{
+		Header: &types.Header{Height: 1},
+	}
+	testCases := []struct {
+		code     []byte
+		gasLimit common.Fixed64
+		outOfGas bool
+	}{
+		{code: putCode, gasLimit: 1001, outOfGas: false},
+		{code: putCode, gasLimit: 1000, outOfGas: true},
+		{code: loopCode, gasLimit: 5000, outOfGas: true},
+	}
+	for i, testCase := range testCases {
+		batch, err := getStateBatch()
+		if err != nil {
+			t.Errorf("NewStateBatch error %s", err)
+			return
+		}
+		vmCode := vmtypes.VmCode{VmType: vmtypes.NEOVM, Code: testCase.code}
+		contract := vmCode.AddressFromVmCode()
+		batch.TryAdd(scommon.ST_CONTRACT, contract[:], &payload.DeployCode{Code: vmCode, NeedStorage: true}, false)
+		tx := &types.Transaction{
+			TxType:  types.Invoke,
+			Payload: &payload.InvokeCode{GasLimit: testCase.gasLimit, Code: vmCode},
+		}
+		_, err = testStateStore.HandleInvokeTransaction(nil, batch, tx, block)
+		if testCase.outOfGas {
+			if err != vmerr.ERR_OUT_OF_GAS {
+				t.Errorf("case %d HandleInvokeTransaction error %v, expect out of gas", i, err)
+				return
+			}
+		} else if err != nil {
+			t.Errorf("case %d HandleInvokeTransaction error %s", i, err)
+			return
+		}
+		item, err := batch.TryGet(scommon.ST_STORAGE, append(contract[:], 'k'))
+		if err != nil {
+			t.Errorf("TryGet error %s", err)
+			return
+		}
+		if testCase.outOfGas != (item == nil) {
+			t.Errorf("case %d storage should be rollback only when out of gas", i)
+			return
+		}
+	}
+}
+
+func TestInvokeGasNestedCall(t *testing.T) {
+	calleeCode := []byte{byte(neovm.PUSHBYTES1), 'v', byte(neovm.PUSHBYTES1), 'k'}
+	calleeCode = append(calleeCode, getSyscallCode("Neo.Storage.GetContext")...)
+	calleeCode = append(calleeCode, getSyscallCode("Neo.Storage.Put")...)
+	callee := vmtypes.VmCode{VmType: vmtypes.NEOVM, Code: calleeCode}
+	calleeAddress := callee.AddressFromVmCode()
+
+	//the caller calls callee which writes storage successfully, then loops until out of gas
+	callerCode := bytes.NewBuffer([]byte{byte(neovm.APPCALL)})
+	err := (&sstates.Contract{Address: calleeAddress}).Serialize(callerCode)
+	if err != nil {
+		t.Errorf("Contract Serialize error %s", err)
+		return
+	}
+	callerCode.Write([]byte{byte(neovm.JMP), 0, 0})
+	caller := vmtypes.VmCode{VmType: vmtypes.NEOVM, Code: callerCode.Bytes()}
+
+	batch, err := getStateBatch()
+	if err != nil {
+		t.Errorf("NewStateBatch error %s", err)
+		return
+	}
+	batch.TryAdd(scommon.ST_CONTRACT, calleeAddress[:], &payload.DeployCode{Code: callee, NeedStorage: true}, false)
+	tx := &types.Transaction{
+		TxType:  types.Invoke,
+		Payload: &payload.InvokeCode{GasLimit: 5000, Code: caller},
+	}
+	block := &types.Block{
+		Header: &types.Header{Height: 1},
+	}
+	_, err = testStateStore.HandleInvokeTransaction(nil, batch, tx, block)
+	if err != vmerr.ERR_OUT_OF_GAS {
+		t.Errorf("HandleInvokeTransaction error %v, expect out of gas", err)
+		return
+	}
+	item, err := batch.TryGet(scommon.ST_STORAGE, append(calleeAddress[:], 'k'))
+	if err != nil {
+		t.Errorf("TryGet error %s", err)
+		return
+	}
+	if item != nil {
+		t.Errorf("storage of callee should be rollback when caller is out of gas")
+		return
+	}
+}
+
+func TestDefaultInvokeGas(t *testing.T) {
+	code := []byte{byte(neovm.PUSH1), byte(neovm.PUSH2), byte(neovm.ADD)}
+	tx := utils.NewInvokeTransaction(vmtypes.VmCode{VmType: vmtypes.NEOVM, Code: code})
+
+	batch, err := getStateBatch()
+	if err != nil {
+		t.Errorf("NewStateBatch error %s", err)
+		return
+	}
+	block := &types.Block{
+		Header: &types.Header{Height: 1},
+	}
+	_, err = testStateStore.HandleInvokeTransaction(nil, batch, tx, block)
+	if err != nil {
+		t.Errorf("HandleInvokeTransaction error %s", err)
+		return
+	}
+
+	result, err := testLedgerStore.PreExecuteContract(tx)
+	if err != nil {
+		t.Errorf("PreExecuteContract error %s", err)
+		return
+	}
+	if result != "03" {
+		t.Errorf("PreExecuteContract result %v != 03", result)
+	}
+}
+
+func getSyscallCode(serviceName string) []byte {
+	buf := bytes.NewBuffer([]byte{byte(neovm.SYSCALL)})
+	serialization.WriteString(buf, serviceName)
+	return buf.Bytes()
+}
@@ -108,6 +108,21 @@ func (self *LevelDBStore) BatchCommit() error {
 	return nil
 }
 
+//BatchData return the serialized data of commit batch
+func (self *LevelDBStore) BatchData() []byte {
+	return self.batch.Dump()
+}
+
+//CommitBatchData commit the batch data serialized by BatchData to leveldb
+func (self *LevelDBStore) CommitBatchData(data []byte) error {
+	batch := new(leveldb.Batch)
+	err := batch.Load(data)
+	if err != nil {
+		return err
+	}
+	return self.db.Write(batch, nil)
+}
+
 //Close leveldb
 func (self *LevelDBStore) Close() error {
 	err := self.db.Close()
@@ -0,0 +1,144 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package memstore
+
+import (
+	"sync"
+
+	"github.com/ontio/ontology/core/store/common"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/comparer"
+	"github.com/syndtr/goleveldb/leveldb/memdb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+//In memory store, data will be lost after closed. Usually using in test
+type MemStore struct {
+	lock  sync.RWMutex
+	db    *memdb.DB //Sorted in memory key-value db
+	batch *leveldb.Batch
+}
+
+//NewMemStore return MemStore instance
+func NewMemStore() *MemStore {
+	return &MemStore{
+		db: memdb.New(comparer.DefaultComparer, 0),
+	}
+}
+
+//Put a key-value pair to store
+func (self *MemStore) Put(key []byte, value []byte) error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	return self.db.Put(key, value)
+}
+
+//Get the value of a key from store, return leveldb.ErrNotFound if the key does not exist
+func (self *MemStore) Get(key []byte) ([]byte, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.db.Get(key)
+}
+
+//Has return whether the key is exist in store
+func (self *MemStore) Has(key []byte) (bool, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.db.Contains(key), nil
+}
+
+//Delete the key in store
+func (self *MemStore) Delete(key []byte) error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	err := self.db.Delete(key)
+	if err == leveldb.ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+//NewBatch start commit batch
+func (self *MemStore) NewBatch() {
+	self.batch = new(leveldb.Batch)
+}
+
+//BatchPut put a key-value pair to batch
+func (self *MemStore) BatchPut(key []byte, value []byte) {
+	self.batch.Put(key, value)
+}
+
+//BatchDelete delete a key to batch
+func (self *MemStore) BatchDelete(key []byte) {
+	self.batch.Delete(key)
+}
+
+//BatchCommit commit batch to store
+func (self *MemStore) BatchCommit() error {
+	err := self.commitBatch(self.batch)
+	if err != nil {
+		return err
+	}
+	self.batch = nil
+	return nil
+}
+
+//BatchData return the serialized data of commit batch
+func (self *MemStore) BatchData() []byte {
+	return self.batch.Dump()
+}
+
+//CommitBatchData commit the batch data serialized by BatchData to store
+func (self *MemStore) CommitBatchData(data []byte) error {
+	batch := new(leveldb.Batch)
+	err := batch.Load(data)
+	if err != nil {
+		return err
+	}
+	return self.commitBatch(batch)
+}
+
+func (self *MemStore) commitBatch(batch *leveldb.Batch) error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	return batch.Replay(&memBatchReplay{db: self.db})
+}
+
+//Close store
+func (self *MemStore) Close() error {
+	return nil
+}
+
+//NewIterator return a iterator of store with the key perfix
+func (self *MemStore) NewIterator(prefix []byte) common.StoreIterator {
+	return self.db.NewIterator(util.BytesPrefix(prefix))
+}
+
+//memBatchReplay apply the batch operation to memdb
+type memBatchReplay struct {
+	db *memdb.DB
+}
+
+func (this *memBatchReplay) Put(key, value []byte) {
+	this.db.Put(key, value)
+}
+
+func (this *memBatchReplay) Delete(key []byte) {
+	this.db.Delete(key)
+}
@@ -21,6 +21,7 @@ package statestore
 import (
 	"bytes"
 	"fmt"
+	"sort"
 
 	"github.com/ontio/ontology/core/payload"
 	"github.com/ontio/ontology/core/states"
@@ -40,11 +41,19 @@ func NewStateStoreBatch(memoryStore common.MemoryCacheStore, store common.Persis
 	}
 }
 
+//Find returns the states of keys with the prefix in key order, the uncommitted
+//changes in batch are merged with the persisted states
 func (self *StateBatch) Find(prefix common.DataEntryPrefix, key []byte) ([]*common.StateItem, error) {
 	var states []*common.StateItem
-	iter := self.store.NewIterator(append([]byte{byte(prefix)}, key...))
+	keyPrefix := append([]byte{byte(prefix)}, key...)
+	changes := self.memoryStore.GetChangeSet()
+	iter := self.store.NewIterator(keyPrefix)
+	defer iter.Release()
 	for iter.Next() {
 		key := iter.Key()
+		if _, ok := changes[string(key)]; ok {
+			continue
+		}
 		value := iter.Value()
 		state, err := getStateObject(prefix, value)
 		if err != nil {
@@ -52,6 +61,15 @@ func (self *StateBatch) Find(prefix common.DataEntryPrefix, key []byte) ([]*comm
 		}
 		states = append(states, &common.StateItem{Key: string(key[1:]), Value: state})
 	}
+	for k, v := range changes {
+		if v.State == common.Deleted || !bytes.HasPrefix([]byte(k), keyPrefix) {
+			continue
+		}
+		states = append(states, &common.StateItem{Key: k[1:], Value: v.Value})
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Key < states[j].Key
+	})
 	return states, nil
 }
 
@@ -151,6 +169,10 @@ func (self *StateBatch) CommitTo() error {
 	return nil
 }
 
+func (self *StateBatch) GetChangeSet() map[string]*common.StateItem {
+	return self.memoryStore.GetChangeSet()
+}
+
 func (this *StateBatch) Change(prefix byte, key []byte, trie bool) {
 	this.memoryStore.Change(prefix, key, trie)
 }
@@ -180,6 +202,12 @@ func getStateObject(prefix common.DataEntryPrefix, enc []byte) (states.StateValu
 			return nil, err
 		}
 		return storage, nil
+	case common.ST_CONTRACT_VERSION:
+		version := new(states.ContractVersion)
+		if err := version.Deserialize(reader); err != nil {
+			return nil, err
+		}
+		return version, nil
 	default:
 		panic("[getStateObject] invalid state type!")
 	}
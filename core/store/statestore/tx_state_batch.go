@@ -0,0 +1,118 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package statestore
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/ontio/ontology/core/states"
+	"github.com/ontio/ontology/core/store/common"
+)
+
+//TxStateBatch is the state cache of executing one transaction on top of block state batch.
+//The changes of transaction are applied to block state batch by Commit, and dropped if execution failed.
+type TxStateBatch struct {
+	batch       common.StateStore
+	memoryStore common.MemoryCacheStore
+}
+
+func NewTxStateBatch(batch common.StateStore) *TxStateBatch {
+	return &TxStateBatch{
+		batch:       batch,
+		memoryStore: NewMemDatabase(),
+	}
+}
+
+//Find returns the states of keys with the prefix in key order, the changes of transaction are merged with block state batch
+func (self *TxStateBatch) Find(prefix common.DataEntryPrefix, key []byte) ([]*common.StateItem, error) {
+	items, err := self.batch.Find(prefix, key)
+	if err != nil {
+		return nil, err
+	}
+	var states []*common.StateItem
+	changes := self.memoryStore.GetChangeSet()
+	for _, item := range items {
+		if _, ok := changes[string(append([]byte{byte(prefix)}, item.Key...))]; !ok {
+			states = append(states, item)
+		}
+	}
+	keyPrefix := append([]byte{byte(prefix)}, key...)
+	for k, v := range changes {
+		if v.State == common.Deleted || !bytes.HasPrefix([]byte(k), keyPrefix) {
+			continue
+		}
+		states = append(states, &common.StateItem{Key: k[1:], Value: v.Value})
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Key < states[j].Key
+	})
+	return states, nil
+}
+
+func (self *TxStateBatch) TryAdd(prefix common.DataEntryPrefix, key []byte, value states.StateValue, trie bool) {
+	self.memoryStore.Put(byte(prefix), key, value, common.Changed, trie)
+}
+
+func (self *TxStateBatch) TryGetOrAdd(prefix common.DataEntryPrefix, key []byte, value states.StateValue, trie bool) error {
+	item, err := self.TryGet(prefix, key)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		self.TryAdd(prefix, key, value, trie)
+	}
+	return nil
+}
+
+func (self *TxStateBatch) TryGet(prefix common.DataEntryPrefix, key []byte) (*common.StateItem, error) {
+	state := self.memoryStore.Get(byte(prefix), key)
+	if state != nil {
+		if state.State == common.Deleted {
+			return nil, nil
+		}
+		return state, nil
+	}
+	return self.batch.TryGet(prefix, key)
+}
+
+func (self *TxStateBatch) TryGetAndChange(prefix common.DataEntryPrefix, key []byte, trie bool) (states.StateValue, error) {
+	item, err := self.TryGet(prefix, key)
+	if err != nil || item == nil {
+		return nil, err
+	}
+	self.TryAdd(prefix, key, item.Value, trie)
+	return item.Value, nil
+}
+
+func (self *TxStateBatch) TryDelete(prefix common.DataEntryPrefix, key []byte) {
+	self.memoryStore.Delete(byte(prefix), key)
+}
+
+//Commit apply the changes of transaction to block state batch
+func (self *TxStateBatch) Commit() {
+	for k, v := range self.memoryStore.GetChangeSet() {
+		prefix := common.DataEntryPrefix(k[0])
+		if v.State == common.Deleted {
+			self.batch.TryDelete(prefix, []byte(k[1:]))
+		} else {
+			self.batch.TryAdd(prefix, []byte(k[1:]), v.Value, v.Trie)
+		}
+	}
+}
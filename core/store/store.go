@@ -21,7 +21,9 @@ package store
 import (
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/proof"
 	"github.com/ontio/ontology/core/states"
+	scom "github.com/ontio/ontology/core/store/common"
 	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/smartcontract/event"
 	"github.com/ontio/ontology-crypto/keypair"
@@ -47,10 +49,19 @@ type LedgerStore interface {
 	IsContainTransaction(txHash common.Uint256) (bool, error)
 	GetBlockRootWithNewTxRoot(txRoot common.Uint256) common.Uint256
 	GetMerkleProof(m, n uint32) ([]common.Uint256, error)
+	GetTransactionProof(txHash common.Uint256, rootHeight uint32) (*proof.TxProof, error)
+	GetConsistencyProof(oldHeight, newHeight uint32) ([]common.Uint256, error)
 	GetContractState(contractHash common.Address) (*payload.DeployCode, error)
+	GetContractHistory(contractHash common.Address) ([]*scom.ContractVersionItem, error)
 	GetBookkeeperState() (*states.BookkeeperState, error)
 	GetStorageItem(key *states.StorageKey) (*states.StorageItem, error)
+	GetStorageItemAtHeight(key *states.StorageKey, height uint32) (*states.StorageItem, error)
+	GetCurrentStateRoot() (common.Uint256, error)
+	GetStorageProof(key *states.StorageKey, height uint32) (*states.StorageProof, error)
+	GetStateHistoryRange() (uint32, uint32, error)
 	PreExecuteContract(tx *types.Transaction) (interface{}, error)
 	GetEventNotifyByTx(tx common.Uint256) ([]*event.NotifyEventInfo, error)
 	GetEventNotifyByBlock(height uint32) ([]common.Uint256, error)
+	GetEventNotifyByContract(contract common.Address, startHeight, endHeight uint32, eventName string, offset, limit uint32) ([]*event.ContractNotifyEventInfo, error)
+	GetTransactionsByAddress(address common.Address, fromHeight uint32, limit uint32) ([]*scom.AddressTransaction, error)
 }
@@ -0,0 +1,236 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Package storetest is the conformance test suite of PersistStore. Every store backend should pass it
+package storetest
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/ontio/ontology/core/store/common"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+//TestPersistStore run all the conformance tests against an empty store
+func TestPersistStore(t *testing.T, store common.PersistStore) {
+	t.Run("PutGet", func(t *testing.T) { testPutGet(t, store) })
+	t.Run("Batch", func(t *testing.T) { testBatch(t, store) })
+	t.Run("BatchData", func(t *testing.T) { testBatchData(t, store) })
+	t.Run("IteratorPrefix", func(t *testing.T) { testIteratorPrefix(t, store) })
+	t.Run("IteratorSeek", func(t *testing.T) { testIteratorSeek(t, store) })
+}
+
+func testPutGet(t *testing.T, store common.PersistStore) {
+	key := []byte("putget")
+	_, err := store.Get(key)
+	if err != leveldb.ErrNotFound {
+		t.Errorf("Get missing key error %v != %s", err, leveldb.ErrNotFound)
+		return
+	}
+	err = store.Put(key, []byte("value"))
+	if err != nil {
+		t.Errorf("Put error %s", err)
+		return
+	}
+	checkValue(t, store, key, []byte("value"))
+
+	emptyKey := []byte("putget-empty")
+	err = store.Put(emptyKey, nil)
+	if err != nil {
+		t.Errorf("Put empty value error %s", err)
+		return
+	}
+	checkValue(t, store, emptyKey, []byte{})
+
+	for _, k := range [][]byte{key, emptyKey} {
+		err = store.Delete(k)
+		if err != nil {
+			t.Errorf("Delete error %s", err)
+			return
+		}
+		checkValue(t, store, k, nil)
+	}
+}
+
+func testBatch(t *testing.T, store common.PersistStore) {
+	err := store.Put([]byte("batch-delete"), []byte("value"))
+	if err != nil {
+		t.Errorf("Put error %s", err)
+		return
+	}
+	store.NewBatch()
+	store.BatchPut([]byte("batch-put"), []byte("value"))
+	store.BatchDelete([]byte("batch-delete"))
+	store.BatchPut([]byte("batch-override"), []byte("value1"))
+	store.BatchPut([]byte("batch-override"), []byte("value2"))
+	store.BatchPut([]byte("batch-put-delete"), []byte("value"))
+	store.BatchDelete([]byte("batch-put-delete"))
+
+	//Nothing is written before commit
+	checkValue(t, store, []byte("batch-put"), nil)
+	checkValue(t, store, []byte("batch-delete"), []byte("value"))
+
+	err = store.BatchCommit()
+	if err != nil {
+		t.Errorf("BatchCommit error %s", err)
+		return
+	}
+	checkValue(t, store, []byte("batch-put"), []byte("value"))
+	checkValue(t, store, []byte("batch-delete"), nil)
+	checkValue(t, store, []byte("batch-override"), []byte("value2"))
+	checkValue(t, store, []byte("batch-put-delete"), nil)
+}
+
+func testBatchData(t *testing.T, store common.PersistStore) {
+	store.NewBatch()
+	store.BatchPut([]byte("batchdata-put"), []byte("value"))
+	store.BatchDelete([]byte("batch-put"))
+	data := store.BatchData()
+	//Batch data can be committed more than once
+	for i := 0; i < 2; i++ {
+		err := store.CommitBatchData(data)
+		if err != nil {
+			t.Errorf("CommitBatchData error %s", err)
+			return
+		}
+		checkValue(t, store, []byte("batchdata-put"), []byte("value"))
+		checkValue(t, store, []byte("batch-put"), nil)
+	}
+}
+
+func testIteratorPrefix(t *testing.T, store common.PersistStore) {
+	keys := [][]byte{{0x10}, {0x10, 0x00}, {0x10, 0x01}, {0x10, 0xff}, {0x10, 0xff, 0xff}, {0x11}, {0x0f, 0xff}}
+	store.NewBatch()
+	for _, key := range keys {
+		store.BatchPut(key, key)
+	}
+	err := store.BatchCommit()
+	if err != nil {
+		t.Errorf("BatchCommit error %s", err)
+		return
+	}
+
+	expect := [][]byte{{0x10}, {0x10, 0x00}, {0x10, 0x01}, {0x10, 0xff}, {0x10, 0xff, 0xff}}
+	iter := store.NewIterator([]byte{0x10})
+	defer iter.Release()
+	result := make([][]byte, 0)
+	for iter.Next() {
+		if !bytes.Equal(iter.Key(), iter.Value()) {
+			t.Errorf("iterator key %x value %x error", iter.Key(), iter.Value())
+			return
+		}
+		result = append(result, append([]byte{}, iter.Key()...))
+	}
+	if fmt.Sprintf("%x", result) != fmt.Sprintf("%x", expect) {
+		t.Errorf("iterator keys %x != %x", result, expect)
+		return
+	}
+
+	//Iterate backward
+	for i := len(expect) - 1; iter.Prev(); i-- {
+		if i < 0 || !bytes.Equal(iter.Key(), expect[i]) {
+			t.Errorf("iterator prev key %x error", iter.Key())
+			return
+		}
+	}
+
+	if !iter.Last() || !bytes.Equal(iter.Key(), expect[len(expect)-1]) {
+		t.Errorf("iterator last key %x error", iter.Key())
+		return
+	}
+	if !iter.First() || !bytes.Equal(iter.Key(), expect[0]) {
+		t.Errorf("iterator first key %x error", iter.Key())
+		return
+	}
+}
+
+func testIteratorSeek(t *testing.T, store common.PersistStore) {
+	store.NewBatch()
+	for i := 0; i < 10; i += 2 {
+		store.BatchPut([]byte(fmt.Sprintf("seek-%d", i)), []byte{byte(i)})
+	}
+	err := store.BatchCommit()
+	if err != nil {
+		t.Errorf("BatchCommit error %s", err)
+		return
+	}
+
+	iter := store.NewIterator([]byte("seek-"))
+	defer iter.Release()
+	if iter.Prev() {
+		t.Errorf("new iterator prev should fail")
+		return
+	}
+	//Seek to the first key not less than the seek key
+	if !iter.Seek([]byte("seek-3")) || string(iter.Key()) != "seek-4" || !bytes.Equal(iter.Value(), []byte{4}) {
+		t.Errorf("seek seek-3 key %s error", iter.Key())
+		return
+	}
+	if !iter.Next() || string(iter.Key()) != "seek-6" {
+		t.Errorf("next after seek key %s error", iter.Key())
+		return
+	}
+	if !iter.Prev() || !iter.Prev() || string(iter.Key()) != "seek-2" {
+		t.Errorf("prev after seek key %s error", iter.Key())
+		return
+	}
+	if !iter.Seek([]byte("seek-4")) || string(iter.Key()) != "seek-4" {
+		t.Errorf("seek seek-4 key %s error", iter.Key())
+		return
+	}
+	//Seek key before prefix moves to the first key of prefix
+	if !iter.Seek([]byte("a")) || string(iter.Key()) != "seek-0" {
+		t.Errorf("seek key before prefix key %s error", iter.Key())
+		return
+	}
+	if iter.Seek([]byte("seek-9")) {
+		t.Errorf("seek key after prefix should fail, key %s", iter.Key())
+		return
+	}
+	if !iter.Prev() || string(iter.Key()) != "seek-8" {
+		t.Errorf("prev after seek end key %s error", iter.Key())
+		return
+	}
+}
+
+func checkValue(t *testing.T, store common.PersistStore, key, expect []byte) {
+	value, err := store.Get(key)
+	if expect == nil {
+		if err != leveldb.ErrNotFound {
+			t.Errorf("Get key %s should not be found, value %x error %v", key, value, err)
+		}
+		has, err := store.Has(key)
+		if err != nil || has {
+			t.Errorf("Has key %s %v error %v, should be false", key, has, err)
+		}
+		return
+	}
+	if err != nil {
+		t.Errorf("Get key %s error %s", key, err)
+		return
+	}
+	if !bytes.Equal(value, expect) {
+		t.Errorf("Get key %s value %x != %x", key, value, expect)
+	}
+	has, err := store.Has(key)
+	if err != nil || !has {
+		t.Errorf("Has key %s %v error %v, should be true", key, has, err)
+	}
+}
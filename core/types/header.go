@@ -35,6 +35,7 @@ type Header struct {
 	PrevBlockHash    common.Uint256
 	TransactionsRoot common.Uint256
 	BlockRoot        common.Uint256
+	StateRoot        common.Uint256 //Storage state root after executing the previous block
 	Timestamp        uint32
 	Height           uint32
 	ConsensusData    uint64
@@ -83,6 +84,7 @@ func (bd *Header) SerializeUnsigned(w io.Writer) error {
 	bd.PrevBlockHash.Serialize(w)
 	bd.TransactionsRoot.Serialize(w)
 	bd.BlockRoot.Serialize(w)
+	bd.StateRoot.Serialize(w)
 	serialization.WriteUint32(w, bd.Timestamp)
 	serialization.WriteUint32(w, bd.Height)
 	serialization.WriteUint64(w, bd.ConsensusData)
@@ -155,6 +157,11 @@ func (bd *Header) DeserializeUnsigned(r io.Reader) error {
 		return err
 	}
 
+	err = bd.StateRoot.Deserialize(r)
+	if err != nil {
+		return err
+	}
+
 	//Timestamp
 	temp, _ = serialization.ReadUint32(r)
 	bd.Timestamp = uint32(temp)
@@ -21,6 +21,7 @@ package types
 import (
 	"bytes"
 	"crypto/sha256"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
@@ -297,6 +298,12 @@ func (tx *Transaction) DeserializeUnsigned(r io.Reader) error {
 		tx.Payload = new(payload.BookKeeping)
 	case Deploy:
 		tx.Payload = new(payload.DeployCode)
+	case Record:
+		tx.Payload = new(payload.Record)
+	case DataFile:
+		tx.Payload = new(payload.DataFile)
+	case Enrollment:
+		tx.Payload = new(payload.Enrollment)
 	default:
 		return fmt.Errorf("unsupported tx type %v", tx.Type())
 	}
@@ -380,6 +387,23 @@ func (tx *Transaction) Verify() error {
 	return nil
 }
 
+//GetValidUntil returns the last block height the transaction could be included in,
+//ok is false if the transaction never expires
+func (tx *Transaction) GetValidUntil() (height uint32, ok bool) {
+	for _, attr := range tx.Attributes {
+		if attr.Usage == ValidUntil && len(attr.Data) == 4 {
+			return binary.LittleEndian.Uint32(attr.Data), true
+		}
+	}
+	return 0, false
+}
+
+//IsExpired returns whether the transaction can't be included in the block of height
+func (tx *Transaction) IsExpired(height uint32) bool {
+	validUntil, ok := tx.GetValidUntil()
+	return ok && height > validUntil
+}
+
 func (tx *Transaction) GetSysFee() common.Fixed64 {
 	return common.Fixed64(config.Parameters.SystemFee[TxName[tx.TxType]])
 }
@@ -20,6 +20,7 @@ package types
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
@@ -31,13 +32,14 @@ type TransactionAttributeUsage byte
 
 const (
 	Nonce          TransactionAttributeUsage = 0x00
+	ValidUntil     TransactionAttributeUsage = 0x01
 	Script         TransactionAttributeUsage = 0x20
 	DescriptionUrl TransactionAttributeUsage = 0x81
 	Description    TransactionAttributeUsage = 0x90
 )
 
 func IsValidAttributeType(usage TransactionAttributeUsage) bool {
-	return usage == Nonce || usage == Script ||
+	return usage == Nonce || usage == ValidUntil || usage == Script ||
 		usage == DescriptionUrl || usage == Description
 }
 
@@ -53,6 +55,14 @@ func NewTxAttribute(u TransactionAttributeUsage, d []byte) TxAttribute {
 	return tx
 }
 
+//NewValidUntilAttribute returns the attribute which makes the transaction expired after the block height
+func NewValidUntilAttribute(height uint32) *TxAttribute {
+	data := make([]byte, 4)
+	binary.LittleEndian.PutUint32(data, height)
+	attr := NewTxAttribute(ValidUntil, data)
+	return &attr
+}
+
 func (u *TxAttribute) GetSize() uint32 {
 	if u.Usage == DescriptionUrl {
 		return uint32(len([]byte{(byte(0xff))}) + len([]byte{(byte(0xff))}) + len(u.Data))
@@ -0,0 +1,49 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ontio/ontology/core/payload"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionValidUntil(t *testing.T) {
+	tx := &Transaction{
+		TxType:  Invoke,
+		Payload: &payload.InvokeCode{},
+	}
+	_, ok := tx.GetValidUntil()
+	assert.False(t, ok)
+	assert.False(t, tx.IsExpired(100))
+
+	tx.Attributes = append(tx.Attributes, NewValidUntilAttribute(100))
+	buf := new(bytes.Buffer)
+	assert.Nil(t, tx.Serialize(buf))
+	tx2 := new(Transaction)
+	assert.Nil(t, tx2.Deserialize(buf))
+
+	height, ok := tx2.GetValidUntil()
+	assert.True(t, ok)
+	assert.Equal(t, uint32(100), height)
+	assert.False(t, tx2.IsExpired(100))
+	assert.True(t, tx2.IsExpired(101))
+}
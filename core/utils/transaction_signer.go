@@ -0,0 +1,145 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/ontio/ontology/core/types"
+)
+
+// SignTransaction adds the single signature of signer to the transaction
+func SignTransaction(signer signature.Signer, tx *types.Transaction) error {
+	hash := tx.Hash()
+	sig, err := signature.Sign(signer, hash[:])
+	if err != nil {
+		return fmt.Errorf("sign transaction error %s", err)
+	}
+	tx.Sigs = append(tx.Sigs, &types.Sig{
+		PubKeys: []keypair.PublicKey{signer.PubKey()},
+		M:       1,
+		SigData: [][]byte{sig},
+	})
+	return nil
+}
+
+// MultiSignTransaction adds the signature of signer to the m-of-n multi-signature of pubKeys in the transaction,
+// the multi-signature is created if it isn't in the transaction. Co-signers can sign the transaction one by one,
+// or sign their own copies of the transaction which are merged by CombineTransactionSigs.
+func MultiSignTransaction(signer signature.Signer, m uint8, pubKeys []keypair.PublicKey, tx *types.Transaction) error {
+	addr, err := types.AddressFromMultiPubKeys(pubKeys, int(m))
+	if err != nil {
+		return err
+	}
+	signerKey := keypair.SerializePublicKey(signer.PubKey())
+	isCosigner := false
+	for _, pubKey := range pubKeys {
+		if bytes.Equal(keypair.SerializePublicKey(pubKey), signerKey) {
+			isCosigner = true
+			break
+		}
+	}
+	if !isCosigner {
+		return errors.New("signer is not in the public keys of multi-signature")
+	}
+
+	hash := tx.Hash()
+	sig, err := signature.Sign(signer, hash[:])
+	if err != nil {
+		return fmt.Errorf("sign transaction error %s", err)
+	}
+	multiSig := findMultiSig(tx, addr)
+	if multiSig == nil {
+		multiSig = &types.Sig{
+			PubKeys: pubKeys,
+			M:       m,
+		}
+		tx.Sigs = append(tx.Sigs, multiSig)
+	}
+	for _, data := range multiSig.SigData {
+		if signature.Verify(signer.PubKey(), hash[:], data) == nil {
+			return errors.New("transaction has been signed by the signer")
+		}
+	}
+	multiSig.SigData = append(multiSig.SigData, sig)
+	return nil
+}
+
+// CombineTransactionSigs merges the signatures of the copies of the same transaction signed by different signers.
+// The multi-signatures of the same address are merged to one, duplicate signature data are ignored.
+func CombineTransactionSigs(txs []*types.Transaction) (*types.Transaction, error) {
+	if len(txs) == 0 {
+		return nil, errors.New("no transaction to combine")
+	}
+	tx := txs[0]
+	hash := tx.Hash()
+	for _, other := range txs[1:] {
+		if other.Hash() != hash {
+			return nil, fmt.Errorf("transaction %x is different from %x", other.Hash(), hash)
+		}
+		for _, sig := range other.Sigs {
+			addr, err := getSigAddress(sig)
+			if err != nil {
+				return nil, err
+			}
+			exist := findMultiSig(tx, addr)
+			if exist == nil {
+				tx.Sigs = append(tx.Sigs, sig)
+				continue
+			}
+			for _, data := range sig.SigData {
+				if !containsSigData(exist.SigData, data) {
+					exist.SigData = append(exist.SigData, data)
+				}
+			}
+		}
+	}
+	return tx, nil
+}
+
+func getSigAddress(sig *types.Sig) (common.Address, error) {
+	if len(sig.PubKeys) == 1 {
+		return types.AddressFromPubKey(sig.PubKeys[0]), nil
+	}
+	return types.AddressFromMultiPubKeys(sig.PubKeys, int(sig.M))
+}
+
+func findMultiSig(tx *types.Transaction, addr common.Address) *types.Sig {
+	for _, sig := range tx.Sigs {
+		sigAddr, err := getSigAddress(sig)
+		if err == nil && sigAddr == addr {
+			return sig
+		}
+	}
+	return nil
+}
+
+func containsSigData(sigData [][]byte, data []byte) bool {
+	for _, d := range sigData {
+		if bytes.Equal(d, data) {
+			return true
+		}
+	}
+	return false
+}
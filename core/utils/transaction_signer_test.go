@@ -0,0 +1,84 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/ontio/ontology/core/types"
+	stypes "github.com/ontio/ontology/smartcontract/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiSignTransaction(t *testing.T) {
+	acc1 := account.NewAccount("")
+	acc2 := account.NewAccount("")
+	acc3 := account.NewAccount("")
+	pubKeys := []keypair.PublicKey{acc1.PubKey(), acc2.PubKey(), acc3.PubKey()}
+
+	tx := NewInvokeTransaction(stypes.VmCode{VmType: stypes.Native, Code: []byte{1, 2, 3}})
+	hash := tx.Hash()
+
+	err := MultiSignTransaction(acc1, 2, pubKeys, tx)
+	assert.Nil(t, err)
+	err = MultiSignTransaction(acc1, 2, pubKeys, tx)
+	assert.NotNil(t, err, "sign twice by the same signer")
+	err = MultiSignTransaction(account.NewAccount(""), 2, pubKeys, tx)
+	assert.NotNil(t, err, "sign by signer out of the public keys")
+
+	assert.Equal(t, 1, len(tx.Sigs))
+	assert.NotNil(t, signature.VerifyMultiSignature(hash[:], pubKeys, 2, tx.Sigs[0].SigData))
+
+	err = MultiSignTransaction(acc3, 2, pubKeys, tx)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(tx.Sigs))
+	assert.Nil(t, signature.VerifyMultiSignature(hash[:], pubKeys, 2, tx.Sigs[0].SigData))
+}
+
+func TestCombineTransactionSigs(t *testing.T) {
+	acc1 := account.NewAccount("")
+	acc2 := account.NewAccount("")
+	acc3 := account.NewAccount("")
+	pubKeys := []keypair.PublicKey{acc1.PubKey(), acc2.PubKey(), acc3.PubKey()}
+
+	newTx := func() *types.Transaction {
+		return NewInvokeTransaction(stypes.VmCode{VmType: stypes.Native, Code: []byte{1, 2, 3}})
+	}
+	tx1, tx2, tx3 := newTx(), newTx(), newTx()
+	assert.Nil(t, MultiSignTransaction(acc1, 3, pubKeys, tx1))
+	assert.Nil(t, MultiSignTransaction(acc2, 3, pubKeys, tx2))
+	assert.Nil(t, MultiSignTransaction(acc3, 3, pubKeys, tx3))
+	payer := account.NewAccount("")
+	assert.Nil(t, SignTransaction(payer, tx3))
+
+	tx, err := CombineTransactionSigs([]*types.Transaction{tx1, tx2, tx3, tx2})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(tx.Sigs))
+	hash := tx.Hash()
+	assert.Equal(t, 3, len(tx.Sigs[0].SigData))
+	assert.Nil(t, signature.VerifyMultiSignature(hash[:], pubKeys, 3, tx.Sigs[0].SigData))
+	assert.Nil(t, signature.Verify(payer.PubKey(), hash[:], tx.Sigs[1].SigData[0]))
+
+	other := NewInvokeTransaction(stypes.VmCode{VmType: stypes.Native, Code: []byte{4, 5, 6}})
+	_, err = CombineTransactionSigs([]*types.Transaction{tx1, other})
+	assert.NotNil(t, err)
+}
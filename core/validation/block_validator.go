@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/core/ledger"
 	"github.com/ontio/ontology/core/signature"
 	"github.com/ontio/ontology/core/types"
@@ -78,6 +79,8 @@ func VerifyBlock(block *types.Block, ld *ledger.Ledger, completely bool) error {
 				return errors.New(fmt.Sprintf("Bookkeeper is not validate."))
 			}
 		*/
+		//the fees of earlier transactions in block are pending for the later ones
+		pendingFees := make(map[common.Address]common.Fixed64)
 		for _, txVerify := range block.Transactions {
 			if errCode := VerifyTransaction(txVerify); errCode != ontErrors.ErrNoError {
 				return errors.New(fmt.Sprintf("VerifyTransaction failed when verifiy block"))
@@ -86,6 +89,11 @@ func VerifyBlock(block *types.Block, ld *ledger.Ledger, completely bool) error {
 			if errCode := VerifyTransactionWithLedger(txVerify, ld); errCode != ontErrors.ErrNoError {
 				return errors.New(fmt.Sprintf("VerifyTransaction failed when verifiy block"))
 			}
+
+			if errCode := VerifyFeeBalance(txVerify, ld, pendingFees); errCode != ontErrors.ErrNoError {
+				return errors.New(fmt.Sprintf("VerifyFeeBalance failed when verifiy block"))
+			}
+			AddPendingFees(pendingFees, txVerify)
 		}
 	}
 
@@ -21,9 +21,11 @@ package validation
 import (
 	"errors"
 	"fmt"
+	"math/big"
 
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/core/genesis"
 	"github.com/ontio/ontology/core/ledger"
 	"github.com/ontio/ontology/core/payload"
 	"github.com/ontio/ontology/core/signature"
@@ -43,14 +45,121 @@ func VerifyTransaction(tx *types.Transaction) ontErrors.ErrCode {
 		return ontErrors.ErrTransactionPayload
 	}
 
+	if err := checkTransactionFee(tx); err != nil {
+		log.Warn("[VerifyTransaction],", err)
+		return ontErrors.ErrInsufficientFee
+	}
+
+	if err := checkTransactionAttributes(tx); err != nil {
+		log.Warn("[VerifyTransaction],", err)
+		return ontErrors.ErrAttributeProgram
+	}
+
 	return ontErrors.ErrNoError
 }
 
+// VerifyTransactionWithLedger verifys the transaction with the state of ledger
 func VerifyTransactionWithLedger(tx *types.Transaction, ledger *ledger.Ledger) ontErrors.ErrCode {
-	//TODO: replay check
+	//the transaction will be included in the next block at the earliest
+	if height := ledger.GetCurrentBlockHeight() + 1; tx.IsExpired(height) {
+		validUntil, _ := tx.GetValidUntil()
+		log.Warnf("[VerifyTransactionWithLedger], transaction %x is valid until height %d, next height %d",
+			tx.Hash(), validUntil, height)
+		return ontErrors.ErrTxExpired
+	}
+	if err := checkFeeBalance(tx, ledger, nil); err != nil {
+		log.Warn("[VerifyTransactionWithLedger],", err)
+		return ontErrors.ErrInsufficientBalance
+	}
+	return ontErrors.ErrNoError
+}
+
+// VerifyFeeBalance verifys the payers of transaction can afford its fee besides the pending fees,
+// which are paid by the payers in other transactions not included in ledger yet
+func VerifyFeeBalance(tx *types.Transaction, ledger *ledger.Ledger, pending map[common.Address]common.Fixed64) ontErrors.ErrCode {
+	if err := checkFeeBalance(tx, ledger, pending); err != nil {
+		log.Warn("[VerifyFeeBalance],", err)
+		return ontErrors.ErrInsufficientBalance
+	}
 	return ontErrors.ErrNoError
 }
 
+// AddPendingFees adds the fee of transaction to the pending fees of its payers
+func AddPendingFees(pending map[common.Address]common.Fixed64, tx *types.Transaction) {
+	for _, fee := range tx.Fee {
+		pending[fee.Payer] += fee.Amount
+	}
+}
+
+// checkTransactionFee check the fee paid by payers covers the system fee, network fee and gas limit of invoke
+func checkTransactionFee(tx *types.Transaction) error {
+	for _, fee := range tx.Fee {
+		if fee.Amount < 0 {
+			return fmt.Errorf("invalid fee amount %d of payer %s", fee.Amount, fee.Payer.ToBase58())
+		}
+	}
+	if tx.NetWorkFee < 0 {
+		return fmt.Errorf("invalid network fee %d", tx.NetWorkFee)
+	}
+	required := tx.GetSysFee() + tx.GetNetworkFee()
+	if invoke, ok := tx.Payload.(*payload.InvokeCode); ok {
+		if invoke.GasLimit < 0 {
+			return fmt.Errorf("invalid gas limit %d", invoke.GasLimit)
+		}
+		required += invoke.GasLimit
+	}
+	if tx.GetTotalFee() < required {
+		return fmt.Errorf("fee %d is lower than required %d", tx.GetTotalFee(), required)
+	}
+	return nil
+}
+
+// checkFeeBalance check every payer has enough ONG to pay its fee and pending fee
+func checkFeeBalance(tx *types.Transaction, ledger *ledger.Ledger, pending map[common.Address]common.Fixed64) error {
+	fees := make(map[common.Address]*big.Int)
+	for _, fee := range tx.Fee {
+		amount, ok := fees[fee.Payer]
+		if !ok {
+			amount = new(big.Int)
+			fees[fee.Payer] = amount
+		}
+		amount.Add(amount, big.NewInt(int64(fee.Amount)))
+	}
+	for payer, amount := range fees {
+		if amount.Sign() == 0 {
+			continue
+		}
+		amount.Add(amount, big.NewInt(int64(pending[payer])))
+		data, err := ledger.GetStorageItem(genesis.OngContractAddress, payer[:])
+		if err != nil {
+			return fmt.Errorf("GetStorageItem error %s", err)
+		}
+		balance := new(big.Int).SetBytes(data)
+		if balance.Cmp(amount) < 0 {
+			return fmt.Errorf("payer %s balance %s is lower than fee %s", payer.ToBase58(), balance, amount)
+		}
+	}
+	return nil
+}
+
+// checkTransactionAttributes check the attributes with constrained data are well formed
+func checkTransactionAttributes(tx *types.Transaction) error {
+	validUntil := 0
+	for _, attr := range tx.Attributes {
+		if attr.Usage != types.ValidUntil {
+			continue
+		}
+		if len(attr.Data) != 4 {
+			return fmt.Errorf("invalid valid until attribute data length %d", len(attr.Data))
+		}
+		validUntil++
+	}
+	if validUntil > 1 {
+		return errors.New("duplicated valid until attribute")
+	}
+	return nil
+}
+
 func checkTransactionSignatures(tx *types.Transaction) error {
 	hash := tx.Hash()
 	address := make(map[common.Address]bool, len(tx.Sigs))
@@ -90,6 +199,16 @@ func checkTransactionSignatures(tx *types.Transaction) error {
 	return nil
 }
 
+// checkSignedBy check the transaction is signed by the address
+func checkSignedBy(tx *types.Transaction, address common.Address) error {
+	for _, addr := range tx.GetSignatureAddresses() {
+		if addr == address {
+			return nil
+		}
+	}
+	return fmt.Errorf("signature missing for %s", address.ToBase58())
+}
+
 func checkTransactionPayload(tx *types.Transaction) error {
 
 	switch pld := tx.Payload.(type) {
@@ -99,6 +218,18 @@ func checkTransactionPayload(tx *types.Transaction) error {
 		return nil
 	case *payload.BookKeeping:
 		return nil
+	case *payload.Record:
+		return pld.Check()
+	case *payload.DataFile:
+		if err := pld.Check(); err != nil {
+			return err
+		}
+		return checkSignedBy(tx, types.AddressFromPubKey(pld.Issuer))
+	case *payload.Enrollment:
+		if err := pld.Check(); err != nil {
+			return err
+		}
+		return checkSignedBy(tx, types.AddressFromPubKey(pld.PublicKey))
 	default:
 		return errors.New(fmt.Sprint("[txValidator], unimplemented transaction payload type.", pld))
 	}
@@ -0,0 +1,106 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/core/utils"
+	stypes "github.com/ontio/ontology/smartcontract/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckTransactionSignatures(t *testing.T) {
+	var accounts []*account.Account
+	var pubKeys []keypair.PublicKey
+	for _, encrypt := range []string{"SHA256withECDSA", "secp256k1", "SM3withSM2", "ed25519"} {
+		acc := account.NewAccount(encrypt)
+		accounts = append(accounts, acc)
+		pubKeys = append(pubKeys, acc.PubKey())
+	}
+	newTx := func() *types.Transaction {
+		return utils.NewInvokeTransaction(stypes.VmCode{VmType: stypes.Native, Code: []byte{1, 2, 3}})
+	}
+
+	for _, acc := range accounts {
+		tx := newTx()
+		tx.Fee = []*types.Fee{{Payer: acc.Address}}
+		assert.Nil(t, utils.SignTransaction(acc, tx))
+		assert.Nil(t, checkTransactionSignatures(tx))
+	}
+
+	//multi-signature of mixed schemes
+	multiAddr, err := types.AddressFromMultiPubKeys(pubKeys, 3)
+	assert.Nil(t, err)
+	tx := newTx()
+	tx.Fee = []*types.Fee{{Payer: multiAddr}}
+	assert.Nil(t, utils.MultiSignTransaction(accounts[3], 3, pubKeys, tx))
+	assert.Nil(t, utils.MultiSignTransaction(accounts[1], 3, pubKeys, tx))
+	assert.NotNil(t, checkTransactionSignatures(tx))
+	assert.Nil(t, utils.MultiSignTransaction(accounts[2], 3, pubKeys, tx))
+	assert.Nil(t, checkTransactionSignatures(tx))
+
+	//signature of other scheme's key is rejected
+	tx = newTx()
+	assert.Nil(t, utils.SignTransaction(accounts[0], tx))
+	tx.Sigs[0].PubKeys = []keypair.PublicKey{accounts[3].PubKey()}
+	assert.NotNil(t, checkTransactionSignatures(tx))
+}
+
+func TestCheckTransactionPayload(t *testing.T) {
+	acc := account.NewAccount("")
+	other := account.NewAccount("")
+
+	tx := &types.Transaction{
+		TxType:  types.Record,
+		Payload: &payload.Record{RecordType: "sha256", RecordData: []byte{1, 2, 3}},
+	}
+	assert.Nil(t, checkTransactionPayload(tx))
+	tx.Payload = &payload.Record{RecordType: "sha256"}
+	assert.NotNil(t, checkTransactionPayload(tx))
+
+	//data file must be signed by issuer
+	tx = &types.Transaction{
+		TxType:  types.DataFile,
+		Payload: &payload.DataFile{IPFSPath: "QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o", Filename: "hello.txt", Issuer: acc.PubKey()},
+	}
+	assert.Nil(t, utils.SignTransaction(other, tx))
+	assert.NotNil(t, checkTransactionPayload(tx))
+	assert.Nil(t, utils.SignTransaction(acc, tx))
+	assert.Nil(t, checkTransactionPayload(tx))
+
+	//enrollment must be signed by the enrolled key
+	tx = &types.Transaction{
+		TxType:  types.Enrollment,
+		Payload: &payload.Enrollment{PublicKey: acc.PubKey()},
+	}
+	assert.NotNil(t, checkTransactionPayload(tx))
+	assert.Nil(t, utils.SignTransaction(acc, tx))
+	assert.Nil(t, checkTransactionPayload(tx))
+
+	tx = &types.Transaction{
+		TxType:  types.Vote,
+		Payload: &payload.Vote{},
+	}
+	assert.NotNil(t, checkTransactionPayload(tx))
+}
@@ -47,6 +47,10 @@ const (
 	ErrNoAccount            ErrCode = 45014
 	ErrRetryExhausted       ErrCode = 45015
 	ErrTxPoolFull           ErrCode = 45016
+	ErrInsufficientFee      ErrCode = 45017
+	ErrInsufficientBalance  ErrCode = 45018
+	ErrTxExpired            ErrCode = 45019
+	ErrCallPermission       ErrCode = 45020
 )
 
 func (err ErrCode) Error() string {
@@ -85,6 +89,14 @@ func (err ErrCode) Error() string {
 		return "retry exhausted"
 	case ErrTxPoolFull:
 		return "tx pool full"
+	case ErrInsufficientFee:
+		return "insufficient transaction fee"
+	case ErrInsufficientBalance:
+		return "insufficient balance to pay fee"
+	case ErrTxExpired:
+		return "transaction expired"
+	case ErrCallPermission:
+		return "contract call permission denied"
 	}
 
 	return fmt.Sprintf("Unknown error? Error code = %d", err)
@@ -27,6 +27,9 @@ import (
 	"github.com/ontio/ontology/common/log"
 	lactor "github.com/ontio/ontology/core/ledger/actor"
 	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/proof"
+	"github.com/ontio/ontology/core/states"
+	scom "github.com/ontio/ontology/core/store/common"
 	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/smartcontract/event"
 )
@@ -152,6 +155,34 @@ func GetStorageItem(codeHash common.Address, key []byte) ([]byte, error) {
 	}
 }
 
+func GetStorageItemAtHeight(codeHash common.Address, key []byte, height uint32) ([]byte, error) {
+	future := defLedgerPid.RequestFuture(&lactor.GetStorageItemAtHeightReq{CodeHash: codeHash, Key: key, Height: height}, REQ_TIMEOUT*time.Second)
+	result, err := future.Result()
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return nil, err
+	}
+	if rsp, ok := result.(*lactor.GetStorageItemAtHeightRsp); !ok {
+		return nil, errors.New("fail")
+	} else {
+		return rsp.Value, rsp.Error
+	}
+}
+
+func GetStorageProof(codeHash common.Address, key []byte, height uint32) (*states.StorageProof, error) {
+	future := defLedgerPid.RequestFuture(&lactor.GetStorageProofReq{CodeHash: codeHash, Key: key, Height: height}, REQ_TIMEOUT*time.Second)
+	result, err := future.Result()
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return nil, err
+	}
+	if rsp, ok := result.(*lactor.GetStorageProofRsp); !ok {
+		return nil, errors.New("fail")
+	} else {
+		return rsp.StorageProof, rsp.Error
+	}
+}
+
 func GetContractStateFromStore(hash common.Address) (*payload.DeployCode, error) {
 	future := defLedgerPid.RequestFuture(&lactor.GetContractStateReq{hash}, REQ_TIMEOUT*time.Second)
 	result, err := future.Result()
@@ -239,6 +270,34 @@ func GetEventNotifyByHeight(height uint32) ([]common.Uint256, error) {
 	}
 }
 
+func GetEventNotifyByContract(contract common.Address, startHeight, endHeight uint32, eventName string, offset, limit uint32) ([]*event.ContractNotifyEventInfo, error) {
+	future := defLedgerPid.RequestFuture(&lactor.GetEventNotifyByContractReq{Contract: contract, StartHeight: startHeight, EndHeight: endHeight, EventName: eventName, Offset: offset, Limit: limit}, REQ_TIMEOUT*time.Second)
+	result, err := future.Result()
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return nil, err
+	}
+	if rsp, ok := result.(*lactor.GetEventNotifyByContractRsp); !ok {
+		return nil, errors.New("fail")
+	} else {
+		return rsp.Notifies, rsp.Error
+	}
+}
+
+func GetTransactionsByAddress(address common.Address, fromHeight uint32, limit uint32) ([]*scom.AddressTransaction, error) {
+	future := defLedgerPid.RequestFuture(&lactor.GetTransactionsByAddressReq{Address: address, FromHeight: fromHeight, Limit: limit}, REQ_TIMEOUT*time.Second)
+	result, err := future.Result()
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return nil, err
+	}
+	if rsp, ok := result.(*lactor.GetTransactionsByAddressRsp); !ok {
+		return nil, errors.New("fail")
+	} else {
+		return rsp.Txs, rsp.Error
+	}
+}
+
 func GetMerkleProof(proofHeight uint32, rootHeight uint32) ([]common.Uint256, error) {
 	future := defLedgerPid.RequestFuture(&lactor.GetMerkleProofReq{proofHeight, rootHeight}, REQ_TIMEOUT*time.Second)
 	result, err := future.Result()
@@ -252,3 +311,45 @@ func GetMerkleProof(proofHeight uint32, rootHeight uint32) ([]common.Uint256, er
 		return rsp.Proof, rsp.Error
 	}
 }
+
+func GetConsistencyProof(oldHeight uint32, newHeight uint32) ([]common.Uint256, error) {
+	future := defLedgerPid.RequestFuture(&lactor.GetConsistencyProofReq{OldHeight: oldHeight, NewHeight: newHeight}, REQ_TIMEOUT*time.Second)
+	result, err := future.Result()
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return nil, err
+	}
+	if rsp, ok := result.(*lactor.GetConsistencyProofRsp); !ok {
+		return nil, errors.New("fail")
+	} else {
+		return rsp.Proof, rsp.Error
+	}
+}
+
+func GetTransactionProof(txHash common.Uint256, rootHeight uint32) (*proof.TxProof, error) {
+	future := defLedgerPid.RequestFuture(&lactor.GetTransactionProofReq{TxHash: txHash, RootHeight: rootHeight}, REQ_TIMEOUT*time.Second)
+	result, err := future.Result()
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return nil, err
+	}
+	if rsp, ok := result.(*lactor.GetTransactionProofRsp); !ok {
+		return nil, errors.New("fail")
+	} else {
+		return rsp.Proof, rsp.Error
+	}
+}
+
+func GetContractHistory(contract common.Address) ([]*scom.ContractVersionItem, error) {
+	future := defLedgerPid.RequestFuture(&lactor.GetContractHistoryReq{Contract: contract}, REQ_TIMEOUT*time.Second)
+	result, err := future.Result()
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return nil, err
+	}
+	if rsp, ok := result.(*lactor.GetContractHistoryRsp); !ok {
+		return nil, errors.New("fail")
+	} else {
+		return rsp.History, rsp.Error
+	}
+}
@@ -0,0 +1,269 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/types"
+	bactor "github.com/ontio/ontology/http/base/actor"
+	"github.com/ontio/ontology/smartcontract/abi"
+	"github.com/ontio/ontology/smartcontract/event"
+	"github.com/ontio/ontology/smartcontract/states"
+	stypes "github.com/ontio/ontology/smartcontract/types"
+	vm "github.com/ontio/ontology/vm/neovm"
+	vtypes "github.com/ontio/ontology/vm/neovm/types"
+)
+
+//NotifyEvent is the notification decoded by the event of contract abi
+type NotifyEvent struct {
+	EventName string
+	Params    map[string]interface{}
+}
+
+func TransNotifyEventInfo(notify *event.NotifyEventInfo) NotifyEventInfo {
+	return NotifyEventInfo{
+		TxHash:          common.ToHexString(notify.TxHash[:]),
+		ContractAddress: notify.ContractAddress.ToHexString(),
+		States:          notify.States,
+		Event:           DecodeNotifyEvent(notify.ContractAddress, notify.States),
+	}
+}
+
+func TransNotifyEventInfos(notifies []*event.NotifyEventInfo) []NotifyEventInfo {
+	evs := make([]NotifyEventInfo, 0, len(notifies))
+	for _, v := range notifies {
+		evs = append(evs, TransNotifyEventInfo(v))
+	}
+	return evs
+}
+
+//GetContractABI return the abi of contract, nil if contract doesn't exist or has no abi
+func GetContractABI(contract common.Address) *abi.ABI {
+	deploy, err := bactor.GetContractStateFromStore(contract)
+	if err != nil || deploy == nil {
+		return nil
+	}
+	return deploy.ABI
+}
+
+//DecodeNotifyEvent decode notify states by the contract abi event named by the first state,
+//return nil if no event matched
+func DecodeNotifyEvent(contract common.Address, notifyStates interface{}) *NotifyEvent {
+	items, ok := notifyStates.([]interface{})
+	if !ok || len(items) == 0 {
+		return nil
+	}
+	name, ok := DecodeABIValue(abi.String, items[0]).(string)
+	if !ok {
+		return nil
+	}
+	contractABI := GetContractABI(contract)
+	if contractABI == nil {
+		return nil
+	}
+	ev := contractABI.GetEvent(name)
+	if ev == nil || len(ev.Parameters) != len(items)-1 {
+		return nil
+	}
+	params := make(map[string]interface{}, len(ev.Parameters))
+	for i, param := range ev.Parameters {
+		params[param.Name] = DecodeABIValue(param.Type, items[i+1])
+	}
+	return &NotifyEvent{EventName: name, Params: params}
+}
+
+//DecodeABIValue decode the hex string value of neovm stack item by abi parameter type,
+//the value is returned as it is if it can't be decoded
+func DecodeABIValue(paramType string, value interface{}) interface{} {
+	str, ok := value.(string)
+	if !ok {
+		return value
+	}
+	data, err := common.HexToBytes(str)
+	if err != nil {
+		return value
+	}
+	switch paramType {
+	case abi.Boolean:
+		for _, b := range data {
+			if b != 0 {
+				return true
+			}
+		}
+		return false
+	case abi.Integer:
+		return vtypes.ConvertBytesToBigInteger(data)
+	case abi.Hash160:
+		address, err := common.AddressParseFromBytes(data)
+		if err != nil {
+			return value
+		}
+		return address.ToBase58()
+	case abi.String:
+		return string(data)
+	case abi.Void:
+		return nil
+	}
+	return value
+}
+
+//BuildInvokeFunctionTx build the transaction invoking contract method with named parameters
+//according to contract abi
+func BuildInvokeFunctionTx(contract common.Address, method string, params map[string]interface{}) (*types.Transaction, *abi.Method, error) {
+	contractABI := GetContractABI(contract)
+	if contractABI == nil {
+		return nil, nil, fmt.Errorf("contract %x has no abi", contract)
+	}
+	m := contractABI.GetMethod(method)
+	if m == nil {
+		return nil, nil, fmt.Errorf("contract %x has no method %s", contract, method)
+	}
+	builder := vm.NewParamsBuilder(new(bytes.Buffer))
+	for i := len(m.Parameters) - 1; i >= 0; i-- {
+		param := m.Parameters[i]
+		value, ok := params[param.Name]
+		if !ok {
+			return nil, nil, fmt.Errorf("missing parameter %s", param.Name)
+		}
+		if err := emitABIValue(builder, param.Type, value); err != nil {
+			return nil, nil, fmt.Errorf("parameter %s error %s", param.Name, err)
+		}
+	}
+	builder.EmitPushInteger(big.NewInt(int64(len(m.Parameters))))
+	builder.Emit(vm.PACK)
+
+	c := &states.Contract{
+		Address: contract,
+		Method:  method,
+		Args:    builder.ToArray(),
+	}
+	buf := bytes.NewBuffer([]byte{byte(vm.APPCALL)})
+	if err := c.Serialize(buf); err != nil {
+		return nil, nil, fmt.Errorf("contract Serialize error %s", err)
+	}
+	tx := &types.Transaction{
+		TxType: types.Invoke,
+		Payload: &payload.InvokeCode{
+			Code: stypes.VmCode{VmType: stypes.NEOVM, Code: buf.Bytes()},
+		},
+	}
+	return tx, m, nil
+}
+
+//emitABIValue push the json value of parameter, the element types of array are decided by json types
+func emitABIValue(builder *vm.ParamsBuilder, paramType string, value interface{}) error {
+	switch paramType {
+	case abi.Boolean:
+		v, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("%v is not boolean", value)
+		}
+		builder.EmitPushBool(v)
+	case abi.Integer:
+		v, ok := parseInteger(value)
+		if !ok {
+			return fmt.Errorf("%v is not integer", value)
+		}
+		if v.IsInt64() {
+			builder.EmitPushInteger(v)
+		} else {
+			builder.EmitPushByteArray(vtypes.ConvertBigIntegerToBytes(v))
+		}
+	case abi.Hash160:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%v is not address", value)
+		}
+		address, err := common.AddressFromBase58(v)
+		if err != nil {
+			data, err := common.HexToBytes(v)
+			if err != nil {
+				return fmt.Errorf("%s is not address", v)
+			}
+			address, err = common.AddressParseFromBytes(data)
+			if err != nil {
+				return fmt.Errorf("%s is not address", v)
+			}
+		}
+		builder.EmitPushByteArray(address[:])
+	case abi.Hash256, abi.ByteArray, abi.PublicKey:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%v is not hex string", value)
+		}
+		data, err := common.HexToBytes(v)
+		if err != nil {
+			return fmt.Errorf("%s is not hex string", v)
+		}
+		builder.EmitPushByteArray(data)
+	case abi.String:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%v is not string", value)
+		}
+		builder.EmitPushByteArray([]byte(v))
+	case abi.Array:
+		v, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%v is not array", value)
+		}
+		for i := len(v) - 1; i >= 0; i-- {
+			var err error
+			switch v[i].(type) {
+			case bool:
+				err = emitABIValue(builder, abi.Boolean, v[i])
+			case float64:
+				err = emitABIValue(builder, abi.Integer, v[i])
+			case string:
+				err = emitABIValue(builder, abi.String, v[i])
+			case []interface{}:
+				err = emitABIValue(builder, abi.Array, v[i])
+			default:
+				err = fmt.Errorf("unsupported array element %v", v[i])
+			}
+			if err != nil {
+				return err
+			}
+		}
+		builder.EmitPushInteger(big.NewInt(int64(len(v))))
+		builder.Emit(vm.PACK)
+	default:
+		return fmt.Errorf("unsupported parameter type %s", paramType)
+	}
+	return nil
+}
+
+//parseInteger parse json number or decimal string to integer
+func parseInteger(value interface{}) (*big.Int, bool) {
+	switch v := value.(type) {
+	case float64:
+		if v != float64(int64(v)) {
+			return nil, false
+		}
+		return big.NewInt(int64(v)), true
+	case string:
+		return new(big.Int).SetString(v, 10)
+	}
+	return nil, false
+}
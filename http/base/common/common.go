@@ -19,14 +19,25 @@
 package common
 
 import (
+	"bytes"
+
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/core/proof"
+	"github.com/ontio/ontology/core/states"
+	scom "github.com/ontio/ontology/core/store/common"
 	"github.com/ontio/ontology/core/types"
 	ontErrors "github.com/ontio/ontology/errors"
 	bactor "github.com/ontio/ontology/http/base/actor"
+	"github.com/ontio/ontology/smartcontract/event"
 	"github.com/ontio/ontology-crypto/keypair"
 )
 
+const (
+	MAX_EVENT_NOTIFY_LIMIT = 100 //Max number of event notifies returned by one contract event query
+	MAX_ADDRESS_TX_LIMIT   = 100 //Max number of transactions returned by one address transaction query
+)
+
 type BalanceOfRsp struct {
 	Ont string `json:"ont"`
 	Ong string `json:"ong"`
@@ -42,10 +53,62 @@ type MerkleProof struct {
 	TargetHashes     []string
 }
 
+type StorageProof struct {
+	Type        string
+	StateRoot   string
+	BlockHeight uint32
+	Value       string
+	Proof       string
+}
+
+type ConsistencyProof struct {
+	Type         string
+	OldHeight    uint32
+	OldBlockRoot string
+	NewHeight    uint32
+	NewBlockRoot string
+	Proof        []string
+}
+
+type TxProof struct {
+	Type        string
+	TxHash      string
+	BlockHeight uint32
+	Header      string
+	TxIndex     uint32
+	TxCount     uint32
+	TxPath      []string
+	RootHeight  uint32
+	BlockPath   []string
+	Proof       string
+}
+
 type NotifyEventInfo struct {
 	TxHash          string
 	ContractAddress string
 	States          interface{}
+	Event           *NotifyEvent `json:",omitempty"`
+}
+
+type ContractNotifyEventInfo struct {
+	Height          uint32
+	TxHash          string
+	ContractAddress string
+	States          interface{}
+	Event           *NotifyEvent `json:",omitempty"`
+}
+
+type ContractVersionInfo struct {
+	Contract    string
+	Version     string
+	Predecessor string
+	Successor   string
+	Forward     bool
+}
+
+type AddressTransaction struct {
+	Height uint32
+	TxHash string
 }
 
 type TxAttributeInfo struct {
@@ -85,6 +148,7 @@ type BlockHead struct {
 	PrevBlockHash    string
 	TransactionsRoot string
 	BlockRoot        string
+	StateRoot        string
 	Timestamp        uint32
 	Height           uint32
 	ConsensusData    uint64
@@ -195,6 +259,7 @@ func GetBlockInfo(block *types.Block) BlockInfo {
 		PrevBlockHash:    common.ToHexString(block.Header.PrevBlockHash.ToArray()),
 		TransactionsRoot: common.ToHexString(block.Header.TransactionsRoot.ToArray()),
 		BlockRoot:        common.ToHexString(block.Header.BlockRoot.ToArray()),
+		StateRoot:        common.ToHexString(block.Header.StateRoot.ToArray()),
 		Timestamp:        block.Header.Timestamp,
 		Height:           block.Header.Height,
 		ConsensusData:    block.Header.ConsensusData,
@@ -216,3 +281,97 @@ func GetBlockInfo(block *types.Block) BlockInfo {
 	}
 	return b
 }
+
+func TransStorageProof(height uint32, storageProof *states.StorageProof) StorageProof {
+	proof := bytes.NewBuffer(nil)
+	storageProof.Serialize(proof)
+	return StorageProof{
+		Type:        "StorageProof",
+		StateRoot:   common.ToHexString(storageProof.StateRoot[:]),
+		BlockHeight: height,
+		Value:       common.ToHexString(storageProof.Value),
+		Proof:       common.ToHexString(proof.Bytes()),
+	}
+}
+
+func TransConsistencyProof(oldHeader, newHeader *types.Header, proof []common.Uint256) ConsistencyProof {
+	return ConsistencyProof{
+		Type:         "ConsistencyProof",
+		OldHeight:    oldHeader.Height,
+		OldBlockRoot: common.ToHexString(oldHeader.BlockRoot[:]),
+		NewHeight:    newHeader.Height,
+		NewBlockRoot: common.ToHexString(newHeader.BlockRoot[:]),
+		Proof:        transHashes(proof),
+	}
+}
+
+func TransTxProof(txHash common.Uint256, txProof *proof.TxProof) TxProof {
+	header := bytes.NewBuffer(nil)
+	txProof.Header.Serialize(header)
+	buf := bytes.NewBuffer(nil)
+	txProof.Serialize(buf)
+	return TxProof{
+		Type:        "TxProof",
+		TxHash:      common.ToHexString(txHash[:]),
+		BlockHeight: txProof.Header.Height,
+		Header:      common.ToHexString(header.Bytes()),
+		TxIndex:     txProof.TxIndex,
+		TxCount:     txProof.TxCount,
+		TxPath:      transHashes(txProof.TxPath),
+		RootHeight:  txProof.RootHeight,
+		BlockPath:   transHashes(txProof.BlockPath),
+		Proof:       common.ToHexString(buf.Bytes()),
+	}
+}
+
+func transHashes(hashes []common.Uint256) []string {
+	strs := make([]string, 0, len(hashes))
+	for _, v := range hashes {
+		strs = append(strs, common.ToHexString(v[:]))
+	}
+	return strs
+}
+
+func TransContractNotifyEventInfos(notifies []*event.ContractNotifyEventInfo) []ContractNotifyEventInfo {
+	evs := make([]ContractNotifyEventInfo, 0, len(notifies))
+	for _, v := range notifies {
+		evs = append(evs, ContractNotifyEventInfo{
+			Height:          v.Height,
+			TxHash:          common.ToHexString(v.Notify.TxHash[:]),
+			ContractAddress: v.Notify.ContractAddress.ToHexString(),
+			States:          v.Notify.States,
+			Event:           DecodeNotifyEvent(v.Notify.ContractAddress, v.Notify.States),
+		})
+	}
+	return evs
+}
+
+func TransAddressTransactions(txs []*scom.AddressTransaction) []AddressTransaction {
+	addrTxs := make([]AddressTransaction, 0, len(txs))
+	for _, v := range txs {
+		addrTxs = append(addrTxs, AddressTransaction{
+			Height: v.Height,
+			TxHash: common.ToHexString(v.TxHash[:]),
+		})
+	}
+	return addrTxs
+}
+
+func TransContractHistory(history []*scom.ContractVersionItem) []ContractVersionInfo {
+	infos := make([]ContractVersionInfo, 0, len(history))
+	for _, v := range history {
+		info := ContractVersionInfo{Contract: v.Contract.ToHexString()}
+		if v.Version != nil {
+			info.Version = v.Version.Version
+			info.Forward = v.Version.Forward
+			if v.Version.Predecessor != (common.Address{}) {
+				info.Predecessor = v.Version.Predecessor.ToHexString()
+			}
+			if v.Version.Successor != (common.Address{}) {
+				info.Successor = v.Version.Successor.ToHexString()
+			}
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
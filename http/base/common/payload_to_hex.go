@@ -4,6 +4,8 @@ import (
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/core/payload"
 	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/smartcontract/abi"
+	"github.com/ontio/ontology/smartcontract/permission"
 	"github.com/ontio/ontology-crypto/keypair"
 )
 
@@ -28,6 +30,8 @@ type DeployCodeInfo struct {
 	Author      string
 	Email       string
 	Description string
+	ABI         *abi.ABI
+	Permissions *permission.Permissions
 }
 
 //implement PayloadInfo define IssueAssetInfo
@@ -57,6 +61,10 @@ type DataFileInfo struct {
 	Issuer   string
 }
 
+type EnrollmentInfo struct {
+	PubKey string
+}
+
 type Claim struct {
 	Claims []*UTXOTxInput
 }
@@ -115,6 +123,24 @@ func TransPayloadToHex(p types.Payload) PayloadInfo {
 		obj.Author = object.Author
 		obj.Email = object.Email
 		obj.Description = object.Description
+		obj.ABI = object.ABI
+		obj.Permissions = object.Permissions
+		return obj
+	case *payload.Record:
+		obj := new(RecordInfo)
+		obj.RecordType = object.RecordType
+		obj.RecordData = common.ToHexString(object.RecordData)
+		return obj
+	case *payload.DataFile:
+		obj := new(DataFileInfo)
+		obj.IPFSPath = object.IPFSPath
+		obj.Filename = object.Filename
+		obj.Note = object.Note
+		obj.Issuer = common.ToHexString(keypair.SerializePublicKey(object.Issuer))
+		return obj
+	case *payload.Enrollment:
+		obj := new(EnrollmentInfo)
+		obj.PubKey = common.ToHexString(keypair.SerializePublicKey(object.PublicKey))
 		return obj
 	case *payload.Vote:
 		obj := new(VoteInfo)
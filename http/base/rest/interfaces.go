@@ -145,6 +145,48 @@ func GetBlockByHash(cmd map[string]interface{}) map[string]interface{} {
 	return resp
 }
 
+func GetSmartCodeEventByContract(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	bys, err := common.HexToBytes(cmd["Hash"].(string))
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	var contract common.Address
+	err = contract.Deserialize(bytes.NewReader(bys))
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	startHeight, err := strconv.ParseUint(cmd["StartHeight"].(string), 10, 32)
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	endHeight, err := strconv.ParseUint(cmd["EndHeight"].(string), 10, 32)
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	var offset uint64
+	if param, ok := cmd["Offset"].(string); ok && len(param) > 0 {
+		offset, err = strconv.ParseUint(param, 10, 32)
+		if err != nil {
+			return ResponsePack(berr.INVALID_PARAMS)
+		}
+	}
+	limit := uint64(bcomn.MAX_EVENT_NOTIFY_LIMIT)
+	if param, ok := cmd["Limit"].(string); ok && len(param) > 0 {
+		limit, err = strconv.ParseUint(param, 10, 32)
+		if err != nil || limit == 0 || limit > bcomn.MAX_EVENT_NOTIFY_LIMIT {
+			return ResponsePack(berr.INVALID_PARAMS)
+		}
+	}
+	eventName, _ := cmd["EventName"].(string)
+	notifies, err := bactor.GetEventNotifyByContract(contract, uint32(startHeight), uint32(endHeight), eventName, uint32(offset), uint32(limit))
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	resp["Result"] = bcomn.TransContractNotifyEventInfos(notifies)
+	return resp
+}
+
 func GetBlockHeightByTxHash(cmd map[string]interface{}) map[string]interface{} {
 	resp := ResponsePack(berr.SUCCESS)
 	param := cmd["Hash"].(string)
@@ -259,6 +301,47 @@ func GetTransactionByHash(cmd map[string]interface{}) map[string]interface{} {
 	resp["Result"] = tran
 	return resp
 }
+//InvokeFunction pre-execute contract method with parameters named by contract abi
+func InvokeFunction(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	str, ok := cmd["Contract"].(string)
+	if !ok {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	bys, err := common.HexToBytes(str)
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	var contract common.Address
+	if err := contract.Deserialize(bytes.NewReader(bys)); err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	method, ok := cmd["Method"].(string)
+	if !ok {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	params := make(map[string]interface{})
+	if v, ok := cmd["Params"]; ok {
+		params, ok = v.(map[string]interface{})
+		if !ok {
+			return ResponsePack(berr.INVALID_PARAMS)
+		}
+	}
+	txn, m, err := bcomn.BuildInvokeFunctionTx(contract, method, params)
+	if err != nil {
+		resp = ResponsePack(berr.INVALID_PARAMS)
+		resp["Result"] = err.Error()
+		return resp
+	}
+	result, err := bactor.PreExecuteContract(txn)
+	if err != nil {
+		log.Error(err)
+		return ResponsePack(berr.SMARTCODE_ERROR)
+	}
+	resp["Result"] = bcomn.DecodeABIValue(m.ReturnType, result)
+	return resp
+}
+
 func SendRawTransaction(cmd map[string]interface{}) map[string]interface{} {
 	resp := ResponsePack(berr.SUCCESS)
 
@@ -338,11 +421,29 @@ func GetSmartCodeEventByTxHash(cmd map[string]interface{}) map[string]interface{
 	if err != nil {
 		return ResponsePack(berr.INVALID_PARAMS)
 	}
-	var evts []bcomn.NotifyEventInfo
-	for _, v := range eventInfos {
-		evts = append(evts, bcomn.NotifyEventInfo{common.ToHexString(v.TxHash[:]),v.ContractAddress.ToHexString(), v.States})
+	resp["Result"] = bcomn.TransNotifyEventInfos(eventInfos)
+	return resp
+}
+
+func GetContractHistory(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	str, ok := cmd["Hash"].(string)
+	if !ok {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	bys, err := common.HexToBytes(str)
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	var contract common.Address
+	if err := contract.Deserialize(bytes.NewReader(bys)); err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	history, err := bactor.GetContractHistory(contract)
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
 	}
-	resp["Result"] = evts
+	resp["Result"] = bcomn.TransContractHistory(history)
 	return resp
 }
 
@@ -393,7 +494,19 @@ func GetStorage(cmd map[string]interface{}) map[string]interface{} {
 		return ResponsePack(berr.INVALID_PARAMS)
 	}
 	log.Info("[GetStorage] ", str, key)
-	value, err := bactor.GetStorageItem(hash, item)
+	var value []byte
+	if param, ok := cmd["Height"].(string); ok && len(param) > 0 {
+		height, err := strconv.ParseUint(param, 10, 32)
+		if err != nil {
+			return ResponsePack(berr.INVALID_PARAMS)
+		}
+		value, err = bactor.GetStorageItemAtHeight(hash, item, uint32(height))
+		if err != nil {
+			return ResponsePack(berr.INVALID_PARAMS)
+		}
+	} else {
+		value, err = bactor.GetStorageItem(hash, item)
+	}
 	if err != nil || value == nil {
 		return ResponsePack(berr.INTERNAL_ERROR)
 	}
@@ -401,6 +514,128 @@ func GetStorage(cmd map[string]interface{}) map[string]interface{} {
 	return resp
 }
 
+func GetConsistencyProof(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	oldHeight, err := strconv.ParseUint(cmd["OldHeight"].(string), 10, 32)
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	newHeight, err := strconv.ParseUint(cmd["NewHeight"].(string), 10, 32)
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	proof, err := bactor.GetConsistencyProof(uint32(oldHeight), uint32(newHeight))
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	oldHeader, err := bactor.GetHeaderByHeight(uint32(oldHeight))
+	if err != nil || oldHeader == nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	newHeader, err := bactor.GetHeaderByHeight(uint32(newHeight))
+	if err != nil || newHeader == nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	resp["Result"] = bcomn.TransConsistencyProof(oldHeader, newHeader, proof)
+	return resp
+}
+
+func GetTxProof(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	bys, err := common.HexToBytes(cmd["Hash"].(string))
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	var hash common.Uint256
+	err = hash.Deserialize(bytes.NewReader(bys))
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	var rootHeight uint32
+	if param, ok := cmd["Height"].(string); ok && len(param) > 0 {
+		h, err := strconv.ParseUint(param, 10, 32)
+		if err != nil {
+			return ResponsePack(berr.INVALID_PARAMS)
+		}
+		rootHeight = uint32(h)
+	} else {
+		rootHeight, err = bactor.BlockHeight()
+		if err != nil {
+			return ResponsePack(berr.INTERNAL_ERROR)
+		}
+	}
+	proof, err := bactor.GetTransactionProof(hash, rootHeight)
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	resp["Result"] = bcomn.TransTxProof(hash, proof)
+	return resp
+}
+
+func GetStorageProof(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	bys, err := common.HexToBytes(cmd["Hash"].(string))
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	var hash common.Address
+	err = hash.Deserialize(bytes.NewReader(bys))
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	key, err := common.HexToBytes(cmd["Key"].(string))
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	var height uint32
+	if param, ok := cmd["Height"].(string); ok && len(param) > 0 {
+		h, err := strconv.ParseUint(param, 10, 32)
+		if err != nil {
+			return ResponsePack(berr.INVALID_PARAMS)
+		}
+		height = uint32(h)
+	} else {
+		height, err = bactor.BlockHeight()
+		if err != nil {
+			return ResponsePack(berr.INTERNAL_ERROR)
+		}
+	}
+	proof, err := bactor.GetStorageProof(hash, key, height)
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	resp["Result"] = bcomn.TransStorageProof(height, proof)
+	return resp
+}
+
+func GetTransactionsByAddress(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	address, err := common.AddressFromBase58(cmd["Addr"].(string))
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	var fromHeight uint64
+	if param, ok := cmd["Height"].(string); ok && len(param) > 0 {
+		fromHeight, err = strconv.ParseUint(param, 10, 32)
+		if err != nil {
+			return ResponsePack(berr.INVALID_PARAMS)
+		}
+	}
+	limit := uint64(bcomn.MAX_ADDRESS_TX_LIMIT)
+	if param, ok := cmd["Limit"].(string); ok && len(param) > 0 {
+		limit, err = strconv.ParseUint(param, 10, 32)
+		if err != nil || limit == 0 || limit > bcomn.MAX_ADDRESS_TX_LIMIT {
+			return ResponsePack(berr.INVALID_PARAMS)
+		}
+	}
+	txs, err := bactor.GetTransactionsByAddress(address, uint32(fromHeight), uint32(limit))
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	resp["Result"] = bcomn.TransAddressTransactions(txs)
+	return resp
+}
+
 func GetBalance(cmd map[string]interface{}) map[string]interface{} {
 	resp := ResponsePack(berr.SUCCESS)
 	addrBase58 := cmd["Addr"].(string)
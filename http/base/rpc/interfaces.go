@@ -22,6 +22,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"fmt"
+	"math"
 	"math/big"
 
 	"github.com/ontio/ontology/common"
@@ -239,7 +240,9 @@ func GetRawTransaction(params []interface{}) map[string]interface{} {
 	return responseSuccess(common.ToHexString(w.Bytes()))
 }
 
+// A JSON example for getstorage method as following:
 //   {"jsonrpc": "2.0", "method": "getstorage", "params": ["code hash", "key"], "id": 0}
+//   {"jsonrpc": "2.0", "method": "getstorage", "params": ["code hash", "key", height], "id": 0}
 func GetStorage(params []interface{}) map[string]interface{} {
 	if len(params) < 2 {
 		return responsePack(berr.INVALID_PARAMS, nil)
@@ -272,6 +275,24 @@ func GetStorage(params []interface{}) map[string]interface{} {
 	default:
 		return responsePack(berr.INVALID_PARAMS, "")
 	}
+	if len(params) >= 3 {
+		switch params[2].(type) {
+		case float64:
+			param := params[2].(float64)
+			if param < 0 || param > math.MaxUint32 || param != math.Trunc(param) {
+				return responsePack(berr.INVALID_PARAMS, "")
+			}
+			height := uint32(param)
+			value, err := bactor.GetStorageItemAtHeight(codeHash, key, height)
+			if err != nil {
+				log.Errorf("GetStorage GetStorageItemAtHeight CodeHash:%x key:%s height:%d error:%s", codeHash, key, height, err)
+				return responsePack(berr.INVALID_PARAMS, err.Error())
+			}
+			return responseSuccess(common.ToHexString(value))
+		default:
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+	}
 	value, err := bactor.GetStorageItem(codeHash, key)
 	if err != nil {
 		log.Errorf("GetStorage GetStorageItem CodeHash:%x key:%s error:%s", codeHash, key, err)
@@ -280,6 +301,147 @@ func GetStorage(params []interface{}) map[string]interface{} {
 	return responseSuccess(common.ToHexString(value))
 }
 
+// A JSON example for getconsistencyproof method as following:
+//   {"jsonrpc": "2.0", "method": "getconsistencyproof", "params": [oldHeight], "id": 0}
+//   {"jsonrpc": "2.0", "method": "getconsistencyproof", "params": [oldHeight, newHeight], "id": 0}
+func GetConsistencyProof(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	var oldHeight, newHeight uint32
+	switch params[0].(type) {
+	case float64:
+		oldHeight = uint32(params[0].(float64))
+	default:
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	if len(params) >= 2 {
+		switch params[1].(type) {
+		case float64:
+			newHeight = uint32(params[1].(float64))
+		default:
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+	} else {
+		curHeight, err := bactor.BlockHeight()
+		if err != nil {
+			return responsePack(berr.INTERNAL_ERROR, "")
+		}
+		newHeight = curHeight
+	}
+	proof, err := bactor.GetConsistencyProof(oldHeight, newHeight)
+	if err != nil {
+		log.Errorf("GetConsistencyProof oldHeight:%d newHeight:%d error:%s", oldHeight, newHeight, err)
+		return responsePack(berr.INVALID_PARAMS, err.Error())
+	}
+	oldHeader, err := bactor.GetHeaderByHeight(oldHeight)
+	if err != nil || oldHeader == nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	newHeader, err := bactor.GetHeaderByHeight(newHeight)
+	if err != nil || newHeader == nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(bcomn.TransConsistencyProof(oldHeader, newHeader, proof))
+}
+
+// A JSON example for gettxproof method as following:
+//   {"jsonrpc": "2.0", "method": "gettxproof", "params": ["transaction hash"], "id": 0}
+//   {"jsonrpc": "2.0", "method": "gettxproof", "params": ["transaction hash", rootHeight], "id": 0}
+func GetTxProof(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	var hash common.Uint256
+	switch params[0].(type) {
+	case string:
+		hex, err := hex.DecodeString(params[0].(string))
+		if err != nil {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		if err := hash.Deserialize(bytes.NewReader(hex)); err != nil {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+	default:
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	var rootHeight uint32
+	if len(params) >= 2 {
+		switch params[1].(type) {
+		case float64:
+			rootHeight = uint32(params[1].(float64))
+		default:
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+	} else {
+		curHeight, err := bactor.BlockHeight()
+		if err != nil {
+			return responsePack(berr.INTERNAL_ERROR, "")
+		}
+		rootHeight = curHeight
+	}
+	proof, err := bactor.GetTransactionProof(hash, rootHeight)
+	if err != nil {
+		log.Errorf("GetTransactionProof TxHash:%x rootHeight:%d error:%s", hash, rootHeight, err)
+		return responsePack(berr.INVALID_PARAMS, err.Error())
+	}
+	return responseSuccess(bcomn.TransTxProof(hash, proof))
+}
+
+// A JSON example for getstorageproof method as following:
+//   {"jsonrpc": "2.0", "method": "getstorageproof", "params": ["code hash", "key"], "id": 0}
+//   {"jsonrpc": "2.0", "method": "getstorageproof", "params": ["code hash", "key", height], "id": 0}
+func GetStorageProof(params []interface{}) map[string]interface{} {
+	if len(params) < 2 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	var codeHash common.Address
+	var key []byte
+	switch params[0].(type) {
+	case string:
+		hex, err := hex.DecodeString(params[0].(string))
+		if err != nil {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		if err := codeHash.Deserialize(bytes.NewReader(hex)); err != nil {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+	default:
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	switch params[1].(type) {
+	case string:
+		hex, err := hex.DecodeString(params[1].(string))
+		if err != nil {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		key = hex
+	default:
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	var height uint32
+	if len(params) >= 3 {
+		switch params[2].(type) {
+		case float64:
+			height = uint32(params[2].(float64))
+		default:
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+	} else {
+		curHeight, err := bactor.BlockHeight()
+		if err != nil {
+			return responsePack(berr.INTERNAL_ERROR, "")
+		}
+		height = curHeight
+	}
+	proof, err := bactor.GetStorageProof(codeHash, key, height)
+	if err != nil {
+		log.Errorf("GetStorageProof CodeHash:%x key:%x height:%d error:%s", codeHash, key, height, err)
+		return responsePack(berr.INVALID_PARAMS, err.Error())
+	}
+	return responseSuccess(bcomn.TransStorageProof(height, proof))
+}
+
 // A JSON example for sendrawtransaction method as following:
 //   {"jsonrpc": "2.0", "method": "sendrawtransaction", "params": ["raw transactioin in hex"], "id": 0}
 func SendRawTransaction(params []interface{}) map[string]interface{} {
@@ -321,6 +483,47 @@ func SendRawTransaction(params []interface{}) map[string]interface{} {
 	return responseSuccess(common.ToHexString(hash.ToArray()))
 }
 
+// A JSON example for invokefunction method as following, the parameters are named by contract abi:
+//   {"jsonrpc": "2.0", "method": "invokefunction", "params": ["contract address", "method", {"param name": "param value"}], "id": 0}
+func InvokeFunction(params []interface{}) map[string]interface{} {
+	if len(params) < 2 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	hex, err := hex.DecodeString(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	var contract common.Address
+	if err := contract.Deserialize(bytes.NewReader(hex)); err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	method, ok := params[1].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	args := make(map[string]interface{})
+	if len(params) >= 3 {
+		args, ok = params[2].(map[string]interface{})
+		if !ok {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+	}
+	txn, m, err := bcomn.BuildInvokeFunctionTx(contract, method, args)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, err.Error())
+	}
+	result, err := bactor.PreExecuteContract(txn)
+	if err != nil {
+		log.Error(err)
+		return responsePack(berr.SMARTCODE_ERROR, "")
+	}
+	return responseSuccess(bcomn.DecodeABIValue(m.ReturnType, result))
+}
+
 func GetNodeVersion(params []interface{}) map[string]interface{} {
 	return responseSuccess(config.Parameters.Version)
 }
@@ -406,17 +609,99 @@ func GetSmartCodeEvent(params []interface{}) map[string]interface{} {
 		if err != nil {
 			return responsePack(berr.INVALID_PARAMS, "")
 		}
-		var evs []bcomn.NotifyEventInfo
-		for _, v := range eventInfos {
-			evs = append(evs, bcomn.NotifyEventInfo{common.ToHexString(v.TxHash[:]),v.ContractAddress.ToHexString(), v.States})
-		}
-		return responseSuccess(evs)
+		return responseSuccess(bcomn.TransNotifyEventInfos(eventInfos))
 	default:
 		return responsePack(berr.INVALID_PARAMS, "")
 	}
 	return responsePack(berr.INVALID_PARAMS, "")
 }
 
+// A JSON example for getcontracthistory method as following:
+//   {"jsonrpc": "2.0", "method": "getcontracthistory", "params": ["contract address"], "id": 0}
+func GetContractHistory(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	hex, err := hex.DecodeString(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	var contract common.Address
+	if err := contract.Deserialize(bytes.NewReader(hex)); err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	history, err := bactor.GetContractHistory(contract)
+	if err != nil {
+		log.Errorf("GetContractHistory contract:%x error:%s", contract, err)
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(bcomn.TransContractHistory(history))
+}
+
+// A JSON example for getsmartcodeeventbycontract method as following:
+//   {"jsonrpc": "2.0", "method": "getsmartcodeeventbycontract", "params": ["contract address", startHeight, endHeight], "id": 0}
+//   {"jsonrpc": "2.0", "method": "getsmartcodeeventbycontract", "params": ["contract address", startHeight, endHeight, "event name", offset, limit], "id": 0}
+func GetSmartCodeEventByContract(params []interface{}) map[string]interface{} {
+	if len(params) < 3 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	var contract common.Address
+	switch params[0].(type) {
+	case string:
+		hex, err := hex.DecodeString(params[0].(string))
+		if err != nil {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		if err := contract.Deserialize(bytes.NewReader(hex)); err != nil {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+	default:
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	var heights [2]uint32
+	for i := range heights {
+		height, ok := params[1+i].(float64)
+		if !ok {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		heights[i] = uint32(height)
+	}
+	var eventName string
+	if len(params) >= 4 {
+		name, ok := params[3].(string)
+		if !ok {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		eventName = name
+	}
+	var offset uint32
+	if len(params) >= 5 {
+		v, ok := params[4].(float64)
+		if !ok {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		offset = uint32(v)
+	}
+	limit := uint32(bcomn.MAX_EVENT_NOTIFY_LIMIT)
+	if len(params) >= 6 {
+		v, ok := params[5].(float64)
+		if !ok || v <= 0 || v > bcomn.MAX_EVENT_NOTIFY_LIMIT {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		limit = uint32(v)
+	}
+	notifies, err := bactor.GetEventNotifyByContract(contract, heights[0], heights[1], eventName, offset, limit)
+	if err != nil {
+		log.Errorf("GetEventNotifyByContract contract:%x error:%s", contract, err)
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(bcomn.TransContractNotifyEventInfos(notifies))
+}
+
 func GetBlockHeightByTxHash(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
 		return responsePack(berr.INVALID_PARAMS, nil)
@@ -448,6 +733,45 @@ func GetBlockHeightByTxHash(params []interface{}) map[string]interface{} {
 	return responsePack(berr.INVALID_PARAMS, "")
 }
 
+// A JSON example for gettransactionsbyaddress method as following:
+//   {"jsonrpc": "2.0", "method": "gettransactionsbyaddress", "params": ["base58 address"], "id": 0}
+//   {"jsonrpc": "2.0", "method": "gettransactionsbyaddress", "params": ["base58 address", fromHeight, limit], "id": 0}
+func GetTransactionsByAddress(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	addrBase58, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	address, err := common.AddressFromBase58(addrBase58)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	var fromHeight uint32
+	if len(params) >= 2 {
+		v, ok := params[1].(float64)
+		if !ok {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		fromHeight = uint32(v)
+	}
+	limit := uint32(bcomn.MAX_ADDRESS_TX_LIMIT)
+	if len(params) >= 3 {
+		v, ok := params[2].(float64)
+		if !ok || v <= 0 || v > bcomn.MAX_ADDRESS_TX_LIMIT {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		limit = uint32(v)
+	}
+	txs, err := bactor.GetTransactionsByAddress(address, fromHeight, limit)
+	if err != nil {
+		log.Errorf("GetTransactionsByAddress address:%s error:%s", addrBase58, err)
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	return responseSuccess(bcomn.TransAddressTransactions(txs))
+}
+
 func GetBalance(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
 		return responsePack(berr.INVALID_PARAMS, "")
@@ -41,16 +41,23 @@ func StartRPCServer() {
 
 	rpc.HandleFunc("getrawtransaction", rpc.GetRawTransaction)
 	rpc.HandleFunc("sendrawtransaction", rpc.SendRawTransaction)
+	rpc.HandleFunc("invokefunction", rpc.InvokeFunction)
 	rpc.HandleFunc("getstorage", rpc.GetStorage)
+	rpc.HandleFunc("getstorageproof", rpc.GetStorageProof)
 	rpc.HandleFunc("getversion", rpc.GetNodeVersion)
 
 	rpc.HandleFunc("getblocksysfee", rpc.GetSystemFee)
 	rpc.HandleFunc("getcontractstate", rpc.GetContractState)
+	rpc.HandleFunc("getcontracthistory", rpc.GetContractHistory)
 	rpc.HandleFunc("getmempooltxstate", rpc.GetMemPoolTxState)
 	rpc.HandleFunc("getsmartcodeevent", rpc.GetSmartCodeEvent)
+	rpc.HandleFunc("getsmartcodeeventbycontract", rpc.GetSmartCodeEventByContract)
 	rpc.HandleFunc("getblockheightbytxhash", rpc.GetBlockHeightByTxHash)
 
 	rpc.HandleFunc("getbalance", rpc.GetBalance)
+	rpc.HandleFunc("gettransactionsbyaddress", rpc.GetTransactionsByAddress)
+	rpc.HandleFunc("gettxproof", rpc.GetTxProof)
+	rpc.HandleFunc("getconsistencyproof", rpc.GetConsistencyProof)
 	rpc.HandleFunc("getmerkleproof", rpc.GetMerkleProof)
 
 	err := http.ListenAndServe(":"+strconv.Itoa(cfg.Parameters.HttpJsonPort), nil)
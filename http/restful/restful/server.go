@@ -59,14 +59,21 @@ const (
 	GET_BLK_HASH          = "/api/v1/block/hash/:height"
 	GET_TX                = "/api/v1/transaction/:hash"
 	GET_STORAGE           = "/api/v1/storage/:hash/:key"
+	GET_STORAGE_PROOF     = "/api/v1/storageproof/:hash/:key"
 	GET_BALANCE           = "/api/v1/balance/:addr"
+	GET_ADDR_TXS          = "/api/v1/address/transactions/:addr"
 	GET_CONTRACT_STATE    = "/api/v1/contract/:hash"
+	GET_CONTRACT_HISTORY  = "/api/v1/contracthistory/:hash"
 	GET_SMTCOCE_EVT_TXS   = "/api/v1/smartcode/event/transactions/:height"
 	GET_SMTCOCE_EVTS      = "/api/v1/smartcode/event/txhash/:hash"
+	GET_SMTCOCE_CTR_EVTS  = "/api/v1/smartcode/event/contract/:hash/:start/:end"
 	GET_BLK_HGT_BY_TXHASH = "/api/v1/block/height/txhash/:hash"
 	GET_MERKLE_PROOF      = "/api/v1/merkleproof/:hash"
+	GET_TX_PROOF          = "/api/v1/txproof/:hash"
+	GET_CONSIST_PROOF     = "/api/v1/consistencyproof/:oldheight/:newheight"
 
-	POST_RAW_TX = "/api/v1/transaction"
+	POST_RAW_TX      = "/api/v1/transaction"
+	POST_INVOKE_FUNC = "/api/v1/smartcode/invoke"
 )
 
 func InitRestServer() rest.ApiServer {
@@ -124,16 +131,23 @@ func (this *restServer) registryMethod() {
 		GET_BLK_HASH:          {name: "getblockhash", handler: rest.GetBlockHash},
 		GET_TX:                {name: "gettransaction", handler: rest.GetTransactionByHash},
 		GET_CONTRACT_STATE:    {name: "getcontract", handler: rest.GetContractState},
+		GET_CONTRACT_HISTORY:  {name: "getcontracthistory", handler: rest.GetContractHistory},
 		GET_SMTCOCE_EVT_TXS:   {name: "getsmartcodeeventbyheight", handler: rest.GetSmartCodeEventTxsByHeight},
 		GET_SMTCOCE_EVTS:      {name: "getsmartcodeeventbyhash", handler: rest.GetSmartCodeEventByTxHash},
+		GET_SMTCOCE_CTR_EVTS:  {name: "getsmartcodeeventbycontract", handler: rest.GetSmartCodeEventByContract},
 		GET_BLK_HGT_BY_TXHASH: {name: "getblockheightbytxhash", handler: rest.GetBlockHeightByTxHash},
 		GET_STORAGE:           {name: "getstorage", handler: rest.GetStorage},
+		GET_STORAGE_PROOF:     {name: "getstorageproof", handler: rest.GetStorageProof},
 		GET_BALANCE:           {name: "getbalance", handler: rest.GetBalance},
+		GET_ADDR_TXS:          {name: "gettransactionsbyaddress", handler: rest.GetTransactionsByAddress},
 		GET_MERKLE_PROOF:     {name: "getmerkleproof", handler: rest.GetMerkleProof},
+		GET_TX_PROOF:          {name: "gettxproof", handler: rest.GetTxProof},
+		GET_CONSIST_PROOF:     {name: "getconsistencyproof", handler: rest.GetConsistencyProof},
 	}
 
 	postMethodMap := map[string]Action{
-		POST_RAW_TX:          {name: "sendrawtransaction", handler: rest.SendRawTransaction},
+		POST_RAW_TX:      {name: "sendrawtransaction", handler: rest.SendRawTransaction},
+		POST_INVOKE_FUNC: {name: "invokefunction", handler: rest.InvokeFunction},
 	}
 	this.postMap = postMethodMap
 	this.getMap = getMethodMap
@@ -152,18 +166,30 @@ func (this *restServer) getPath(url string) string {
 		return GET_TX
 	} else if strings.Contains(url, strings.TrimRight(GET_CONTRACT_STATE, ":hash")) {
 		return GET_CONTRACT_STATE
+	} else if strings.Contains(url, strings.TrimRight(GET_CONTRACT_HISTORY, ":hash")) {
+		return GET_CONTRACT_HISTORY
 	} else if strings.Contains(url, strings.TrimRight(GET_SMTCOCE_EVT_TXS, ":height")) {
 		return GET_SMTCOCE_EVT_TXS
 	} else if strings.Contains(url, strings.TrimRight(GET_SMTCOCE_EVTS, ":hash")) {
 		return GET_SMTCOCE_EVTS
+	} else if strings.Contains(url, strings.TrimRight(GET_SMTCOCE_CTR_EVTS, ":hash/:start/:end")) {
+		return GET_SMTCOCE_CTR_EVTS
 	} else if strings.Contains(url, strings.TrimRight(GET_BLK_HGT_BY_TXHASH, ":hash")) {
 		return GET_BLK_HGT_BY_TXHASH
 	} else if strings.Contains(url, strings.TrimRight(GET_STORAGE, ":hash/:key")) {
 		return GET_STORAGE
+	} else if strings.Contains(url, strings.TrimRight(GET_STORAGE_PROOF, ":hash/:key")) {
+		return GET_STORAGE_PROOF
 	} else if strings.Contains(url, strings.TrimRight(GET_BALANCE, ":addr")) {
 		return GET_BALANCE
+	} else if strings.Contains(url, strings.TrimRight(GET_ADDR_TXS, ":addr")) {
+		return GET_ADDR_TXS
 	} else if strings.Contains(url, strings.TrimRight(GET_MERKLE_PROOF, ":hash")) {
 		return GET_MERKLE_PROOF
+	} else if strings.Contains(url, strings.TrimRight(GET_TX_PROOF, ":hash")) {
+		return GET_TX_PROOF
+	} else if strings.Contains(url, strings.TrimRight(GET_CONSIST_PROOF, ":oldheight/:newheight")) {
+		return GET_CONSIST_PROOF
 	}
 	return url
 }
@@ -184,6 +210,8 @@ func (this *restServer) getParams(r *http.Request, url string, req map[string]in
 		req["Hash"], req["Raw"] = getParam(r, "hash"), r.FormValue("raw")
 	case GET_CONTRACT_STATE:
 		req["Hash"], req["Raw"] = getParam(r, "hash"), r.FormValue("raw")
+	case GET_CONTRACT_HISTORY:
+		req["Hash"] = getParam(r, "hash")
 	case POST_RAW_TX:
 		userid := r.FormValue("userid")
 		req["Userid"] = userid
@@ -192,17 +220,28 @@ func (this *restServer) getParams(r *http.Request, url string, req map[string]in
 		}
 		req["PreExec"] = r.FormValue("preExec")
 	case GET_STORAGE:
-		req["Hash"], req["Key"] = getParam(r, "hash"), getParam(r, "key")
+		req["Hash"], req["Key"], req["Height"] = getParam(r, "hash"), getParam(r, "key"), r.FormValue("height")
+	case GET_STORAGE_PROOF:
+		req["Hash"], req["Key"], req["Height"] = getParam(r, "hash"), getParam(r, "key"), r.FormValue("height")
 	case GET_SMTCOCE_EVT_TXS:
 		req["Height"] = getParam(r, "height")
 	case GET_SMTCOCE_EVTS:
 		req["Hash"] = getParam(r, "hash")
+	case GET_SMTCOCE_CTR_EVTS:
+		req["Hash"], req["StartHeight"], req["EndHeight"] = getParam(r, "hash"), getParam(r, "start"), getParam(r, "end")
+		req["EventName"], req["Offset"], req["Limit"] = r.FormValue("name"), r.FormValue("offset"), r.FormValue("limit")
 	case GET_BLK_HGT_BY_TXHASH:
 		req["Hash"] = getParam(r, "hash")
 	case GET_BALANCE:
 		req["Addr"] = getParam(r, "addr")
+	case GET_ADDR_TXS:
+		req["Addr"], req["Height"], req["Limit"] = getParam(r, "addr"), r.FormValue("height"), r.FormValue("limit")
 	case GET_MERKLE_PROOF:
 		req["Hash"] = getParam(r, "hash")
+	case GET_TX_PROOF:
+		req["Hash"], req["Height"] = getParam(r, "hash"), r.FormValue("height")
+	case GET_CONSIST_PROOF:
+		req["OldHeight"], req["NewHeight"] = getParam(r, "oldheight"), getParam(r, "newheight")
 	default:
 	}
 	return req
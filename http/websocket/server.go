@@ -78,12 +78,7 @@ func pushSmartCodeEvent(v interface{}) {
 	go func() {
 		switch object := rs.Result.(type) {
 		case []*event.NotifyEventInfo:
-			evts := []bcomn.NotifyEventInfo{}
-			for _, v := range object {
-				txhash := v.TxHash
-				evts = append(evts, bcomn.NotifyEventInfo{common.ToHexString(txhash[:]), v.ContractAddress.ToHexString(), v.States})
-			}
-			pushEvent(rs.TxHash, rs.Error, rs.Action, evts)
+			pushEvent(rs.TxHash, rs.Error, rs.Action, bcomn.TransNotifyEventInfos(object))
 		case *event.LogEventArgs:
 			type logEventArgs struct {
 				TxHash          string
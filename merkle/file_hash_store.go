@@ -55,7 +55,7 @@ func NewFileHashStore(name string, tree_size uint32) (HashStore, error) {
 		return nil, err
 	}
 
-	num_hashes := getStoredHashNum(tree_size)
+	num_hashes := GetStoredHashNum(tree_size)
 	size := int64(num_hashes) * int64(common.UINT256_SIZE)
 
 	_, err = store.file.Seek(size, io.SeekStart)
@@ -65,7 +65,8 @@ func NewFileHashStore(name string, tree_size uint32) (HashStore, error) {
 	return store, nil
 }
 
-func getStoredHashNum(tree_size uint32) int64 {
+// GetStoredHashNum returns the count of hashes stored in HashStore for a tree of tree_size
+func GetStoredHashNum(tree_size uint32) int64 {
 	subtreesize := getSubTreeSize(tree_size)
 	sum := int64(0)
 	for _, v := range subtreesize {
@@ -76,7 +77,7 @@ func getStoredHashNum(tree_size uint32) int64 {
 }
 
 func (self *fileHashStore) checkConsistence(tree_size uint32) error {
-	num_hashes := getStoredHashNum(tree_size)
+	num_hashes := GetStoredHashNum(tree_size)
 
 	stat, err := self.file.Stat()
 	if err != nil {
@@ -205,28 +205,36 @@ func getSubTreePos(n uint32) []uint32 {
 }
 
 // return merkle root of D[0:n] not include n
-func (self *CompactMerkleTree) merkleRoot(n uint32) common.Uint256 {
+func (self *CompactMerkleTree) merkleRoot(n uint32) (common.Uint256, error) {
 	hashespos := getSubTreePos(n)
 	nhashes := uint(len(hashespos))
 
 	hashes := make([]common.Uint256, nhashes, nhashes)
 	for i := uint(0); i < nhashes; i++ {
-		hashes[i], _ = self.hashStore.GetHash(hashespos[i] - 1)
+		hash, err := self.hashStore.GetHash(hashespos[i] - 1)
+		if err != nil {
+			return common.Uint256{}, err
+		}
+		hashes[i] = hash
 	}
-	return self.hasher._hash_fold(hashes)
+	return self.hasher._hash_fold(hashes), nil
 }
 
 // ConsistencyProof returns consistency proof
-func (self *CompactMerkleTree) ConsistencyProof(m, n uint32) []common.Uint256 {
-	if m > n || self.treeSize < n || self.hashStore == nil {
-		return nil
+func (self *CompactMerkleTree) ConsistencyProof(m, n uint32) ([]common.Uint256, error) {
+	if m > n {
+		return nil, errors.New("wrong paramaters")
+	} else if self.treeSize < n {
+		return nil, errors.New("not available yet")
+	} else if self.hashStore == nil {
+		return nil, errors.New("hash store not available")
 	}
 
 	return self.subproof(m, n, true)
 }
 
 // m, n 1-based
-func (self *CompactMerkleTree) subproof(m, n uint32, b bool) []common.Uint256 {
+func (self *CompactMerkleTree) subproof(m, n uint32, b bool) ([]common.Uint256, error) {
 	offset := uint32(0)
 	var hashes []common.Uint256
 	for m < n {
@@ -236,14 +244,21 @@ func (self *CompactMerkleTree) subproof(m, n uint32, b bool) []common.Uint256 {
 			subhashes := make([]common.Uint256, len(pos), len(pos))
 			for p := range pos {
 				pos[p] += offset + k*2 - 1
-				subhashes[p], _ = self.hashStore.GetHash(pos[p] - 1)
+				hash, err := self.hashStore.GetHash(pos[p] - 1)
+				if err != nil {
+					return nil, err
+				}
+				subhashes[p] = hash
 			}
 			rootk2n := self.hasher._hash_fold(subhashes)
 			hashes = append(hashes, rootk2n)
 			n = k
 		} else {
 			offset += k*2 - 1
-			root02k, _ := self.hashStore.GetHash(offset - 1)
+			root02k, err := self.hashStore.GetHash(offset - 1)
+			if err != nil {
+				return nil, err
+			}
 			hashes = append(hashes, root02k)
 			m -= k
 			n -= k
@@ -258,7 +273,10 @@ func (self *CompactMerkleTree) subproof(m, n uint32, b bool) []common.Uint256 {
 		if len(pos) != 1 {
 			panic("assert error")
 		}
-		root02n, _ := self.hashStore.GetHash(pos[0] + offset - 1)
+		root02n, err := self.hashStore.GetHash(pos[0] + offset - 1)
+		if err != nil {
+			return nil, err
+		}
 		hashes = append(hashes, root02n)
 	}
 
@@ -268,7 +286,7 @@ func (self *CompactMerkleTree) subproof(m, n uint32, b bool) []common.Uint256 {
 		reverse[k] = hashes[length-k-1]
 	}
 
-	return reverse
+	return reverse, nil
 }
 
 // InclusionProof returns the proof d[m] in D[0:n]
@@ -291,14 +309,21 @@ func (self *CompactMerkleTree) InclusionProof(m, n uint32) ([]common.Uint256, er
 			subhashes := make([]common.Uint256, len(pos), len(pos))
 			for p := range pos {
 				pos[p] += offset + k*2 - 1
-				subhashes[p], _ = self.hashStore.GetHash(pos[p] - 1)
+				hash, err := self.hashStore.GetHash(pos[p] - 1)
+				if err != nil {
+					return nil, err
+				}
+				subhashes[p] = hash
 			}
 			rootk2n := self.hasher._hash_fold(subhashes)
 			hashes = append(hashes, rootk2n)
 			n = k
 		} else {
 			offset += k*2 - 1
-			root02k, _ := self.hashStore.GetHash(offset - 1)
+			root02k, err := self.hashStore.GetHash(offset - 1)
+			if err != nil {
+				return nil, err
+			}
 			hashes = append(hashes, root02k)
 			m -= k
 			n -= k
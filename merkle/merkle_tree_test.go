@@ -132,7 +132,11 @@ func TestMerkleRoot(t *testing.T) {
 
 	cmp := make([]common.Uint256, n, n)
 	for i := 0; i < n; i++ {
-		cmp[i] = tree.merkleRoot(uint32(i) + 1)
+		root, err := tree.merkleRoot(uint32(i) + 1)
+		if err != nil {
+			t.Fatal("merkle root error:", i, err)
+		}
+		cmp[i] = root
 		if cmp[i] != roots[i] {
 			t.Error(fmt.Sprintf("error merkle root is not equal at %d", i))
 		}
@@ -177,7 +181,10 @@ func TestMerkleConsistencyProofLen(t *testing.T) {
 
 	cmp := []int{3, 2, 4, 1, 4, 3, 0}
 	for i := uint32(0); i < n; i++ {
-		proof := tree.ConsistencyProof(i+1, n)
+		proof, err := tree.ConsistencyProof(i+1, n)
+		if err != nil {
+			t.Fatal("consistency proof error:", i, err)
+		}
 		if len(proof) != cmp[i] {
 			t.Fatal("error: wrong proof length")
 		}
@@ -198,8 +205,11 @@ func TestMerkleConsistencyProof(t *testing.T) {
 	verify := NewMerkleVerifier()
 
 	for i := uint32(0); i < n; i++ {
-		proof := tree.ConsistencyProof(i+1, n)
-		err := verify.VerifyConsistency(i+1, n, roots[i], roots[n-1], proof)
+		proof, err := tree.ConsistencyProof(i+1, n)
+		if err != nil {
+			t.Fatal("consistency proof error:", i, err)
+		}
+		err = verify.VerifyConsistency(i+1, n, roots[i], roots[n-1], proof)
 		if err != nil {
 			t.Fatal("verify consistency error:", i, err)
 		}
@@ -0,0 +1,319 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/serialization"
+)
+
+const (
+	SPARSE_KEY_BITS = common.UINT256_SIZE * 8 // depth of sparse merkle tree
+
+	sparseLeafNode     = byte(0)
+	sparseInternalNode = byte(1)
+)
+
+// SparseNodeStore is the persistence of sparse merkle tree nodes, which are addressed by node hash
+type SparseNodeStore interface {
+	// GetNode returns the node data of hash, or nil if not found
+	GetNode(hash common.Uint256) ([]byte, error)
+}
+
+type sparseNode struct {
+	leaf  bool
+	left  common.Uint256 // key hash of leaf
+	right common.Uint256 // value hash of leaf
+}
+
+func (self *sparseNode) serialize() []byte {
+	data := make([]byte, 0, 1+2*common.UINT256_SIZE)
+	if self.leaf {
+		data = append(data, sparseLeafNode)
+	} else {
+		data = append(data, sparseInternalNode)
+	}
+	data = append(data, self.left[:]...)
+	return append(data, self.right[:]...)
+}
+
+func (self *sparseNode) hash() common.Uint256 {
+	hasher := TreeHasher{}
+	if self.leaf {
+		return hasher.hash_leaf(append(self.left[:], self.right[:]...))
+	}
+	return hasher.hash_children(self.left, self.right)
+}
+
+func deserializeSparseNode(data []byte) (*sparseNode, error) {
+	if len(data) != 1+2*common.UINT256_SIZE || data[0] > sparseInternalNode {
+		return nil, errors.New("invalid sparse merkle node")
+	}
+	node := &sparseNode{leaf: data[0] == sparseLeafNode}
+	copy(node.left[:], data[1:1+common.UINT256_SIZE])
+	copy(node.right[:], data[1+common.UINT256_SIZE:])
+	return node, nil
+}
+
+// sparseKeyBit returns the bit of key at depth, 0 means left
+func sparseKeyBit(key common.Uint256, depth int) byte {
+	return (key[depth/8] >> (7 - uint(depth%8))) & 1
+}
+
+// SparseMerkleTree is a sparse merkle tree over 256 bits keys. A subtree with only one leaf is
+// replaced by the leaf itself, and an empty subtree has EMPTY_HASH. Nodes are immutable and
+// addressed by hash, so the tree of any old root can still be proved while its nodes are kept.
+type SparseMerkleTree struct {
+	root     common.Uint256
+	store    SparseNodeStore
+	newNodes map[common.Uint256][]byte
+}
+
+// NewSparseMerkleTree returns a SparseMerkleTree of root with nodes in store
+func NewSparseMerkleTree(root common.Uint256, store SparseNodeStore) *SparseMerkleTree {
+	return &SparseMerkleTree{
+		root:     root,
+		store:    store,
+		newNodes: make(map[common.Uint256][]byte),
+	}
+}
+
+// Root returns the root hash of tree
+func (self *SparseMerkleTree) Root() common.Uint256 {
+	return self.root
+}
+
+// NewNodes returns the nodes created by Update, which should be saved to SparseNodeStore
+func (self *SparseMerkleTree) NewNodes() map[common.Uint256][]byte {
+	return self.newNodes
+}
+
+// Update sets the value hash of key, EMPTY_HASH value hash deletes the key
+func (self *SparseMerkleTree) Update(key, valueHash common.Uint256) error {
+	root, err := self.update(self.root, 0, key, valueHash)
+	if err != nil {
+		return err
+	}
+	self.root = root
+	return nil
+}
+
+func (self *SparseMerkleTree) getNode(hash common.Uint256) (*sparseNode, error) {
+	data, ok := self.newNodes[hash]
+	if !ok {
+		var err error
+		data, err = self.store.GetNode(hash)
+		if err != nil {
+			return nil, err
+		}
+		if data == nil {
+			return nil, fmt.Errorf("sparse merkle node %x not found", hash)
+		}
+	}
+	return deserializeSparseNode(data)
+}
+
+func (self *SparseMerkleTree) putNode(node *sparseNode) common.Uint256 {
+	hash := node.hash()
+	self.newNodes[hash] = node.serialize()
+	return hash
+}
+
+func (self *SparseMerkleTree) putLeaf(key, valueHash common.Uint256) common.Uint256 {
+	if valueHash == EMPTY_HASH {
+		return EMPTY_HASH
+	}
+	return self.putNode(&sparseNode{leaf: true, left: key, right: valueHash})
+}
+
+func (self *SparseMerkleTree) update(hash common.Uint256, depth int, key, valueHash common.Uint256) (common.Uint256, error) {
+	if hash == EMPTY_HASH {
+		return self.putLeaf(key, valueHash), nil
+	}
+	node, err := self.getNode(hash)
+	if err != nil {
+		return EMPTY_HASH, err
+	}
+	if node.leaf {
+		if node.left == key {
+			return self.putLeaf(key, valueHash), nil
+		}
+		if valueHash == EMPTY_HASH {
+			return hash, nil
+		}
+		return self.mergeLeaves(depth, hash, node.left, self.putLeaf(key, valueHash), key), nil
+	}
+	left, right := node.left, node.right
+	if sparseKeyBit(key, depth) == 0 {
+		left, err = self.update(left, depth+1, key, valueHash)
+	} else {
+		right, err = self.update(right, depth+1, key, valueHash)
+	}
+	if err != nil {
+		return EMPTY_HASH, err
+	}
+	if left == node.left && right == node.right {
+		return hash, nil
+	}
+	// subtree left with a single leaf collapses to the leaf
+	if left == EMPTY_HASH || right == EMPTY_HASH {
+		child := left
+		if child == EMPTY_HASH {
+			child = right
+		}
+		if child == EMPTY_HASH {
+			return EMPTY_HASH, nil
+		}
+		childNode, err := self.getNode(child)
+		if err != nil {
+			return EMPTY_HASH, err
+		}
+		if childNode.leaf {
+			return child, nil
+		}
+	}
+	return self.putNode(&sparseNode{left: left, right: right}), nil
+}
+
+// mergeLeaves returns the subtree at depth with two leaves of different keys
+func (self *SparseMerkleTree) mergeLeaves(depth int, hashA, keyA, hashB, keyB common.Uint256) common.Uint256 {
+	bitA, bitB := sparseKeyBit(keyA, depth), sparseKeyBit(keyB, depth)
+	if bitA != bitB {
+		if bitA == 0 {
+			return self.putNode(&sparseNode{left: hashA, right: hashB})
+		}
+		return self.putNode(&sparseNode{left: hashB, right: hashA})
+	}
+	child := self.mergeLeaves(depth+1, hashA, keyA, hashB, keyB)
+	if bitA == 0 {
+		return self.putNode(&sparseNode{left: child, right: EMPTY_HASH})
+	}
+	return self.putNode(&sparseNode{left: EMPTY_HASH, right: child})
+}
+
+// Prove returns the proof of key, which proves the value hash of key or that key is absent
+func (self *SparseMerkleTree) Prove(key common.Uint256) (*SparseMerkleProof, error) {
+	proof := &SparseMerkleProof{}
+	hash := self.root
+	for depth := 0; hash != EMPTY_HASH; depth++ {
+		node, err := self.getNode(hash)
+		if err != nil {
+			return nil, err
+		}
+		if node.leaf {
+			proof.LeafKey = node.left
+			proof.LeafValueHash = node.right
+			break
+		}
+		if sparseKeyBit(key, depth) == 0 {
+			proof.Siblings = append(proof.Siblings, node.right)
+			hash = node.left
+		} else {
+			proof.Siblings = append(proof.Siblings, node.left)
+			hash = node.right
+		}
+	}
+	return proof, nil
+}
+
+// SparseMerkleProof is the path from the root to the leaf or empty subtree where the key is located.
+// Siblings are ordered from the root. LeafValueHash is EMPTY_HASH if the path ends with empty subtree.
+type SparseMerkleProof struct {
+	Siblings      []common.Uint256
+	LeafKey       common.Uint256
+	LeafValueHash common.Uint256
+}
+
+func (self *SparseMerkleProof) Serialize(w io.Writer) error {
+	err := serialization.WriteVarUint(w, uint64(len(self.Siblings)))
+	if err != nil {
+		return err
+	}
+	for _, sibling := range self.Siblings {
+		err = sibling.Serialize(w)
+		if err != nil {
+			return err
+		}
+	}
+	err = self.LeafKey.Serialize(w)
+	if err != nil {
+		return err
+	}
+	return self.LeafValueHash.Serialize(w)
+}
+
+func (self *SparseMerkleProof) Deserialize(r io.Reader) error {
+	count, err := serialization.ReadVarUint(r, SPARSE_KEY_BITS)
+	if err != nil {
+		return err
+	}
+	self.Siblings = make([]common.Uint256, count)
+	for i := range self.Siblings {
+		err = self.Siblings[i].Deserialize(r)
+		if err != nil {
+			return err
+		}
+	}
+	err = self.LeafKey.Deserialize(r)
+	if err != nil {
+		return err
+	}
+	return self.LeafValueHash.Deserialize(r)
+}
+
+// VerifySparseMerkleProof checks the proof that key has valueHash in the tree of root.
+// EMPTY_HASH valueHash checks the proof that key is absent.
+func VerifySparseMerkleProof(root, key, valueHash common.Uint256, proof *SparseMerkleProof) error {
+	if len(proof.Siblings) > SPARSE_KEY_BITS {
+		return errors.New("too many siblings in proof")
+	}
+	hash := EMPTY_HASH
+	if proof.LeafValueHash != EMPTY_HASH {
+		// leaf of another key must share the path of key
+		for depth := range proof.Siblings {
+			if sparseKeyBit(proof.LeafKey, depth) != sparseKeyBit(key, depth) {
+				return errors.New("leaf of proof is not on the path of key")
+			}
+		}
+		hash = (&sparseNode{leaf: true, left: proof.LeafKey, right: proof.LeafValueHash}).hash()
+	}
+	if proof.LeafValueHash != EMPTY_HASH && proof.LeafKey == key {
+		if valueHash != proof.LeafValueHash {
+			return errors.New("value hash is inconsistent with proof")
+		}
+	} else if valueHash != EMPTY_HASH {
+		return errors.New("proof shows key is absent")
+	}
+	hasher := TreeHasher{}
+	for depth := len(proof.Siblings) - 1; depth >= 0; depth-- {
+		if sparseKeyBit(key, depth) == 0 {
+			hash = hasher.hash_children(hash, proof.Siblings[depth])
+		} else {
+			hash = hasher.hash_children(proof.Siblings[depth], hash)
+		}
+	}
+	if hash != root {
+		return fmt.Errorf("root of proof %x != %x", hash, root)
+	}
+	return nil
+}
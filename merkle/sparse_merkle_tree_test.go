@@ -0,0 +1,122 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+)
+
+type memSparseNodeStore map[common.Uint256][]byte
+
+func (self memSparseNodeStore) GetNode(hash common.Uint256) ([]byte, error) {
+	return self[hash], nil
+}
+
+func (self memSparseNodeStore) save(tree *SparseMerkleTree) {
+	for hash, data := range tree.NewNodes() {
+		self[hash] = data
+	}
+}
+
+func sparseTestKey(i int) common.Uint256 {
+	return sha256.Sum256([]byte{byte(i)})
+}
+
+func TestSparseMerkleTree(t *testing.T) {
+	store := memSparseNodeStore{}
+	tree := NewSparseMerkleTree(EMPTY_HASH, store)
+	roots := []common.Uint256{tree.Root()}
+	for i := 0; i < 20; i++ {
+		err := tree.Update(sparseTestKey(i), sparseTestKey(i+100))
+		if err != nil {
+			t.Fatal(err)
+		}
+		roots = append(roots, tree.Root())
+	}
+	store.save(tree)
+
+	// root is independent of the order of updates
+	reverse := NewSparseMerkleTree(EMPTY_HASH, memSparseNodeStore{})
+	for i := 19; i >= 0; i-- {
+		reverse.Update(sparseTestKey(i), sparseTestKey(i+100))
+	}
+	if reverse.Root() != tree.Root() {
+		t.Fatalf("root %x != %x", reverse.Root(), tree.Root())
+	}
+
+	for i := 0; i < 25; i++ {
+		proof, err := tree.Prove(sparseTestKey(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		valueHash := EMPTY_HASH
+		if i < 20 {
+			valueHash = sparseTestKey(i + 100)
+		}
+		err = VerifySparseMerkleProof(tree.Root(), sparseTestKey(i), valueHash, proof)
+		if err != nil {
+			t.Fatalf("key %d verify error %s", i, err)
+		}
+		err = VerifySparseMerkleProof(tree.Root(), sparseTestKey(i), sparseTestKey(i+200), proof)
+		if err == nil {
+			t.Fatalf("key %d verify should fail with wrong value", i)
+		}
+
+		buf := bytes.NewBuffer(nil)
+		proof.Serialize(buf)
+		proof2 := new(SparseMerkleProof)
+		err = proof2.Deserialize(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(proof2.Siblings) > 0 {
+			proof2.Siblings[0][0] ^= 1
+			err = VerifySparseMerkleProof(tree.Root(), sparseTestKey(i), valueHash, proof2)
+			if err == nil {
+				t.Fatalf("key %d verify should fail with tampered proof", i)
+			}
+		}
+	}
+
+	// old root can still be proved
+	old := NewSparseMerkleTree(roots[10], store)
+	proof, err := old.Prove(sparseTestKey(15))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = VerifySparseMerkleProof(roots[10], sparseTestKey(15), EMPTY_HASH, proof)
+	if err != nil {
+		t.Fatalf("verify old root error %s", err)
+	}
+
+	// deleting keys restores the old roots
+	for i := 19; i >= 0; i-- {
+		err := tree.Update(sparseTestKey(i), EMPTY_HASH)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tree.Root() != roots[i] {
+			t.Fatalf("root after delete %d %x != %x", i, tree.Root(), roots[i])
+		}
+	}
+}
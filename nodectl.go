@@ -25,9 +25,13 @@ import (
 	"github.com/urfave/cli"
 
 	_ "github.com/ontio/ontology/cli"
+	"github.com/ontio/ontology/cli/block"
 	"github.com/ontio/ontology/cli/common"
+	"github.com/ontio/ontology/cli/rollback"
+	"github.com/ontio/ontology/cli/snapshot"
 	"github.com/ontio/ontology/cli/test"
 	"github.com/ontio/ontology/cli/transfer"
+	"github.com/ontio/ontology/cli/tx"
 	"github.com/ontio/ontology/cli/wallet"
 )
 
@@ -52,6 +56,10 @@ func main() {
 		*test.NewCommand(),
 		*wallet.NewCommand(),
 		*transfer.NewCommand(),
+		*rollback.NewCommand(),
+		*snapshot.NewCommand(),
+		*block.NewCommand(),
+		*tx.NewCommand(),
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 	sort.Sort(cli.FlagsByName(app.Flags))
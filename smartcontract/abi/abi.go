@@ -0,0 +1,151 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package abi
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Parameter types of smart contract method and event
+const (
+	Boolean   = "Boolean"
+	Integer   = "Integer"
+	Hash160   = "Hash160" // Contract or account address
+	Hash256   = "Hash256"
+	ByteArray = "ByteArray"
+	PublicKey = "PublicKey"
+	String    = "String"
+	Array     = "Array"
+	Void      = "Void" // Only used as method return type
+)
+
+var paramTypes = map[string]bool{
+	Boolean:   true,
+	Integer:   true,
+	Hash160:   true,
+	Hash256:   true,
+	ByteArray: true,
+	PublicKey: true,
+	String:    true,
+	Array:     true,
+}
+
+// Parameter describe the name and type of method or event parameter
+type Parameter struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Method describe smart contract method
+type Method struct {
+	Name       string      `json:"name"`
+	Parameters []Parameter `json:"parameters"`
+	ReturnType string      `json:"returntype"`
+}
+
+// Event describe smart contract event, the event name is the first state of notification
+type Event struct {
+	Name       string      `json:"name"`
+	Parameters []Parameter `json:"parameters"`
+}
+
+// ABI describe the methods and events of smart contract
+type ABI struct {
+	EntryPoint string   `json:"entrypoint"`
+	Functions  []Method `json:"functions"`
+	Events     []Event  `json:"events"`
+}
+
+// NewABI parse json data to abi and check it
+func NewABI(data []byte) (*ABI, error) {
+	abi := new(ABI)
+	if err := json.Unmarshal(data, abi); err != nil {
+		return nil, fmt.Errorf("unmarshal abi error %s", err)
+	}
+	if err := abi.Check(); err != nil {
+		return nil, err
+	}
+	return abi, nil
+}
+
+// Check whether names of methods, events and parameters are unique, and parameter types are supported
+func (this *ABI) Check() error {
+	methods := make(map[string]bool)
+	for _, method := range this.Functions {
+		if method.Name == "" || methods[method.Name] {
+			return fmt.Errorf("invalid method name %q", method.Name)
+		}
+		methods[method.Name] = true
+		if method.ReturnType != Void && !paramTypes[method.ReturnType] {
+			return fmt.Errorf("method %s invalid return type %q", method.Name, method.ReturnType)
+		}
+		if err := checkParameters(method.Parameters); err != nil {
+			return fmt.Errorf("method %s %s", method.Name, err)
+		}
+	}
+	if this.EntryPoint != "" && !methods[this.EntryPoint] {
+		return fmt.Errorf("entry point %s is not method", this.EntryPoint)
+	}
+	events := make(map[string]bool)
+	for _, event := range this.Events {
+		if event.Name == "" || events[event.Name] {
+			return fmt.Errorf("invalid event name %q", event.Name)
+		}
+		events[event.Name] = true
+		if err := checkParameters(event.Parameters); err != nil {
+			return fmt.Errorf("event %s %s", event.Name, err)
+		}
+	}
+	return nil
+}
+
+// GetMethod return the method of name, nil if not found
+func (this *ABI) GetMethod(name string) *Method {
+	for i := range this.Functions {
+		if this.Functions[i].Name == name {
+			return &this.Functions[i]
+		}
+	}
+	return nil
+}
+
+// GetEvent return the event of name, nil if not found
+func (this *ABI) GetEvent(name string) *Event {
+	for i := range this.Events {
+		if this.Events[i].Name == name {
+			return &this.Events[i]
+		}
+	}
+	return nil
+}
+
+func checkParameters(params []Parameter) error {
+	names := make(map[string]bool)
+	for _, param := range params {
+		if param.Name == "" || names[param.Name] {
+			return fmt.Errorf("invalid parameter name %q", param.Name)
+		}
+		names[param.Name] = true
+		if !paramTypes[param.Type] {
+			return fmt.Errorf("parameter %s invalid type %q", param.Name, param.Type)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package abi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testABI = `{
+	"entrypoint": "Main",
+	"functions": [
+		{"name": "Main", "parameters": [{"name": "operation", "type": "String"}, {"name": "args", "type": "Array"}], "returntype": "ByteArray"},
+		{"name": "balanceOf", "parameters": [{"name": "address", "type": "Hash160"}], "returntype": "Integer"}
+	],
+	"events": [
+		{"name": "transfer", "parameters": [{"name": "from", "type": "Hash160"}, {"name": "to", "type": "Hash160"}, {"name": "amount", "type": "Integer"}]}
+	]
+}`
+
+func TestNewABI(t *testing.T) {
+	abi, err := NewABI([]byte(testABI))
+	assert.Nil(t, err)
+
+	method := abi.GetMethod("balanceOf")
+	assert.NotNil(t, method)
+	assert.Equal(t, Integer, method.ReturnType)
+	assert.Nil(t, abi.GetMethod("transfer"))
+
+	event := abi.GetEvent("transfer")
+	assert.NotNil(t, event)
+	assert.Equal(t, 3, len(event.Parameters))
+	assert.Equal(t, "amount", event.Parameters[2].Name)
+}
+
+func TestABICheck(t *testing.T) {
+	invalids := []string{
+		`{"functions": [{"name": "", "returntype": "Void"}]}`,
+		`{"functions": [{"name": "a", "returntype": "Void"}, {"name": "a", "returntype": "Void"}]}`,
+		`{"functions": [{"name": "a", "returntype": "Float"}]}`,
+		`{"functions": [{"name": "a", "parameters": [{"name": "p", "type": "Void"}], "returntype": "Void"}]}`,
+		`{"functions": [{"name": "a", "parameters": [{"name": "p", "type": "String"}, {"name": "p", "type": "String"}], "returntype": "Void"}]}`,
+		`{"entrypoint": "Main", "functions": [{"name": "a", "returntype": "Void"}]}`,
+		`{"events": [{"name": "e", "parameters": [{"name": "", "type": "String"}]}]}`,
+		`not json`,
+	}
+	for _, data := range invalids {
+		_, err := NewABI([]byte(data))
+		assert.NotNil(t, err, data)
+	}
+}
@@ -30,6 +30,7 @@ import (
 // when need to check authorization, use CheckWitness
 // when smart contract execute trigger event, use PushNotifications push it to smart contract notifications
 // when need to invoke a smart contract, use AppCall to invoke it
+// when execute opcode or service which cost gas, use CheckUseGas to consume it
 type ContextRef interface {
 	PushContext(context *Context)
 	CurrentContext() *Context
@@ -38,11 +39,13 @@ type ContextRef interface {
 	PopContext()
 	CheckWitness(address common.Address) bool
 	PushNotifications(notifications []*event.NotifyEventInfo)
-	AppCall(address common.Address, method string, codes, args []byte) ([]byte,error)
+	AppCall(address common.Address, method string, codes, args []byte) (interface{}, error)
+	CheckUseGas(gas uint64) bool
 }
 
 // Context describe smart contract execute context struct
 type Context struct {
-	ContractAddress common.Address
-	Code            stypes.VmCode
+	ContractAddress   common.Address
+	Code              stypes.VmCode
+	DelegatedStorages []common.Address // read only storages handed to the called contract
 }
@@ -38,5 +38,9 @@ func PushSmartCodeEvent(txHash common.Uint256, errcode int64, action string, res
 		Result: result,
 		Error:  errcode,
 	}
+	//Event hub isn't initialized when blocks are executed by offline tools
+	if events.DefActorPublisher == nil {
+		return
+	}
 	events.DefActorPublisher.Publish(message.TOPIC_SMART_CODE_EVENT, &message.SmartCodeEventMsg{smartCodeEvt})
 }
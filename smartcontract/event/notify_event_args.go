@@ -37,3 +37,22 @@ type NotifyEventInfo struct {
 	States          interface{}
 }
 
+
+// ContractNotifyEventInfo describe smart contract event notify info with the block height it was emitted at
+type ContractNotifyEventInfo struct {
+	Height uint32
+	Notify *NotifyEventInfo
+}
+
+// GetNotifyEventName return the first element of the notify states, which by convention is the event name
+func GetNotifyEventName(states interface{}) string {
+	arr, ok := states.([]interface{})
+	if !ok || len(arr) == 0 {
+		return ""
+	}
+	name, ok := arr[0].(string)
+	if !ok {
+		return ""
+	}
+	return name
+}
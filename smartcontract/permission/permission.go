@@ -0,0 +1,142 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package permission
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ontio/ontology/common"
+)
+
+// Wildcard matches any contract, method or caller
+const Wildcard = "*"
+
+// Permission describe a contract which may be called and the methods of it
+type Permission struct {
+	Contract string   `json:"contract"` // Hex address of the called contract, or wildcard
+	Methods  []string `json:"methods"`  // Method names, or wildcard
+}
+
+// Permissions describe the calls declared when deploying smart contract
+// Calls are the contracts and methods the contract may call
+// Callers are the hex addresses of contracts which may call the contract
+// A nil list, which is absent in json, is unrestricted, while an empty list permits nothing
+type Permissions struct {
+	Calls   []Permission `json:"calls"`
+	Callers []string     `json:"callers"`
+}
+
+// NewPermissions parse json data to permissions and check it
+func NewPermissions(data []byte) (*Permissions, error) {
+	permissions := new(Permissions)
+	if err := json.Unmarshal(data, permissions); err != nil {
+		return nil, fmt.Errorf("unmarshal permissions error %s", err)
+	}
+	if err := permissions.Check(); err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+// Check whether contract addresses are valid and unique, and method names aren't empty
+func (this *Permissions) Check() error {
+	contracts := make(map[string]bool)
+	for _, call := range this.Calls {
+		if err := checkAddress(call.Contract); err != nil {
+			return fmt.Errorf("call %s", err)
+		}
+		if contracts[call.Contract] {
+			return fmt.Errorf("duplicate call contract %s", call.Contract)
+		}
+		contracts[call.Contract] = true
+		if len(call.Methods) == 0 {
+			return fmt.Errorf("call contract %s without methods", call.Contract)
+		}
+		for _, method := range call.Methods {
+			if method == "" {
+				return fmt.Errorf("call contract %s empty method name", call.Contract)
+			}
+		}
+	}
+	for _, caller := range this.Callers {
+		if err := checkAddress(caller); err != nil {
+			return fmt.Errorf("caller %s", err)
+		}
+	}
+	return nil
+}
+
+// CanCall return whether the method of contract may be called
+// An empty method, which is decided by arguments at runtime, only matches wildcard
+func (this *Permissions) CanCall(contract common.Address, method string) bool {
+	if this.Calls == nil {
+		return true
+	}
+	for _, call := range this.Calls {
+		if !matchAddress(call.Contract, contract) {
+			continue
+		}
+		for _, m := range call.Methods {
+			if m == Wildcard || (method != "" && m == method) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AllowCaller return whether the contract may be called by caller
+func (this *Permissions) AllowCaller(caller common.Address) bool {
+	if this.Callers == nil {
+		return true
+	}
+	for _, c := range this.Callers {
+		if matchAddress(c, caller) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkAddress(address string) error {
+	if address == Wildcard {
+		return nil
+	}
+	if _, err := parseAddress(address); err != nil {
+		return fmt.Errorf("invalid address %q", address)
+	}
+	return nil
+}
+
+func matchAddress(pattern string, address common.Address) bool {
+	if pattern == Wildcard {
+		return true
+	}
+	addr, err := parseAddress(pattern)
+	return err == nil && addr == address
+}
+
+func parseAddress(address string) (common.Address, error) {
+	data, err := common.HexToBytes(address)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return common.AddressParseFromBytes(data)
+}
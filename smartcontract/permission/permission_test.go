@@ -0,0 +1,91 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package permission
+
+import (
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	contractA = common.Address{0x80, 1}
+	contractB = common.Address{0x80, 2}
+	contractC = common.Address{0x80, 3}
+)
+
+func TestNewPermissions(t *testing.T) {
+	data := `{
+		"calls": [
+			{"contract": "` + contractA.ToHexString() + `", "methods": ["transfer", "balanceOf"]},
+			{"contract": "` + contractB.ToHexString() + `", "methods": ["*"]}
+		],
+		"callers": ["` + contractC.ToHexString() + `"]
+	}`
+	permissions, err := NewPermissions([]byte(data))
+	assert.Nil(t, err)
+
+	assert.True(t, permissions.CanCall(contractA, "transfer"))
+	assert.False(t, permissions.CanCall(contractA, "approve"))
+	assert.False(t, permissions.CanCall(contractA, ""))
+	assert.True(t, permissions.CanCall(contractB, "approve"))
+	assert.True(t, permissions.CanCall(contractB, ""))
+	assert.False(t, permissions.CanCall(contractC, "transfer"))
+
+	assert.True(t, permissions.AllowCaller(contractC))
+	assert.False(t, permissions.AllowCaller(contractA))
+}
+
+func TestPermissionsWildcard(t *testing.T) {
+	permissions := &Permissions{
+		Calls:   []Permission{{Contract: Wildcard, Methods: []string{"balanceOf"}}},
+		Callers: []string{Wildcard},
+	}
+	assert.Nil(t, permissions.Check())
+	assert.True(t, permissions.CanCall(contractA, "balanceOf"))
+	assert.True(t, permissions.CanCall(contractC, "balanceOf"))
+	assert.False(t, permissions.CanCall(contractC, "transfer"))
+	assert.True(t, permissions.AllowCaller(contractB))
+
+	unrestricted := new(Permissions)
+	assert.True(t, unrestricted.CanCall(contractA, "balanceOf"))
+	assert.True(t, unrestricted.AllowCaller(contractA))
+
+	empty, err := NewPermissions([]byte(`{"calls": [], "callers": []}`))
+	assert.Nil(t, err)
+	assert.False(t, empty.CanCall(contractA, "balanceOf"))
+	assert.False(t, empty.AllowCaller(contractA))
+}
+
+func TestPermissionsCheck(t *testing.T) {
+	invalids := []string{
+		`{"calls": [{"contract": "0102", "methods": ["*"]}]}`,
+		`{"calls": [{"contract": "zz", "methods": ["*"]}]}`,
+		`{"calls": [{"contract": "*", "methods": []}]}`,
+		`{"calls": [{"contract": "*", "methods": [""]}]}`,
+		`{"calls": [{"contract": "*", "methods": ["a"]}, {"contract": "*", "methods": ["b"]}]}`,
+		`{"callers": ["0102"]}`,
+		`{"calls": {}}`,
+	}
+	for _, data := range invalids {
+		_, err := NewPermissions([]byte(data))
+		assert.NotNil(t, err, data)
+	}
+}
@@ -19,8 +19,6 @@
 package neovm
 
 import (
-	"bytes"
-
 	vm "github.com/ontio/ontology/vm/neovm"
 	"github.com/ontio/ontology/errors"
 	stypes "github.com/ontio/ontology/smartcontract/types"
@@ -46,21 +44,13 @@ func ContractCreate(service *NeoVmService, engine *vm.ExecutionEngine) error {
 
 // ContractMigrate migrate old smart contract to a new contract, and destory old contract
 func ContractMigrate(service *NeoVmService, engine *vm.ExecutionEngine) error {
-	contract, err := isContractParamValid(engine); if err != nil {
-		return errors.NewDetailErr(err, errors.ErrNoCode, "[ContractMigrate] contract parameters invalid!")
-	}
-	contractAddress := contract.Code.AddressFromVmCode()
-
-	if err := isContractExist(service, contractAddress); err != nil {
-		return errors.NewDetailErr(err, errors.ErrNoCode, "[ContractMigrate] contract invalid!")
-	}
+	return migrateContract(service, engine, false)
+}
 
-	service.CloneCache.Add(scommon.ST_CONTRACT, contractAddress[:], contract)
-	if err := storeMigration(service, contractAddress); err != nil {
-		return errors.NewDetailErr(err, errors.ErrNoCode, "[ContractMigrate] contract store migration error!")
-	}
-	vm.PushData(engine, contract)
-	return ContractDestory(service, engine)
+// ContractMigrateForward migrate old smart contract to a new contract, and destory old contract
+// The calls to old contract are forwarded to new contract
+func ContractMigrateForward(service *NeoVmService, engine *vm.ExecutionEngine) error {
+	return migrateContract(service, engine, true)
 }
 
 // ContractDestory destory a contract
@@ -157,24 +147,68 @@ func isContractExist(service *NeoVmService, contractAddress common.Address) erro
 	return nil
 }
 
-func storeMigration(service *NeoVmService, contractAddress common.Address) error {
-	stateValues, err := service.CloneCache.Store.Find(scommon.ST_CONTRACT, contractAddress[:]); if err != nil {
+func migrateContract(service *NeoVmService, engine *vm.ExecutionEngine, forward bool) error {
+	contract, err := isContractParamValid(engine); if err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "[ContractMigrate] contract parameters invalid!")
+	}
+	contractAddress := contract.Code.AddressFromVmCode()
+
+	if err := isContractExist(service, contractAddress); err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "[ContractMigrate] contract invalid!")
+	}
+	context := service.ContextRef.CurrentContext(); if context == nil {
+		return errors.NewErr("[ContractMigrate] current contract context invalid!")
+	}
+
+	service.CloneCache.Add(scommon.ST_CONTRACT, contractAddress[:], contract)
+	if err := storeMigration(service, context.ContractAddress, contractAddress); err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "[ContractMigrate] contract store migration error!")
+	}
+	if err := versionMigration(service, context.ContractAddress, contract, forward); err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "[ContractMigrate] contract version migration error!")
+	}
+	vm.PushData(engine, contract)
+	return ContractDestory(service, engine)
+}
+
+// storeMigration copy the storage items of old contract to new contract
+func storeMigration(service *NeoVmService, oldAddress, newAddress common.Address) error {
+	stateValues, err := service.CloneCache.Find(scommon.ST_STORAGE, oldAddress[:]); if err != nil {
 		return errors.NewDetailErr(err, errors.ErrNoCode, "[Contract] Find error!")
 	}
 	for _, v := range stateValues {
-		key := new(states.StorageKey)
-		bf := bytes.NewBuffer([]byte(v.Key))
-		if err := key.Deserialize(bf); err != nil {
-			return errors.NewErr("[Contract] Key deserialize error!")
-		}
-		key = &states.StorageKey{CodeHash: contractAddress, Key: key.Key}
-		b := new(bytes.Buffer)
-		if _, err := key.Serialize(b); err != nil {
-			return errors.NewErr("[Contract] Key Serialize error!")
-		}
-		service.CloneCache.Add(scommon.ST_STORAGE, key.ToArray(), v.Value)
+		service.CloneCache.Add(scommon.ST_STORAGE, getStorageKey(newAddress, []byte(v.Key)[common.ADDR_LEN:]), v.Value)
 	}
 	return nil
 }
 
-
+// versionMigration record the old contract is superseded by new contract.
+// New contract address with version history, e.g. destroyed after migration, can't be migrated to again.
+func versionMigration(service *NeoVmService, oldAddress common.Address, contract *payload.DeployCode, forward bool) error {
+	item, err := service.CloneCache.Get(scommon.ST_CONTRACT, oldAddress[:]); if err != nil || item == nil {
+		return errors.NewErr("[Contract] Get old contract error!")
+	}
+	successor := contract.Code.AddressFromVmCode()
+	successorVersion, err := service.CloneCache.Get(scommon.ST_CONTRACT_VERSION, successor[:]); if err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "[Contract] Get new contract version error!")
+	}
+	if successorVersion != nil {
+		return errors.NewErr("[Contract] New contract has version history!")
+	}
+	oldVersion := new(states.ContractVersion)
+	versionItem, err := service.CloneCache.Get(scommon.ST_CONTRACT_VERSION, oldAddress[:]); if err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "[Contract] Get old contract version error!")
+	}
+	if versionItem != nil {
+		oldVersion.Predecessor = versionItem.(*states.ContractVersion).Predecessor
+	}
+	oldVersion.Version = item.(*payload.DeployCode).Version
+	oldVersion.Successor = successor
+	oldVersion.Forward = forward
+	service.CloneCache.Add(scommon.ST_CONTRACT_VERSION, oldAddress[:], oldVersion)
+	service.CloneCache.Add(scommon.ST_CONTRACT_VERSION, oldVersion.Successor[:], &states.ContractVersion{
+		Version:     contract.Version,
+		Predecessor: oldAddress,
+	})
+	return nil
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package neovm
+
+import (
+	"testing"
+
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/states"
+	scommon "github.com/ontio/ontology/core/store/common"
+	"github.com/ontio/ontology/core/store/memstore"
+	"github.com/ontio/ontology/core/store/statestore"
+	"github.com/ontio/ontology/smartcontract/storage"
+	stypes "github.com/ontio/ontology/smartcontract/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestContract(code byte, version string) *payload.DeployCode {
+	return &payload.DeployCode{
+		Code:    stypes.VmCode{VmType: stypes.NEOVM, Code: []byte{code}},
+		Version: version,
+	}
+}
+
+func TestVersionMigration(t *testing.T) {
+	service := &NeoVmService{
+		CloneCache: storage.NewCloneCache(statestore.NewStateStoreBatch(statestore.NewMemDatabase(), memstore.NewMemStore())),
+	}
+	oldContract := newTestContract(1, "1.0")
+	oldAddress := oldContract.Code.AddressFromVmCode()
+	newContract := newTestContract(2, "2.0")
+	newAddress := newContract.Code.AddressFromVmCode()
+	service.CloneCache.Add(scommon.ST_CONTRACT, oldAddress[:], oldContract)
+
+	err := versionMigration(service, oldAddress, newContract, true)
+	assert.Nil(t, err)
+	item, err := service.CloneCache.Get(scommon.ST_CONTRACT_VERSION, oldAddress[:])
+	assert.Nil(t, err)
+	assert.Equal(t, &states.ContractVersion{Version: "1.0", Successor: newAddress, Forward: true}, item)
+	item, err = service.CloneCache.Get(scommon.ST_CONTRACT_VERSION, newAddress[:])
+	assert.Nil(t, err)
+	assert.Equal(t, &states.ContractVersion{Version: "2.0", Predecessor: oldAddress}, item)
+
+	// Version history of new contract is kept after it is destroyed, another contract can't be migrated to it
+	otherContract := newTestContract(3, "1.0")
+	otherAddress := otherContract.Code.AddressFromVmCode()
+	service.CloneCache.Add(scommon.ST_CONTRACT, otherAddress[:], otherContract)
+	service.CloneCache.Delete(scommon.ST_CONTRACT, newAddress[:])
+	err = versionMigration(service, otherAddress, newContract, false)
+	assert.NotNil(t, err)
+	item, err = service.CloneCache.Get(scommon.ST_CONTRACT_VERSION, newAddress[:])
+	assert.Nil(t, err)
+	assert.Equal(t, &states.ContractVersion{Version: "2.0", Predecessor: oldAddress}, item)
+	item, err = service.CloneCache.Get(scommon.ST_CONTRACT_VERSION, otherAddress[:])
+	assert.Nil(t, err)
+	assert.Nil(t, item)
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package neovm
+
+import (
+	vm "github.com/ontio/ontology/vm/neovm"
+)
+
+const (
+	OPCODE_GAS  = 1 // Gas of opcode not in OPCODE_GAS_TABLE
+	SYSCALL_GAS = 1 // Gas of syscall not in SYSCALL_GAS_TABLE
+)
+
+var (
+	// Gas of opcode, push opcodes are free. SYSCALL is charged by SYSCALL_GAS_TABLE
+	OPCODE_GAS_TABLE = map[vm.OpCode]uint64{
+		vm.NOP:           0,
+		vm.SYSCALL:       0,
+		vm.APPCALL:       10,
+		vm.TAILCALL:      10,
+		vm.SHA1:          10,
+		vm.SHA256:        10,
+		vm.HASH160:       20,
+		vm.HASH256:       20,
+		vm.CHECKSIG:      100,
+		vm.CHECKMULTISIG: 500,
+	}
+
+	// Gas of syscall by service name
+	SYSCALL_GAS_TABLE = map[string]uint64{
+		"Neo.Blockchain.GetHeader":      100,
+		"Neo.Blockchain.GetBlock":       200,
+		"Neo.Blockchain.GetTransaction": 100,
+		"Neo.Blockchain.GetContract":    100,
+		"Neo.Contract.Create":           5000,
+		"Neo.Contract.Migrate":          5000,
+		"Neo.Contract.MigrateForward":   5000,
+		"Neo.Runtime.CheckSig":          100,
+		"Neo.Storage.Get":               100,
+		"Neo.Storage.Put":               1000,
+		"Neo.Storage.Delete":            100,
+		"Neo.Storage.Find":              100,
+	}
+)
+
+// opCodeGas return the gas of executing opcode
+func opCodeGas(opCode vm.OpCode) uint64 {
+	if opCode <= vm.PUSH16 {
+		return 0
+	}
+	if gas, ok := OPCODE_GAS_TABLE[opCode]; ok {
+		return gas
+	}
+	return OPCODE_GAS
+}
+
+// syscallGas return the gas of invoking syscall service
+func syscallGas(serviceName string) uint64 {
+	if gas, ok := SYSCALL_GAS_TABLE[serviceName]; ok {
+		return gas
+	}
+	return SYSCALL_GAS
+}
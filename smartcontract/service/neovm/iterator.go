@@ -0,0 +1,226 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package neovm
+
+import (
+	"github.com/ontio/ontology/core/states"
+	scommon "github.com/ontio/ontology/core/store/common"
+	"github.com/ontio/ontology/errors"
+	vm "github.com/ontio/ontology/vm/neovm"
+	vmerr "github.com/ontio/ontology/vm/neovm/errors"
+	vtypes "github.com/ontio/ontology/vm/neovm/types"
+)
+
+const (
+	STORAGE_FIND_ITEM_GAS = 10 // Gas of every storage item found by Neo.Storage.Find
+)
+
+// Enumerator enumerates stack items, it is positioned before the first item when created
+type Enumerator interface {
+	Next() bool
+	Value() vtypes.StackItems
+	ToArray() []byte
+}
+
+// Iterator enumerates key value pairs
+type Iterator interface {
+	Enumerator
+	Key() vtypes.StackItems
+}
+
+// StorageIterator iterates the storage items found by Neo.Storage.Find
+type StorageIterator struct {
+	items []*scommon.StateItem
+	index int
+}
+
+// NewStorageIterator return a storage iterator of the items
+func NewStorageIterator(items []*scommon.StateItem) *StorageIterator {
+	return &StorageIterator{items: items, index: -1}
+}
+
+func (this *StorageIterator) Next() bool {
+	if this.index < len(this.items) {
+		this.index++
+	}
+	return this.index < len(this.items)
+}
+
+// Key return storage key without contract address
+func (this *StorageIterator) Key() vtypes.StackItems {
+	if this.index < 0 || this.index >= len(this.items) {
+		return vtypes.NewByteArray([]byte{})
+	}
+	return vtypes.NewByteArray([]byte(this.items[this.index].Key[20:]))
+}
+
+func (this *StorageIterator) Value() vtypes.StackItems {
+	if this.index < 0 || this.index >= len(this.items) {
+		return vtypes.NewByteArray([]byte{})
+	}
+	return vtypes.NewByteArray(this.items[this.index].Value.(*states.StorageItem).Value)
+}
+
+func (this *StorageIterator) ToArray() []byte {
+	return []byte{}
+}
+
+// ArrayIterator iterates the items of array, the key is the index of item
+type ArrayIterator struct {
+	items []vtypes.StackItems
+	index int
+}
+
+// NewArrayIterator return an array iterator of the items
+func NewArrayIterator(items []vtypes.StackItems) *ArrayIterator {
+	return &ArrayIterator{items: items, index: -1}
+}
+
+func (this *ArrayIterator) Next() bool {
+	if this.index < len(this.items) {
+		this.index++
+	}
+	return this.index < len(this.items)
+}
+
+func (this *ArrayIterator) Key() vtypes.StackItems {
+	return vm.NewStackItem(this.index)
+}
+
+func (this *ArrayIterator) Value() vtypes.StackItems {
+	if this.index < 0 || this.index >= len(this.items) {
+		return vtypes.NewByteArray([]byte{})
+	}
+	return this.items[this.index]
+}
+
+func (this *ArrayIterator) ToArray() []byte {
+	return []byte{}
+}
+
+// ConcatEnumerator enumerates the items of first enumerator then second enumerator
+type ConcatEnumerator struct {
+	first   Enumerator
+	second  Enumerator
+	current Enumerator
+}
+
+// NewConcatEnumerator return a enumerator concatenating first and second
+func NewConcatEnumerator(first, second Enumerator) *ConcatEnumerator {
+	return &ConcatEnumerator{first: first, second: second, current: first}
+}
+
+func (this *ConcatEnumerator) Next() bool {
+	if this.current.Next() {
+		return true
+	}
+	if this.current == this.first {
+		this.current = this.second
+		return this.current.Next()
+	}
+	return false
+}
+
+func (this *ConcatEnumerator) Value() vtypes.StackItems {
+	return this.current.Value()
+}
+
+func (this *ConcatEnumerator) ToArray() []byte {
+	return []byte{}
+}
+
+// StorageFind push the iterator of smart contract storage items with the key prefix to vm stack,
+// the items written by the executing transaction are included
+func StorageFind(service *NeoVmService, engine *vm.ExecutionEngine) error {
+	context, err := getContext(engine)
+	if err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "[StorageFind] get pop context error!")
+	}
+	prefix := vm.PopByteArray(engine)
+	items, err := service.CloneCache.Find(scommon.ST_STORAGE, getStorageKey(context.address, prefix))
+	if err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "[StorageFind] find storage error!")
+	}
+	if !service.ContextRef.CheckUseGas(uint64(len(items)) * STORAGE_FIND_ITEM_GAS) {
+		engine.State = vm.FAULT
+		return vmerr.ERR_OUT_OF_GAS
+	}
+	vm.PushData(engine, NewStorageIterator(items))
+	return nil
+}
+
+// EnumeratorCreate push the iterator of array to vm stack
+func EnumeratorCreate(service *NeoVmService, engine *vm.ExecutionEngine) error {
+	vm.PushData(engine, NewArrayIterator(vm.PopArray(engine)))
+	return nil
+}
+
+// EnumeratorNext move enumerator to next item, push whether the item exists to vm stack
+func EnumeratorNext(service *NeoVmService, engine *vm.ExecutionEngine) error {
+	enumerator, err := popEnumerator(engine)
+	if err != nil {
+		return err
+	}
+	vm.PushData(engine, enumerator.Next())
+	return nil
+}
+
+// EnumeratorValue push the value of current item to vm stack
+func EnumeratorValue(service *NeoVmService, engine *vm.ExecutionEngine) error {
+	enumerator, err := popEnumerator(engine)
+	if err != nil {
+		return err
+	}
+	vm.PushData(engine, enumerator.Value())
+	return nil
+}
+
+// EnumeratorConcat push the enumerator concatenating two enumerators to vm stack
+func EnumeratorConcat(service *NeoVmService, engine *vm.ExecutionEngine) error {
+	first, err := popEnumerator(engine)
+	if err != nil {
+		return err
+	}
+	second, err := popEnumerator(engine)
+	if err != nil {
+		return err
+	}
+	vm.PushData(engine, NewConcatEnumerator(first, second))
+	return nil
+}
+
+// IteratorKey push the key of current item to vm stack
+func IteratorKey(service *NeoVmService, engine *vm.ExecutionEngine) error {
+	opInterface := vm.PopInteropInterface(engine)
+	iterator, ok := opInterface.(Iterator)
+	if !ok {
+		return errors.NewErr("[IteratorKey] Wrong type!")
+	}
+	vm.PushData(engine, iterator.Key())
+	return nil
+}
+
+func popEnumerator(engine *vm.ExecutionEngine) (Enumerator, error) {
+	opInterface := vm.PopInteropInterface(engine)
+	enumerator, ok := opInterface.(Enumerator)
+	if !ok {
+		return nil, errors.NewErr("[Enumerator] Wrong type!")
+	}
+	return enumerator, nil
+}
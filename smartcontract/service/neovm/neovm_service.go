@@ -31,6 +31,7 @@ import (
 	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/errors"
 	"github.com/ontio/ontology/smartcontract/states"
+	vmerr "github.com/ontio/ontology/vm/neovm/errors"
 )
 
 const (
@@ -65,6 +66,7 @@ var (
 		"Neo.Transaction.GetAttributes": {Execute: TransactionGetAttributes, Validator: validatorTransaction},
 		"Neo.Contract.Create": {Execute: ContractCreate},
 		"Neo.Contract.Migrate": {Execute: ContractMigrate},
+		"Neo.Contract.MigrateForward": {Execute: ContractMigrateForward},
 		"Neo.Contract.GetStorageContext": {Execute: ContractGetStorageContext},
 		"Neo.Contract.Destroy": {Execute: ContractDestory},
 		"Neo.Contract.GetScript": {Execute: ContractGetCode, Validator: validatorGetCode},
@@ -77,6 +79,18 @@ var (
 		"Neo.Storage.Put": {Execute: StoragePut},
 		"Neo.Storage.Delete": {Execute: StorageDelete},
 		"Neo.Storage.GetContext": {Execute: StorageGetContext},
+		"Neo.Storage.Find": {Execute: StorageFind},
+		"Neo.Storage.GetReadOnlyContext": {Execute: StorageGetReadOnlyContext},
+		"Neo.Storage.GetDelegatedContexts": {Execute: StorageGetDelegatedContexts},
+		"Neo.StorageContext.AsReadOnly": {Execute: StorageContextAsReadOnly, Validator: validatorContext},
+		"Neo.StorageContext.Delegate": {Execute: StorageContextDelegate, Validator: validatorContext},
+		"Neo.Iterator.Next": {Execute: EnumeratorNext, Validator: validatorIterator},
+		"Neo.Iterator.Key": {Execute: IteratorKey, Validator: validatorIterator},
+		"Neo.Iterator.Value": {Execute: EnumeratorValue, Validator: validatorIterator},
+		"Neo.Enumerator.Create": {Execute: EnumeratorCreate, Validator: validatorEnumeratorCreate},
+		"Neo.Enumerator.Next": {Execute: EnumeratorNext, Validator: validatorEnumerator},
+		"Neo.Enumerator.Value": {Execute: EnumeratorValue, Validator: validatorEnumerator},
+		"Neo.Enumerator.Concat": {Execute: EnumeratorConcat, Validator: validatorEnumeratorConcat},
 		"System.ExecutionEngine.GetScriptContainer": {Execute: GetCodeContainer},
 		"System.ExecutionEngine.GetExecutingScriptHash": {Execute: GetExecutingAddress},
 		"System.ExecutionEngine.GetCallingScriptHash": {Execute: GetCallingAddress},
@@ -123,15 +137,16 @@ func NewNeoVmService(store store.LedgerStore, dbCache scommon.StateStore, tx *ty
 	return &service
 }
 
-// Invoke a smart contract
-func (this *NeoVmService) Invoke() error {
+// Invoke a smart contract, return the stack item on the top of evaluation stack as result
+// Every opcode and syscall cost gas, if gas is out, execute fault and cache is not committed
+func (this *NeoVmService) Invoke() (vmtype.StackItems, error) {
 	engine := vm.NewExecutionEngine()
 	ctx := this.ContextRef.CurrentContext()
 	if ctx == nil {
-		return ERR_CURRENT_CONTEXT_NIL
+		return nil, ERR_CURRENT_CONTEXT_NIL
 	}
 	if len(ctx.Code.Code) == 0 {
-		return ERR_EXECUTE_CODE
+		return nil, ERR_EXECUTE_CODE
 	}
 	engine.PushContext(vm.NewExecutionContext(engine, ctx.Code.Code))
 	for {
@@ -142,41 +157,59 @@ func (this *NeoVmService) Invoke() error {
 			break
 		}
 		if err := engine.ExecuteCode(); err != nil {
-			return err
+			return nil, err
 		}
 		if engine.Context.GetInstructionPointer() < len(engine.Context.Code) {
 			if ok := checkStackSize(engine); !ok {
-				return ERR_CHECK_STACK_SIZE
+				return nil, ERR_CHECK_STACK_SIZE
 			}
 			if ok := checkArraySize(engine); !ok {
-				return ERR_CHECK_ARRAY_SIZE
+				return nil, ERR_CHECK_ARRAY_SIZE
 			}
 			if ok := checkBigIntegers(engine); !ok {
-				return ERR_CHECK_BIGINTEGER
+				return nil, ERR_CHECK_BIGINTEGER
 			}
 		}
+		if !this.ContextRef.CheckUseGas(opCodeGas(engine.OpCode)) {
+			engine.State = vm.FAULT
+			return nil, vmerr.ERR_OUT_OF_GAS
+		}
 		switch engine.OpCode {
 		case vm.SYSCALL:
 			if err := this.SystemCall(engine); err != nil {
-				return errors.NewDetailErr(err, errors.ErrNoCode, "[NeoVmService] service system call error!")
+				if err == vmerr.ERR_OUT_OF_GAS {
+					return nil, err
+				}
+				return nil, errors.NewDetailErr(err, errors.ErrNoCode, "[NeoVmService] service system call error!")
 			}
 		case vm.APPCALL:
 			c := new(states.Contract)
 			if err := c.Deserialize(engine.Context.OpReader.Reader()); err != nil {
-				return errors.NewDetailErr(err, errors.ErrNoCode, "[NeoVmService] get contract parameters error!")
+				return nil, errors.NewDetailErr(err, errors.ErrNoCode, "[NeoVmService] get contract parameters error!")
 			}
-			if _,err := this.ContextRef.AppCall(c.Address, c.Method, c.Code, c.Args); err != nil {
-				return errors.NewDetailErr(err, errors.ErrNoCode, "[NeoVmService] service app call error!")
+			result, err := this.ContextRef.AppCall(c.Address, c.Method, c.Code, c.Args)
+			if err != nil {
+				return nil, errors.NewDetailErr(err, errors.ErrNoCode, "[NeoVmService] service app call error!")
 			}
+			// the result of neovm contract is pushed to the evaluation stack of caller.
+			// Contracts deployed before it don't expect the extra item after APPCALL, so their execution may change.
+			if item, ok := result.(vmtype.StackItems); ok {
+				vm.PushData(engine, item)
+			}
+			// delegated storages are only handed to the next called contract
+			this.ContextRef.CurrentContext().DelegatedStorages = nil
 		default:
 			if err := engine.StepInto(); err != nil {
-				return errors.NewDetailErr(err, errors.ErrNoCode, "[NeoVmService] vm execute error!")
+				return nil, errors.NewDetailErr(err, errors.ErrNoCode, "[NeoVmService] vm execute error!")
 			}
 		}
 	}
 	this.ContextRef.PushNotifications(this.Notifications)
 	this.CloneCache.Commit()
-	return nil
+	if engine.EvaluationStack.Count() > 0 {
+		return vm.PeekStackItem(engine), nil
+	}
+	return nil, nil
 }
 
 // SystemCall provide register service for smart contract to interaction with blockchian
@@ -186,6 +219,10 @@ func (this *NeoVmService) SystemCall(engine *vm.ExecutionEngine) error {
 	if !ok {
 		return errors.NewErr("[SystemCall] service not support!")
 	}
+	if !this.ContextRef.CheckUseGas(syscallGas(serviceName)) {
+		engine.State = vm.FAULT
+		return vmerr.ERR_OUT_OF_GAS
+	}
 	if service.Validator != nil {
 		if err := service.Validator(engine); err != nil {
 			return errors.NewDetailErr(err, errors.ErrNoCode, "[SystemCall] service validator error!")
@@ -193,6 +230,9 @@ func (this *NeoVmService) SystemCall(engine *vm.ExecutionEngine) error {
 	}
 
 	if err := service.Execute(this, engine); err != nil {
+		if err == vmerr.ERR_OUT_OF_GAS {
+			return err
+		}
 		return errors.NewDetailErr(err, errors.ErrNoCode, "[SystemCall] service execute error!")
 	}
 	return nil
@@ -26,7 +26,6 @@ import (
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/ontio/ontology/smartcontract/event"
 	scommon "github.com/ontio/ontology/smartcontract/common"
-	"github.com/ontio/ontology/core/signature"
 )
 
 // HeaderGetNextConsensus put current block time to vm stack
@@ -79,7 +78,8 @@ func RuntimeCheckSig(service *NeoVmService, engine *vm.ExecutionEngine) error {
 	pubKey := vm.PopByteArray(engine)
 	data := vm.PopByteArray(engine)
 	sig := vm.PopByteArray(engine)
-	return signature.Verify(pubKey, data, sig)
+	vm.PushData(engine, vm.CheckSig(pubKey, data, sig))
+	return nil
 }
 
 
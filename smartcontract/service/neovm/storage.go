@@ -26,6 +26,7 @@ import (
 	"github.com/ontio/ontology/errors"
 	"github.com/ontio/ontology/core/states"
 	"github.com/ontio/ontology/common"
+	vtypes "github.com/ontio/ontology/vm/neovm/types"
 )
 
 // StoragePut put smart contract storage item to cache
@@ -85,7 +86,50 @@ func StorageGetContext(service *NeoVmService, engine *vm.ExecutionEngine) error
 	return nil
 }
 
+// StorageGetReadOnlyContext push smart contract read only storage context to vm stack
+func StorageGetReadOnlyContext(service *NeoVmService, engine *vm.ExecutionEngine) error {
+	vm.PushData(engine, NewReadOnlyStorageContext(service.ContextRef.CurrentContext().ContractAddress))
+	return nil
+}
+
+// StorageContextAsReadOnly push the read only copy of storage context to vm stack
+func StorageContextAsReadOnly(service *NeoVmService, engine *vm.ExecutionEngine) error {
+	context, err := popStorageContext(engine); if err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "[StorageContextAsReadOnly] pop context error!")
+	}
+	vm.PushData(engine, context.AsReadOnly())
+	return nil
+}
+
+// StorageContextDelegate hand the read only copy of storage context to the contract called by next AppCall
+func StorageContextDelegate(service *NeoVmService, engine *vm.ExecutionEngine) error {
+	context, err := popStorageContext(engine); if err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "[StorageContextDelegate] pop context error!")
+	}
+	ctx := service.ContextRef.CurrentContext()
+	ctx.DelegatedStorages = append(ctx.DelegatedStorages, context.address)
+	return nil
+}
+
+// StorageGetDelegatedContexts push the read only storage contexts handed by calling contract to vm stack
+func StorageGetDelegatedContexts(service *NeoVmService, engine *vm.ExecutionEngine) error {
+	var contexts []vtypes.StackItems
+	if calling := service.ContextRef.CallingContext(); calling != nil {
+		for _, address := range calling.DelegatedStorages {
+			contexts = append(contexts, vm.NewStackItem(NewReadOnlyStorageContext(address)))
+		}
+	}
+	vm.PushData(engine, vtypes.NewArray(contexts))
+	return nil
+}
+
 func checkStorageContext(service *NeoVmService, context *StorageContext) error {
+	if context.IsReadOnly() {
+		return errors.NewErr("[CheckStorageContext] storage context is read only!")
+	}
+	if context.address != service.ContextRef.CurrentContext().ContractAddress {
+		return errors.NewErr("[CheckStorageContext] storage context is not executing contract!")
+	}
 	item, err := service.CloneCache.Get(scommon.ST_CONTRACT, context.address[:])
 	if err != nil || item == nil {
 		return errors.NewDetailErr(err, errors.ErrNoCode, "[CheckStorageContext] get context fail!")
@@ -106,6 +150,13 @@ func getContext(engine *vm.ExecutionEngine) (*StorageContext, error) {
 	return context, nil
 }
 
+func popStorageContext(engine *vm.ExecutionEngine) (*StorageContext, error) {
+	context, ok := vm.PopInteropInterface(engine).(*StorageContext); if !ok {
+		return nil, errors.NewErr("[Context] Get storageContext invalid")
+	}
+	return context, nil
+}
+
 func getStorageKey(codeHash common.Address, key []byte) []byte {
 	buf := bytes.NewBuffer(nil)
 	buf.Write(codeHash[:])
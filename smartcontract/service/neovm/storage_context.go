@@ -22,9 +22,10 @@ import (
 	"github.com/ontio/ontology/common"
 )
 
-// StorageContext store smart contract address
+// StorageContext store smart contract address and whether the storage is read only
 type StorageContext struct {
-	address common.Address
+	address    common.Address
+	isReadOnly bool
 }
 
 // NewStorageContext return a new smart contract storage context
@@ -34,6 +35,23 @@ func NewStorageContext(address common.Address) *StorageContext {
 	return &storageContext
 }
 
+// NewReadOnlyStorageContext return a new smart contract storage context which can only be used to read storage
+func NewReadOnlyStorageContext(address common.Address) *StorageContext {
+	storageContext := NewStorageContext(address)
+	storageContext.isReadOnly = true
+	return storageContext
+}
+
+// IsReadOnly return whether storage context can only be used to read storage
+func (this *StorageContext) IsReadOnly() bool {
+	return this.isReadOnly
+}
+
+// AsReadOnly return a read only storage context of the same smart contract
+func (this *StorageContext) AsReadOnly() *StorageContext {
+	return NewReadOnlyStorageContext(this.address)
+}
+
 // ToArray return address byte array
 func (this *StorageContext) ToArray() []byte {
 	return this.address[:]
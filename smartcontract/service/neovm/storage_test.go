@@ -0,0 +1,186 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package neovm
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/core/states"
+	scommon "github.com/ontio/ontology/core/store/common"
+	"github.com/ontio/ontology/core/store/memstore"
+	"github.com/ontio/ontology/core/store/statestore"
+	"github.com/ontio/ontology/smartcontract/context"
+	"github.com/ontio/ontology/smartcontract/event"
+	sstates "github.com/ontio/ontology/smartcontract/states"
+	"github.com/ontio/ontology/smartcontract/storage"
+	vm "github.com/ontio/ontology/vm/neovm"
+	"github.com/stretchr/testify/assert"
+)
+
+// testContextRef keep the contexts of executing contracts, AppCall is handled by appCall
+type testContextRef struct {
+	contexts []*context.Context
+	appCall  func(address common.Address) error
+}
+
+func (this *testContextRef) PushContext(context *context.Context) {
+	this.contexts = append(this.contexts, context)
+}
+
+func (this *testContextRef) CurrentContext() *context.Context {
+	if len(this.contexts) < 1 {
+		return nil
+	}
+	return this.contexts[len(this.contexts)-1]
+}
+
+func (this *testContextRef) CallingContext() *context.Context {
+	if len(this.contexts) < 2 {
+		return nil
+	}
+	return this.contexts[len(this.contexts)-2]
+}
+
+func (this *testContextRef) EntryContext() *context.Context {
+	if len(this.contexts) < 1 {
+		return nil
+	}
+	return this.contexts[0]
+}
+
+func (this *testContextRef) PopContext() {
+	this.contexts = this.contexts[:len(this.contexts)-1]
+}
+
+func (this *testContextRef) CheckWitness(address common.Address) bool {
+	return false
+}
+
+func (this *testContextRef) PushNotifications(notifications []*event.NotifyEventInfo) {
+}
+
+func (this *testContextRef) AppCall(address common.Address, method string, codes, args []byte) (interface{}, error) {
+	return nil, this.appCall(address)
+}
+
+func (this *testContextRef) CheckUseGas(gas uint64) bool {
+	return true
+}
+
+// newTestStorageService return the service executing contract of code, and the contract address
+func newTestStorageService(code []byte) (*NeoVmService, common.Address) {
+	contract := newTestContract(0, "1.0")
+	contract.Code.Code = code
+	address := contract.Code.AddressFromVmCode()
+	ref := new(testContextRef)
+	ref.PushContext(&context.Context{ContractAddress: address, Code: contract.Code})
+	service := &NeoVmService{
+		CloneCache: storage.NewCloneCache(statestore.NewStateStoreBatch(statestore.NewMemDatabase(), memstore.NewMemStore())),
+		ContextRef: ref,
+	}
+	service.CloneCache.Add(scommon.ST_CONTRACT, address[:], contract)
+	return service, address
+}
+
+func storagePut(service *NeoVmService, context *StorageContext, key, value []byte) error {
+	engine := vm.NewExecutionEngine()
+	vm.PushData(engine, value)
+	vm.PushData(engine, key)
+	vm.PushData(engine, context)
+	return StoragePut(service, engine)
+}
+
+func storageDelete(service *NeoVmService, context *StorageContext, key []byte) error {
+	engine := vm.NewExecutionEngine()
+	vm.PushData(engine, key)
+	vm.PushData(engine, context)
+	return StorageDelete(service, engine)
+}
+
+func TestStorageContextCheck(t *testing.T) {
+	service, address := newTestStorageService([]byte{byte(vm.PUSH1)})
+	other := newTestContract(2, "1.0")
+	otherAddress := other.Code.AddressFromVmCode()
+	service.CloneCache.Add(scommon.ST_CONTRACT, otherAddress[:], other)
+	key := []byte("key")
+
+	assert.Nil(t, storagePut(service, NewStorageContext(address), key, []byte("value")))
+	item, err := service.CloneCache.Get(scommon.ST_STORAGE, getStorageKey(address, key))
+	assert.Nil(t, err)
+	assert.Equal(t, &states.StorageItem{Value: []byte("value")}, item)
+
+	for _, context := range []*StorageContext{
+		NewReadOnlyStorageContext(address),
+		NewStorageContext(address).AsReadOnly(),
+		NewStorageContext(otherAddress),
+	} {
+		assert.NotNil(t, storagePut(service, context, key, []byte("other")))
+		assert.NotNil(t, storageDelete(service, context, key))
+	}
+	item, err = service.CloneCache.Get(scommon.ST_STORAGE, getStorageKey(address, key))
+	assert.Nil(t, err)
+	assert.Equal(t, &states.StorageItem{Value: []byte("value")}, item)
+	item, err = service.CloneCache.Get(scommon.ST_STORAGE, getStorageKey(otherAddress, key))
+	assert.Nil(t, err)
+	assert.Nil(t, item)
+
+	assert.Nil(t, storageDelete(service, NewStorageContext(address), key))
+	item, err = service.CloneCache.Get(scommon.ST_STORAGE, getStorageKey(address, key))
+	assert.Nil(t, err)
+	assert.Nil(t, item)
+}
+
+func TestStorageDelegatedContexts(t *testing.T) {
+	callee := common.Address{1}
+	code := new(bytes.Buffer)
+	for _, name := range []string{"Neo.Storage.GetContext", "Neo.StorageContext.Delegate"} {
+		code.WriteByte(byte(vm.SYSCALL))
+		serialization.WriteString(code, name)
+	}
+	// the storage is delegated to the first call, and not to the second call
+	for i := 0; i < 2; i++ {
+		code.WriteByte(byte(vm.APPCALL))
+		assert.Nil(t, (&sstates.Contract{Address: callee}).Serialize(code))
+	}
+	service, address := newTestStorageService(code.Bytes())
+
+	var delegated [][]*StorageContext
+	ref := service.ContextRef.(*testContextRef)
+	ref.appCall = func(address common.Address) error {
+		ref.PushContext(&context.Context{ContractAddress: address})
+		defer ref.PopContext()
+		engine := vm.NewExecutionEngine()
+		if err := StorageGetDelegatedContexts(service, engine); err != nil {
+			return err
+		}
+		var contexts []*StorageContext
+		for _, item := range vm.PopArray(engine) {
+			contexts = append(contexts, item.GetInterface().(*StorageContext))
+		}
+		delegated = append(delegated, contexts)
+		return nil
+	}
+
+	_, err := service.Invoke()
+	assert.Nil(t, err)
+	assert.Equal(t, [][]*StorageContext{{NewReadOnlyStorageContext(address)}, nil}, delegated)
+	assert.Nil(t, ref.CurrentContext().DelegatedStorages)
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/ontio/ontology/errors"
 	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/core/payload"
+	vtypes "github.com/ontio/ontology/vm/neovm/types"
 )
 
 func validatorAttribute(engine *vm.ExecutionEngine) error {
@@ -166,3 +167,54 @@ func peekBlock(engine *vm.ExecutionEngine) (*types.Block, error) {
 	return block, nil
 }
 
+func validatorEnumerator(engine *vm.ExecutionEngine) error {
+	if vm.EvaluationStackCount(engine) < 1 {
+		return errors.NewErr("[validatorEnumerator] Too few input parameters ")
+	}
+	if _, ok := vm.PeekInteropInterface(engine).(Enumerator); !ok {
+		return errors.NewErr("[validatorEnumerator] Wrong type!")
+	}
+	return nil
+}
+
+func validatorIterator(engine *vm.ExecutionEngine) error {
+	if vm.EvaluationStackCount(engine) < 1 {
+		return errors.NewErr("[validatorIterator] Too few input parameters ")
+	}
+	if _, ok := vm.PeekInteropInterface(engine).(Iterator); !ok {
+		return errors.NewErr("[validatorIterator] Wrong type!")
+	}
+	return nil
+}
+
+func validatorEnumeratorCreate(engine *vm.ExecutionEngine) error {
+	if vm.EvaluationStackCount(engine) < 1 {
+		return errors.NewErr("[validatorEnumeratorCreate] Too few input parameters ")
+	}
+	if _, ok := vm.PeekStackItem(engine).(*vtypes.Array); !ok {
+		return errors.NewErr("[validatorEnumeratorCreate] Wrong type!")
+	}
+	return nil
+}
+
+func validatorEnumeratorConcat(engine *vm.ExecutionEngine) error {
+	if vm.EvaluationStackCount(engine) < 2 {
+		return errors.NewErr("[validatorEnumeratorConcat] Too few input parameters ")
+	}
+	for i := 0; i < 2; i++ {
+		if _, ok := vm.PeekNStackItem(i, engine).GetInterface().(Enumerator); !ok {
+			return errors.NewErr("[validatorEnumeratorConcat] Wrong type!")
+		}
+	}
+	return nil
+}
+
+func validatorContext(engine *vm.ExecutionEngine) error {
+	if vm.EvaluationStackCount(engine) < 1 {
+		return errors.NewErr("[validatorContext] Too few input parameters ")
+	}
+	if _, ok := vm.PeekInteropInterface(engine).(*StorageContext); !ok {
+		return errors.NewErr("[validatorContext] Wrong type!")
+	}
+	return nil
+}
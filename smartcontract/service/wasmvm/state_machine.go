@@ -96,6 +96,9 @@ func (s *WasmStateMachine) putstore(engine *exec.ExecutionEngine) (bool, error)
 	if err != nil {
 		return false, err
 	}
+	if err := engine.UseGas(uint64(len(key)+len(value)) * exec.STORAGE_BYTE_GAS); err != nil {
+		return false, err
+	}
 	k, err := serializeStorageKey(vm.ContractAddress, key)
 	if err != nil {
 		return false, err
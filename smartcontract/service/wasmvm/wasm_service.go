@@ -51,6 +51,8 @@ func (this *WasmVmService)Invoke() ([]byte,error){
 		new(util.ECDsaCrypto),
 		stateMachine,
 	)
+	//the gas is shared with the nested contract calls through context
+	engine.GasMeter = this.ContextRef
 
 	contract := &states.Contract{}
 	contract.Deserialize(bytes.NewBuffer(ctx.Code.Code))
@@ -127,16 +129,24 @@ func(this *WasmVmService)callContract(engine *exec.ExecutionEngine)(bool,error){
 	if err != nil {
 		return false, errors.NewErr("[callContract]get Contract arg failed:" + err.Error())
 	}
+	if err := engine.UseGas(uint64(len(arg)) * exec.MEMORY_BYTE_GAS); err != nil {
+		return false, err
+	}
 	//todo get result from AppCall
 	//res := 0
 	result ,err := this.ContextRef.AppCall(contractAddress,util.TrimBuffToString(methodName),nil,arg)
+	if err == exec.ErrOutOfGas || errors.ErrerCode(err) == errors.ErrCallPermission {
+		return false, err
+	}
 	if err != nil {
 		return false, errors.NewErr("[callContract]AppCall failed:" + err.Error())
 	}
 
 	vm.RestoreCtx()
 	if envCall.GetReturns() {
-		idx,err := vm.SetPointerMemory(result)
+		// only the byte array result of wasm contract can be returned
+		res, _ := result.([]byte)
+		idx,err := vm.SetPointerMemory(res)
 		if err != nil {
 			return false, errors.NewErr("[callContract]SetPointerMemory failed:" + err.Error())
 		}
@@ -18,6 +18,8 @@ package smartcontract
 
 import (
 	"bytes"
+	"fmt"
+
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/core/store"
 	scommon "github.com/ontio/ontology/core/store/common"
@@ -29,15 +31,23 @@ import (
 	stypes "github.com/ontio/ontology/smartcontract/types"
 	"github.com/ontio/ontology/smartcontract/service/neovm"
 	"github.com/ontio/ontology/core/payload"
+	cstates "github.com/ontio/ontology/core/states"
 	"github.com/ontio/ontology/smartcontract/states"
 	vm "github.com/ontio/ontology/vm/neovm"
 	"github.com/ontio/ontology/smartcontract/service/wasmvm"
+	"github.com/ontio/ontology/smartcontract/permission"
+)
+
+const (
+	MAX_FORWARD_DEPTH = 64 // Max number of successors followed when calling a migrated contract
+	DEFAULT_GAS_LIMIT = 100000 // Gas of executing invoke transaction which doesn't set gas limit
 )
 
 var (
 	CONTRACT_NOT_EXIST = errors.NewErr("[AppCall] Get contract context nil")
 	DEPLOYCODE_TYPE_ERROR = errors.NewErr("[AppCall] DeployCode type error!")
 	INVOKE_CODE_EXIST = errors.NewErr("[AppCall] Invoke codes exist!")
+	CALL_PERMISSION_DENIED = errors.NewErr("[AppCall] Contract call permission denied!")
 )
 
 // SmartContract describe smart contract execute engine
@@ -46,6 +56,7 @@ type SmartContract struct {
 	Config        *Config
 	Engine        Engine
 	Notifications []*event.NotifyEventInfo // all execute smart contract event notify info
+	Gas           uint64                   // remain gas of smart contract execute
 }
 
 // Config describe smart contract need parameters configuration
@@ -61,6 +72,15 @@ type Engine interface {
 	Invoke()
 }
 
+// GetGasLimit return the gas of executing invoke transaction
+// If gas limit of transaction isn't set, return DEFAULT_GAS_LIMIT
+func GetGasLimit(gasLimit common.Fixed64) uint64 {
+	if gasLimit > 0 {
+		return uint64(gasLimit)
+	}
+	return DEFAULT_GAS_LIMIT
+}
+
 // PushContext push current context to smart contract
 func (this *SmartContract) PushContext(context *context.Context) {
 	this.Contexts = append(this.Contexts, context)
@@ -104,7 +124,8 @@ func (this *SmartContract) PushNotifications(notifications []*event.NotifyEventI
 
 // Execute is smart contract execute manager
 // According different vm type to launch different service
-func (this *SmartContract) Execute() ([]byte, error) {
+// The result of neovm is the stack item on the top of evaluation stack, the result of wasmvm is byte array
+func (this *SmartContract) Execute() (interface{}, error) {
 	ctx := this.CurrentContext()
 	switch ctx.Code.VmType {
 	case stypes.Native:
@@ -114,10 +135,14 @@ func (this *SmartContract) Execute() ([]byte, error) {
 		}
 	case stypes.NEOVM:
 		service := neovm.NewNeoVmService(this.Config.Store, this.Config.DBCache, this.Config.Tx, this.Config.Time, this)
-		if err := service.Invoke(); err != nil {
+		result, err := service.Invoke()
+		if err != nil {
 			//fmt.Println("execute neovm error:", err)
 			return nil, err
 		}
+		if result != nil {
+			return result, nil
+		}
 	case stypes.WASMVM:
 		service := wasmvm.NewWasmVmService(this.Config.Store, this.Config.DBCache, this.Config.Tx, this.Config.Time, this)
 		result, err := service.Invoke()
@@ -134,11 +159,23 @@ func (this *SmartContract) Execute() ([]byte, error) {
 // Param method: invoke smart contract method name
 // Param codes: invoke smart contract off blockchain
 // Param args: invoke smart contract args
-func (this *SmartContract) AppCall(address common.Address, method string, codes, args []byte) ([]byte, error) {
+func (this *SmartContract) AppCall(address common.Address, method string, codes, args []byte) (interface{}, error) {
 	var code []byte
 
 	vmType := stypes.VmType(address[0])
 
+	callee := address
+	if vmType == stypes.NEOVM && len(codes) == 0 {
+		forward, err := this.getForwardAddress(address)
+		if err != nil {
+			return nil, err
+		}
+		address = forward
+	}
+	if err := this.checkCallPermission(callee, address, method); err != nil {
+		return nil, err
+	}
+
 	switch vmType {
 	case stypes.Native:
 		bf := new(bytes.Buffer)
@@ -193,6 +230,32 @@ func (this *SmartContract) AppCall(address common.Address, method string, codes,
 	return res, nil
 }
 
+// checkCallPermission check whether current contract may call the method of callee, and target accepts it as caller
+// Param callee: the contract address called by current contract
+// Param target: the contract address actually executed, which differs from callee when the call is forwarded
+// Contracts deployed without permissions, native contracts and off blockchain codes are unrestricted
+func (this *SmartContract) checkCallPermission(callee, target common.Address, method string) error {
+	caller := this.CurrentContext()
+	if caller == nil {
+		return nil
+	}
+	permissions, err := this.getPermissions(caller.ContractAddress); if err != nil {
+		return err
+	}
+	if permissions != nil && !permissions.CanCall(callee, method) {
+		return errors.NewDetailErr(CALL_PERMISSION_DENIED, errors.ErrCallPermission,
+			fmt.Sprintf("[AppCall] contract %x isn't permitted to call contract %x method %q", caller.ContractAddress, callee, method))
+	}
+	permissions, err = this.getPermissions(target); if err != nil {
+		return err
+	}
+	if permissions != nil && !permissions.AllowCaller(caller.ContractAddress) {
+		return errors.NewDetailErr(CALL_PERMISSION_DENIED, errors.ErrCallPermission,
+			fmt.Sprintf("[AppCall] contract %x doesn't accept caller %x", target, caller.ContractAddress))
+	}
+	return nil
+}
+
 // CheckWitness check whether authorization correct
 // If address is wallet address, check whether in the signature addressed list
 // Else check whether address is calling contract address
@@ -214,6 +277,16 @@ func (this *SmartContract) CheckWitness(address common.Address) bool {
 	return false
 }
 
+// CheckUseGas consume gas from remain gas of execute
+// If remain gas is not enough, return false
+func (this *SmartContract) CheckUseGas(gas uint64) bool {
+	if this.Gas < gas {
+		return false
+	}
+	this.Gas -= gas
+	return true
+}
+
 // loadCode load smart contract execute code
 // Param address, invoke on blockchain smart contract address
 // Param codes, invoke off blockchain smart contract code
@@ -243,6 +316,44 @@ func (this *SmartContract) loadCode(address common.Address, codes []byte) ([]byt
 	}
 }
 
+// getForwardAddress return the successor which calls to the migrated contract are forwarded to
+// If contract exists or isn't forwarded, return the address itself
+func (this *SmartContract) getForwardAddress(address common.Address) (common.Address, error) {
+	for i := 0; i < MAX_FORWARD_DEPTH; i++ {
+		item, err := this.getContract(address[:]); if err != nil {
+			return address, err
+		}
+		if item != nil {
+			return address, nil
+		}
+		item, err = this.Config.DBCache.TryGet(scommon.ST_CONTRACT_VERSION, address[:]); if err != nil {
+			return address, errors.NewErr("[getForwardAddress] Get contract version error!")
+		}
+		if item == nil {
+			return address, nil
+		}
+		version, ok := item.Value.(*cstates.ContractVersion); if !ok || !version.Forward {
+			return address, nil
+		}
+		address = version.Successor
+	}
+	return address, nil
+}
+
+// getPermissions return the permissions declared when contract deployed, nil if contract is unrestricted
+func (this *SmartContract) getPermissions(address common.Address) (*permission.Permissions, error) {
+	item, err := this.getContract(address[:]); if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, nil
+	}
+	contract, ok := item.Value.(*payload.DeployCode); if !ok {
+		return nil, DEPLOYCODE_TYPE_ERROR
+	}
+	return contract.Permissions, nil
+}
+
 func (this *SmartContract) getContract(address []byte) (*scommon.StateItem, error) {
 	item, err := this.Config.DBCache.TryGet(scommon.ST_CONTRACT, address[:]);
 	if err != nil {
@@ -0,0 +1,203 @@
+// Copyright 2017 The Ontology Authors
+// This file is part of the Ontology library.
+//
+// The Ontology library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Ontology library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Ontology library. If not, see <http://www.gnu.org/licenses/>.
+
+package smartcontract
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/payload"
+	cstates "github.com/ontio/ontology/core/states"
+	scommon "github.com/ontio/ontology/core/store/common"
+	"github.com/ontio/ontology/errors"
+	"github.com/ontio/ontology/smartcontract/context"
+	"github.com/ontio/ontology/smartcontract/permission"
+	"github.com/ontio/ontology/smartcontract/states"
+	stypes "github.com/ontio/ontology/smartcontract/types"
+	vm "github.com/ontio/ontology/vm/neovm"
+	vmtype "github.com/ontio/ontology/vm/neovm/types"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	contractA = common.Address{byte(stypes.NEOVM), 1}
+	contractB = common.Address{byte(stypes.NEOVM), 2}
+	contractC = common.Address{byte(stypes.NEOVM), 3}
+)
+
+type testStateStore map[string]*scommon.StateItem
+
+func (this testStateStore) TryAdd(prefix scommon.DataEntryPrefix, key []byte, value cstates.StateValue, trie bool) {
+	this[string(append([]byte{byte(prefix)}, key...))] = &scommon.StateItem{Key: string(key), Value: value}
+}
+
+func (this testStateStore) TryGetOrAdd(prefix scommon.DataEntryPrefix, key []byte, value cstates.StateValue, trie bool) error {
+	if _, ok := this[string(append([]byte{byte(prefix)}, key...))]; !ok {
+		this.TryAdd(prefix, key, value, trie)
+	}
+	return nil
+}
+
+func (this testStateStore) TryGet(prefix scommon.DataEntryPrefix, key []byte) (*scommon.StateItem, error) {
+	return this[string(append([]byte{byte(prefix)}, key...))], nil
+}
+
+func (this testStateStore) TryGetAndChange(prefix scommon.DataEntryPrefix, key []byte, trie bool) (cstates.StateValue, error) {
+	if item := this[string(append([]byte{byte(prefix)}, key...))]; item != nil {
+		return item.Value, nil
+	}
+	return nil, nil
+}
+
+func (this testStateStore) TryDelete(prefix scommon.DataEntryPrefix, key []byte) {
+	delete(this, string(append([]byte{byte(prefix)}, key...)))
+}
+
+func (this testStateStore) Find(prefix scommon.DataEntryPrefix, key []byte) ([]*scommon.StateItem, error) {
+	var items []*scommon.StateItem
+	for k, v := range this {
+		if strings.HasPrefix(k, string(append([]byte{byte(prefix)}, key...))) {
+			items = append(items, v)
+		}
+	}
+	return items, nil
+}
+
+// appCallCode return neovm code which calls the method of contract
+func appCallCode(t *testing.T, address common.Address, method string) []byte {
+	bf := bytes.NewBuffer([]byte{byte(vm.APPCALL)})
+	c := states.Contract{Address: address, Method: method}
+	assert.Nil(t, c.Serialize(bf))
+	return bf.Bytes()
+}
+
+// newCallChain deploy contract A calls B.b, B calls C.c and C pushes 1
+func newCallChain(t *testing.T, permA, permB, permC *permission.Permissions) *SmartContract {
+	cache := make(testStateStore)
+	deploys := []struct {
+		address     common.Address
+		code        []byte
+		permissions *permission.Permissions
+	}{
+		{contractA, appCallCode(t, contractB, "b"), permA},
+		{contractB, appCallCode(t, contractC, "c"), permB},
+		{contractC, []byte{byte(vm.PUSH1)}, permC},
+	}
+	for _, d := range deploys {
+		cache.TryAdd(scommon.ST_CONTRACT, d.address[:], &payload.DeployCode{
+			Code:        stypes.VmCode{VmType: stypes.NEOVM, Code: d.code},
+			Permissions: d.permissions,
+		}, false)
+	}
+	sc := &SmartContract{
+		Config: &Config{DBCache: cache},
+		Gas:    100000,
+	}
+	sc.PushContext(&context.Context{
+		Code:            stypes.VmCode{VmType: stypes.NEOVM, Code: deploys[0].code},
+		ContractAddress: contractA,
+	})
+	return sc
+}
+
+func checkPermissionDenied(t *testing.T, err error) {
+	assert.NotNil(t, err)
+	assert.Equal(t, errors.ErrCallPermission, errors.ErrerCode(err))
+	assert.Equal(t, CALL_PERMISSION_DENIED, errors.RootErr(err))
+}
+
+func TestAppCallWithoutPermissions(t *testing.T) {
+	sc := newCallChain(t, nil, nil, nil)
+	_, err := sc.Execute()
+	assert.Nil(t, err)
+}
+
+func TestAppCallReturnValue(t *testing.T) {
+	// C pushes 1, which is returned to B and then to A
+	result, err := newCallChain(t, nil, nil, nil).Execute()
+	assert.Nil(t, err)
+	item, ok := result.(vmtype.StackItems)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), item.GetBigInteger().Int64())
+
+	// the returned value is pushed to the evaluation stack of caller
+	sc := newCallChain(t, nil, nil, nil)
+	code := append(appCallCode(t, contractC, "c"), byte(vm.PUSH2), byte(vm.ADD))
+	sc.PopContext()
+	sc.PushContext(&context.Context{
+		Code:            stypes.VmCode{VmType: stypes.NEOVM, Code: code},
+		ContractAddress: contractA,
+	})
+	result, err = sc.Execute()
+	assert.Nil(t, err)
+	item, ok = result.(vmtype.StackItems)
+	assert.True(t, ok)
+	assert.Equal(t, int64(3), item.GetBigInteger().Int64())
+}
+
+func TestAppCallPermissions(t *testing.T) {
+	callB := &permission.Permissions{Calls: []permission.Permission{{Contract: contractB.ToHexString(), Methods: []string{"b"}}}}
+	callC := &permission.Permissions{Calls: []permission.Permission{{Contract: contractC.ToHexString(), Methods: []string{permission.Wildcard}}}}
+
+	_, err := newCallChain(t, callB, callC, nil).Execute()
+	assert.Nil(t, err)
+
+	callOther := &permission.Permissions{Calls: []permission.Permission{{Contract: contractB.ToHexString(), Methods: []string{"x"}}}}
+	_, err = newCallChain(t, callOther, nil, nil).Execute()
+	checkPermissionDenied(t, err)
+
+	// the nested call of B is checked with the permissions of B
+	callNone := &permission.Permissions{Calls: []permission.Permission{}}
+	_, err = newCallChain(t, callB, callNone, nil).Execute()
+	checkPermissionDenied(t, err)
+}
+
+func TestAppCallCallers(t *testing.T) {
+	callerB := &permission.Permissions{Callers: []string{contractB.ToHexString()}}
+	_, err := newCallChain(t, nil, nil, callerB).Execute()
+	assert.Nil(t, err)
+
+	// only the immediate caller is accepted, A isn't trusted through B
+	callerA := &permission.Permissions{Callers: []string{contractA.ToHexString()}}
+	_, err = newCallChain(t, nil, nil, callerA).Execute()
+	checkPermissionDenied(t, err)
+
+	_, err = newCallChain(t, nil, callerA, callerA).Execute()
+	checkPermissionDenied(t, err)
+
+	anyCaller := &permission.Permissions{Callers: []string{permission.Wildcard}}
+	_, err = newCallChain(t, nil, anyCaller, anyCaller).Execute()
+	assert.Nil(t, err)
+}
+
+func TestAppCallVmTypes(t *testing.T) {
+	nativeContract := common.Address{byte(stypes.Native), 1}
+	wasmContract := common.Address{byte(stypes.WASMVM), 1}
+	callB := &permission.Permissions{Calls: []permission.Permission{{Contract: contractB.ToHexString(), Methods: []string{permission.Wildcard}}}}
+
+	sc := newCallChain(t, callB, nil, nil)
+	_, err := sc.AppCall(nativeContract, "transfer", nil, nil)
+	checkPermissionDenied(t, err)
+	_, err = sc.AppCall(wasmContract, "transfer", nil, nil)
+	checkPermissionDenied(t, err)
+	_, err = sc.AppCall(contractC, "c", nil, nil)
+	checkPermissionDenied(t, err)
+	_, err = sc.AppCall(contractB, "", nil, nil)
+	assert.Nil(t, err)
+}
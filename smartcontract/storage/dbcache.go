@@ -19,6 +19,9 @@
 package storage
 
 import (
+	"bytes"
+	"sort"
+
 	"github.com/ontio/ontology/core/states"
 	"github.com/ontio/ontology/core/store/common"
 )
@@ -121,3 +124,28 @@ func (cloneCache *CloneCache) Delete(prefix common.DataEntryPrefix, key []byte)
 		}
 	}
 }
+
+// Find items of keys with the prefix in key order, transaction cache is merged with block cache
+func (cloneCache *CloneCache) Find(prefix common.DataEntryPrefix, key []byte) ([]*common.StateItem, error) {
+	items, err := cloneCache.Store.Find(prefix, key)
+	if err != nil {
+		return nil, err
+	}
+	var states []*common.StateItem
+	for _, item := range items {
+		if _, ok := cloneCache.Memory[string(append([]byte{byte(prefix)}, item.Key...))]; !ok {
+			states = append(states, item)
+		}
+	}
+	keyPrefix := append([]byte{byte(prefix)}, key...)
+	for k, v := range cloneCache.Memory {
+		if v.State == common.Deleted || !bytes.HasPrefix([]byte(k), keyPrefix) {
+			continue
+		}
+		states = append(states, &common.StateItem{Key: v.Key, Value: v.Value, State: v.State})
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Key < states[j].Key
+	})
+	return states, nil
+}
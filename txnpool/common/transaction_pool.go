@@ -49,7 +49,8 @@ type TXEntry struct {
 // in the ledger.
 type TXPool struct {
 	sync.RWMutex
-	txList map[common.Uint256]*TXEntry // Transactions which have been verified
+	txList    map[common.Uint256]*TXEntry       // Transactions which have been verified
+	payerFees map[common.Address]common.Fixed64 // Fees paid by each payer in the pool
 }
 
 // Init creates a new transaction pool to gather.
@@ -57,6 +58,7 @@ func (tp *TXPool) Init() {
 	tp.Lock()
 	defer tp.Unlock()
 	tp.txList = make(map[common.Uint256]*TXEntry)
+	tp.payerFees = make(map[common.Address]common.Fixed64)
 }
 
 // AddTxList adds a valid transaction to the transaction pool. If the
@@ -73,6 +75,7 @@ func (tp *TXPool) AddTxList(txEntry *TXEntry) bool {
 	}
 
 	tp.txList[txHash] = txEntry
+	tp.addPayerFees(txEntry.Tx)
 	return true
 }
 
@@ -87,8 +90,9 @@ func (tp *TXPool) CleanTransactionList(txs []*types.Transaction) error {
 			txsNum = txsNum - 1
 			continue
 		}
-		if _, ok := tp.txList[tx.Hash()]; ok {
+		if txEntry, ok := tp.txList[tx.Hash()]; ok {
 			delete(tp.txList, tx.Hash())
+			tp.delPayerFees(txEntry.Tx)
 			cleaned++
 		}
 	}
@@ -101,18 +105,67 @@ func (tp *TXPool) CleanTransactionList(txs []*types.Transaction) error {
 	return nil
 }
 
+// RemoveExpiredTxs removes the transactions which can't be included in
+// the block of the height any more, and returns the removed transactions.
+func (tp *TXPool) RemoveExpiredTxs(height uint32) []*types.Transaction {
+	tp.Lock()
+	defer tp.Unlock()
+	var expired []*types.Transaction
+	for hash, txEntry := range tp.txList {
+		if txEntry.Tx.IsExpired(height) {
+			delete(tp.txList, hash)
+			tp.delPayerFees(txEntry.Tx)
+			expired = append(expired, txEntry.Tx)
+		}
+	}
+	return expired
+}
+
 // DelTxList removes a single transaction from the pool.
 func (tp *TXPool) DelTxList(tx *types.Transaction) bool {
 	tp.Lock()
 	defer tp.Unlock()
 	txHash := tx.Hash()
-	if _, ok := tp.txList[txHash]; !ok {
+	txEntry, ok := tp.txList[txHash]
+	if !ok {
 		return false
 	}
 	delete(tp.txList, txHash)
+	tp.delPayerFees(txEntry.Tx)
 	return true
 }
 
+// GetPayerFees returns the fees paid in the pool by the payers of the
+// transaction.
+func (tp *TXPool) GetPayerFees(tx *types.Transaction) map[common.Address]common.Fixed64 {
+	tp.RLock()
+	defer tp.RUnlock()
+	fees := make(map[common.Address]common.Fixed64, len(tx.Fee))
+	for _, fee := range tx.Fee {
+		if amount, ok := tp.payerFees[fee.Payer]; ok {
+			fees[fee.Payer] = amount
+		}
+	}
+	return fees
+}
+
+// addPayerFees adds the fee of the transaction to its payers
+func (tp *TXPool) addPayerFees(tx *types.Transaction) {
+	for _, fee := range tx.Fee {
+		tp.payerFees[fee.Payer] += fee.Amount
+	}
+}
+
+// delPayerFees subtracts the fee of the transaction from its payers
+func (tp *TXPool) delPayerFees(tx *types.Transaction) {
+	for _, fee := range tx.Fee {
+		tp.payerFees[fee.Payer] -= fee.Amount
+		if tp.payerFees[fee.Payer] == 0 {
+			delete(tp.payerFees, fee.Payer)
+		}
+	}
+}
+
 // compareTxHeight compares a verifed transaction's height with the next
 // block height from consensus. If the height is less than the next block
 // height, re-verify it.
@@ -129,7 +182,9 @@ func (tp *TXPool) compareTxHeight(txEntry *TXEntry, height uint32) bool {
 // GetTxPool gets the transaction lists from the pool for the consensus,
 // if the byCount is marked, return the configured number at most; if the
 // the byCount is not marked, return all of the current transaction pool.
-func (tp *TXPool) GetTxPool(byCount bool, height uint32) ([]*TXEntry,
+// The transactions which can't be included in the block of blockHeight
+// are skipped.
+func (tp *TXPool) GetTxPool(byCount bool, height, blockHeight uint32) ([]*TXEntry,
 	[]*types.Transaction) {
 	tp.RLock()
 	defer tp.RUnlock()
@@ -146,6 +201,9 @@ func (tp *TXPool) GetTxPool(byCount bool, height uint32) ([]*TXEntry,
 	txList := make([]*TXEntry, 0, count)
 	oldTxList := make([]*types.Transaction, 0)
 	for _, txEntry := range tp.txList {
+		if txEntry.Tx.IsExpired(blockHeight) {
+			continue
+		}
 		if !tp.compareTxHeight(txEntry, height) {
 			oldTxList = append(oldTxList, txEntry.Tx)
 			continue
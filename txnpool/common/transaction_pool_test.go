@@ -78,7 +78,7 @@ func TestTxPool(t *testing.T) {
 		return
 	}
 
-	txList := txPool.GetTxPool(true)
+	txList, _ := txPool.GetTxPool(true, 0, 1)
 	for _, v := range txList {
 		fmt.Println(v)
 	}
@@ -104,3 +104,35 @@ func TestTxPool(t *testing.T) {
 		return
 	}
 }
+
+func TestGetTxPoolExpired(t *testing.T) {
+	txPool := &TXPool{}
+	txPool.Init()
+
+	tx := &types.Transaction{
+		Version:    0,
+		Attributes: []*types.TxAttribute{types.NewValidUntilAttribute(10)},
+		TxType:     types.BookKeeping,
+		Payload:    &payload.BookKeeping{Nonce: 1},
+	}
+	txEntry := &TXEntry{
+		Tx:    tx,
+		Attrs: []*TXAttr{},
+	}
+	if !txPool.AddTxList(txEntry) {
+		t.Error("Failed to add tx to the pool")
+		return
+	}
+
+	txList, _ := txPool.GetTxPool(false, 5, 10)
+	if len(txList) != 1 {
+		t.Error("Transaction valid until the next block should be returned")
+		return
+	}
+	// Transactions are verified at a lower height than the next block in the incremental validator
+	txList, _ = txPool.GetTxPool(false, 5, 11)
+	if len(txList) != 0 {
+		t.Error("Transaction expired before the next block should not be returned")
+		return
+	}
+}
@@ -82,6 +82,9 @@ const (
 	DuplicateStats              // The count that the transactions are duplicated input
 	SigErrStats                 // The count that the transactions' signature error
 	StateErrStats               // The count that the transactions are invalid in database
+	BatchStats                  // The count of batches verified by stateless validators
+	BatchTxStats                // The count of the transactions verified in batches
+	BatchTimeStats              // The microseconds spent verifying the batches
 
 	MaxStats
 )
@@ -201,6 +201,7 @@ func (tpa *TxPoolActor) Receive(context actor.Context) {
 
 		if msg.Block != nil {
 			tpa.server.cleanTransactionList(msg.Block.Transactions)
+			tpa.server.removeExpiredTxs(msg.Block.Header.Height)
 		}
 
 	default:
@@ -243,6 +244,9 @@ func (vpa *VerifyRspActor) Receive(context actor.Context) {
 
 		vpa.server.assignRspToWorker(msg)
 
+	case *types.VerifyStats:
+		vpa.server.addVerifyStats(msg)
+
 	default:
 		log.Warn("txpool-verify actor:Unknown msg ", msg, "type", reflect.TypeOf(msg))
 	}
@@ -23,11 +23,14 @@ package proc
 import (
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/ontio/ontology-eventbus/actor"
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/core/ledger"
 	tx "github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/core/validation"
 	"github.com/ontio/ontology/errors"
 	tc "github.com/ontio/ontology/txnpool/common"
 	"github.com/ontio/ontology/validator/types"
@@ -74,6 +77,7 @@ type TXPoolServer struct {
 	validators    *registerValidators                 // The registered validators
 	stats         txStats                             // The transaction statstics
 	slots         chan struct{}                       // The limited slots for the new transaction
+	feeMu         sync.Mutex                          // Sync mutex for checking fee balance and adding tx
 }
 
 // NewTxPoolServer creates a new tx pool server to schedule workers to
@@ -410,7 +414,9 @@ func (s *TXPoolServer) getTransaction(hash common.Uint256) *tx.Transaction {
 
 // getTxPool returns a tx list for consensus.
 func (s *TXPoolServer) getTxPool(byCount bool, height uint32) []*tc.TXEntry {
-	avlTxList, oldTxList := s.txPool.GetTxPool(byCount, height)
+	//Consensus is making the next block of ledger
+	blockHeight := ledger.DefLedger.GetCurrentBlockHeight() + 1
+	avlTxList, oldTxList := s.txPool.GetTxPool(byCount, height, blockHeight)
 
 	for _, t := range oldTxList {
 		s.delTransaction(t)
@@ -436,6 +442,14 @@ func (s *TXPoolServer) cleanTransactionList(txs []*tx.Transaction) error {
 	return s.txPool.CleanTransactionList(txs)
 }
 
+// removeExpiredTxs evicts the txs which expire after the block of height
+func (s *TXPoolServer) removeExpiredTxs(height uint32) {
+	expired := s.txPool.RemoveExpiredTxs(height + 1)
+	for _, t := range expired {
+		log.Infof("Transaction %x is evicted from pool, err: %s", t.Hash(), errors.ErrTxExpired)
+	}
+}
+
 // delTransaction deletes a transaction in the tx pool.
 func (s *TXPoolServer) delTransaction(t *tx.Transaction) {
 	s.txPool.DelTxList(t)
@@ -450,6 +464,34 @@ func (s *TXPoolServer) addTxList(txEntry *tc.TXEntry) bool {
 	return ret
 }
 
+// addVerifiedTx adds a verified transaction to the tx pool. The payers
+// of the transaction should afford its fee besides the fees of the txs
+// in the pool, except the tx in the block from consensus, whose fee is
+// accounted with the block by the block validator.
+func (s *TXPoolServer) addVerifiedTx(txEntry *tc.TXEntry) errors.ErrCode {
+	s.feeMu.Lock()
+	defer s.feeMu.Unlock()
+
+	if txEntry.Tx.GetTotalFee() > 0 && !s.isInPendingBlock(txEntry.Tx.Hash()) {
+		pending := s.txPool.GetPayerFees(txEntry.Tx)
+		errCode := validation.VerifyFeeBalance(txEntry.Tx, ledger.DefLedger, pending)
+		if errCode != errors.ErrNoError {
+			return errCode
+		}
+	}
+	s.addTxList(txEntry)
+	return errors.ErrNoError
+}
+
+// isInPendingBlock checks whether a transaction is in the block from
+// consensus which is verifying.
+func (s *TXPoolServer) isInPendingBlock(hash common.Uint256) bool {
+	s.pendingBlock.mu.RLock()
+	defer s.pendingBlock.mu.RUnlock()
+	_, ok := s.pendingBlock.unProcessedTxs[hash]
+	return ok
+}
+
 // increaseStats increases the count with the stats type
 func (s *TXPoolServer) increaseStats(v tc.TxnStatsType) {
 	s.stats.Lock()
@@ -457,6 +499,15 @@ func (s *TXPoolServer) increaseStats(v tc.TxnStatsType) {
 	s.stats.count[v-1]++
 }
 
+// addVerifyStats adds the statistics of a batch verified by validator
+func (s *TXPoolServer) addVerifyStats(stats *types.VerifyStats) {
+	s.stats.Lock()
+	defer s.stats.Unlock()
+	s.stats.count[tc.BatchStats-1] += stats.Batches
+	s.stats.count[tc.BatchTxStats-1] += stats.TxCount
+	s.stats.count[tc.BatchTimeStats-1] += uint64(stats.Elapsed / time.Microsecond)
+}
+
 // getStats returns the transaction statistics
 func (s *TXPoolServer) getStats() []uint64 {
 	s.stats.RLock()
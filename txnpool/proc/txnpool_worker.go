@@ -155,14 +155,19 @@ func (worker *txPoolWorker) handleTimeoutEvent() {
 }
 
 // putTxPool adds a valid transaction to the tx pool and removes it from
-// the pending list.
+// the pending list. If the payers can't afford the fee, the transaction
+// is dropped.
 func (worker *txPoolWorker) putTxPool(pt *pendingTx) bool {
 	txEntry := &tc.TXEntry{
 		Tx:    pt.tx,
 		Attrs: pt.ret,
 		Fee:   pt.tx.GetTotalFee(),
 	}
-	worker.server.addTxList(txEntry)
+	if errCode := worker.server.addVerifiedTx(txEntry); errCode != errors.ErrNoError {
+		log.Info(fmt.Sprintf("Transaction %x invalid: %s", pt.tx.Hash(), errCode.Error()))
+		worker.server.removePendingTx(pt.tx.Hash(), errCode)
+		return false
+	}
 	worker.server.removePendingTx(pt.tx.Hash(), errors.ErrNoError)
 	return true
 }
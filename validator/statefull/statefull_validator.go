@@ -24,6 +24,7 @@ import (
 	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/core/ledger"
 	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/core/validation"
 	"github.com/ontio/ontology/errors"
 	"github.com/ontio/ontology/validator/db"
 	vatypes "github.com/ontio/ontology/validator/types"
@@ -76,6 +77,8 @@ func (self *validator) Receive(context actor.Context) {
 			errCode = errors.ErrUnknown
 		} else if exist {
 			errCode = errors.ErrDuplicatedTx
+		} else {
+			errCode = validation.VerifyTransactionWithLedger(&msg.Tx, ledger.DefLedger)
 		}
 
 		response := &vatypes.CheckResponse{
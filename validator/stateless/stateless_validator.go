@@ -20,13 +20,24 @@ package stateless
 
 import (
 	"reflect"
+	"runtime"
+	"sync"
+	"time"
 
 	"github.com/ontio/ontology-eventbus/actor"
 	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/core/validation"
+	"github.com/ontio/ontology/errors"
 	vatypes "github.com/ontio/ontology/validator/types"
 )
 
+const (
+	BATCH_WINDOW   = 10 * time.Millisecond // The time window to accumulate txs into a batch
+	MAX_BATCH_SIZE = 1024                  // The max count of txs verified in a batch
+	MAX_PENDING    = 4 * MAX_BATCH_SIZE    // The max count of txs waiting for batching
+)
+
 // Validator wraps validator actor's pid
 type Validator interface {
 	// Register send a register message to poolId
@@ -37,14 +48,42 @@ type Validator interface {
 	VerifyType() vatypes.VerifyType
 }
 
+// checkReq is a received CheckTx with its sender
+type checkReq struct {
+	sender *actor.PID
+	msg    *vatypes.CheckTx
+}
+
+// verifyJob is a tx verified by the worker pool
+type verifyJob struct {
+	tx      *types.Transaction
+	errCode *errors.ErrCode
+	wg      *sync.WaitGroup
+}
+
 type validator struct {
-	pid *actor.PID
-	id  string
+	pid    *actor.PID
+	id     string
+	reqCh  chan *checkReq  // CheckTx requests waiting for batching
+	jobCh  chan *verifyJob // txs waiting for the worker pool
+	stopCh chan struct{}
 }
 
-// NewValidator spawns a validator actor and return its pid wraped in Validator
+// NewValidator spawns a validator actor and return its pid wraped in Validator.
+// The validator accumulates CheckTx requests in BATCH_WINDOW, and verifies the
+// batch in parallel with a worker pool of one worker per cpu.
 func NewValidator(id string) (Validator, error) {
-	validator := &validator{id: id}
+	validator := &validator{
+		id:     id,
+		reqCh:  make(chan *checkReq, MAX_PENDING),
+		jobCh:  make(chan *verifyJob, MAX_BATCH_SIZE),
+		stopCh: make(chan struct{}),
+	}
+	for i := 0; i < runtime.NumCPU(); i++ {
+		go validator.verifyWorker()
+	}
+	go validator.batchLoop()
+
 	props := actor.FromProducer(func() actor.Actor {
 		return validator
 	})
@@ -64,26 +103,109 @@ func (self *validator) Receive(context actor.Context) {
 		log.Info("stateless-validator: restarting")
 	case *actor.Stopped:
 		log.Info("stateless-validator: stopped")
+		close(self.stopCh)
 	case *vatypes.CheckTx:
 		log.Debugf("stateless-validator receive tx %x", msg.Tx.Hash())
-		sender := context.Sender()
-		errCode := validation.VerifyTransaction(&msg.Tx)
+		self.reqCh <- &checkReq{sender: context.Sender(), msg: msg}
+	case *vatypes.UnRegisterAck:
+		context.Self().Stop()
+	default:
+		log.Info("stateless-validator: unknown msg ", msg, "type", reflect.TypeOf(msg))
+	}
 
-		response := &vatypes.CheckResponse{
-			WorkerId: msg.WorkerId,
-			ErrCode:  errCode,
-			Hash:     msg.Tx.Hash(),
+}
+
+// batchLoop collects the CheckTx requests into batches, a batch is verified
+// when it is full or BATCH_WINDOW passed since its first request
+func (self *validator) batchLoop() {
+	var batch []*checkReq
+	var window <-chan time.Time
+	for {
+		select {
+		case req := <-self.reqCh:
+			if len(batch) == 0 {
+				window = time.After(BATCH_WINDOW)
+			}
+			batch = append(batch, req)
+			if len(batch) < MAX_BATCH_SIZE {
+				continue
+			}
+		case <-window:
+		case <-self.stopCh:
+			close(self.jobCh)
+			return
+		}
+		self.verifyBatch(batch)
+		batch = nil
+		window = nil
+	}
+}
+
+// verifyBatch verifies the batch, and responds every request and the
+// statistics of the batch to the senders
+func (self *validator) verifyBatch(batch []*checkReq) {
+	if len(batch) == 0 {
+		return
+	}
+	start := time.Now()
+	txs := make([]*types.Transaction, 0, len(batch))
+	for _, req := range batch {
+		txs = append(txs, &req.msg.Tx)
+	}
+	errCodes := self.verifyTxs(txs)
+	elapsed := time.Since(start)
+	log.Debugf("stateless-validator verify %d txs in %v", len(txs), elapsed)
+
+	for i, req := range batch {
+		req.sender.Tell(&vatypes.CheckResponse{
+			WorkerId: req.msg.WorkerId,
+			ErrCode:  errCodes[i],
+			Hash:     req.msg.Tx.Hash(),
 			Type:     self.VerifyType(),
 			Height:   0,
+		})
+	}
+	for sender, stats := range self.batchStats(batch, elapsed) {
+		sender.Tell(stats)
+	}
+}
+
+// batchStats returns the statistics of the batch for each sender. The tx
+// count is per sender, while the batch and its elapsed time are counted
+// once, by the sender of the first request.
+func (self *validator) batchStats(batch []*checkReq, elapsed time.Duration) map[*actor.PID]*vatypes.VerifyStats {
+	stats := make(map[*actor.PID]*vatypes.VerifyStats)
+	for _, req := range batch {
+		if stats[req.sender] == nil {
+			stats[req.sender] = &vatypes.VerifyStats{Type: self.VerifyType()}
 		}
+		stats[req.sender].TxCount++
+	}
+	first := stats[batch[0].sender]
+	first.Batches = 1
+	first.Elapsed = elapsed
+	return stats
+}
 
-		sender.Tell(response)
-	case *vatypes.UnRegisterAck:
-		context.Self().Stop()
-	default:
-		log.Info("stateless-validator: unknown msg ", msg, "type", reflect.TypeOf(msg))
+// verifyTxs verifies the txs in parallel by the worker pool. ontology-crypto
+// has no batch verification for any scheme yet, so every signature is
+// verified separately.
+func (self *validator) verifyTxs(txs []*types.Transaction) []errors.ErrCode {
+	errCodes := make([]errors.ErrCode, len(txs))
+	wg := new(sync.WaitGroup)
+	wg.Add(len(txs))
+	for i, tx := range txs {
+		self.jobCh <- &verifyJob{tx: tx, errCode: &errCodes[i], wg: wg}
 	}
+	wg.Wait()
+	return errCodes
+}
 
+func (self *validator) verifyWorker() {
+	for job := range self.jobCh {
+		*job.errCode = validation.VerifyTransaction(job.tx)
+		job.wg.Done()
+	}
 }
 
 func (self *validator) VerifyType() vatypes.VerifyType {
@@ -0,0 +1,101 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package stateless
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ontio/ontology-eventbus/actor"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/core/utils"
+	"github.com/ontio/ontology/errors"
+	stypes "github.com/ontio/ontology/smartcontract/types"
+	vatypes "github.com/ontio/ontology/validator/types"
+)
+
+func init() {
+	log.Init(log.Stdout)
+}
+
+func TestVerifyTxs(t *testing.T) {
+	v := &validator{
+		jobCh: make(chan *verifyJob, MAX_BATCH_SIZE),
+	}
+	for i := 0; i < 4; i++ {
+		go v.verifyWorker()
+	}
+	defer close(v.jobCh)
+
+	acc := account.NewAccount("")
+	var txs []*types.Transaction
+	for i := 0; i < 100; i++ {
+		tx := utils.NewInvokeTransaction(stypes.VmCode{VmType: stypes.Native, Code: []byte{byte(i)}})
+		tx.Fee = []*types.Fee{{Payer: acc.Address}}
+		if err := utils.SignTransaction(acc, tx); err != nil {
+			t.Fatalf("SignTransaction error %s", err)
+		}
+		//every third tx is signed by other account
+		if i%3 == 0 {
+			tx.Sigs[0].PubKeys[0] = account.NewAccount("").PubKey()
+		}
+		txs = append(txs, tx)
+	}
+
+	errCodes := v.verifyTxs(txs)
+	if len(errCodes) != len(txs) {
+		t.Fatalf("got %d results of %d txs", len(errCodes), len(txs))
+	}
+	for i, errCode := range errCodes {
+		if i%3 == 0 && errCode == errors.ErrNoError {
+			t.Errorf("tx %d with invalid signature passed verification", i)
+		}
+		if i%3 != 0 && errCode != errors.ErrNoError {
+			t.Errorf("tx %d verify error %s", i, errCode)
+		}
+	}
+}
+
+func TestBatchStats(t *testing.T) {
+	v := &validator{}
+	sender1 := &actor.PID{Id: "sender1"}
+	sender2 := &actor.PID{Id: "sender2"}
+	var batch []*checkReq
+	for _, sender := range []*actor.PID{sender2, sender1, sender2} {
+		batch = append(batch, &checkReq{sender: sender, msg: &vatypes.CheckTx{}})
+	}
+	elapsed := 5 * time.Millisecond
+
+	stats := v.batchStats(batch, elapsed)
+	if len(stats) != 2 {
+		t.Fatalf("got stats of %d senders", len(stats))
+	}
+	if stats[sender1].TxCount != 1 || stats[sender2].TxCount != 2 {
+		t.Errorf("got tx count %d and %d of senders", stats[sender1].TxCount, stats[sender2].TxCount)
+	}
+	//only the sender of the first request counts the batch
+	if stats[sender1].Batches != 0 || stats[sender1].Elapsed != 0 {
+		t.Errorf("batch is counted by other sender")
+	}
+	if stats[sender2].Batches != 1 || stats[sender2].Elapsed != elapsed {
+		t.Errorf("batch is not counted by the sender of first request")
+	}
+}
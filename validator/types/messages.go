@@ -19,6 +19,8 @@
 package types
 
 import (
+	"time"
+
 	"github.com/ontio/ontology-eventbus/actor"
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/core/types"
@@ -55,6 +57,16 @@ type CheckResponse struct {
 	ErrCode  errors.ErrCode
 }
 
+// VerifyStats is the statistics of a batch of txs verified by validator.
+// A batch is sent to every sender of its txs with the tx count of the
+// sender, but only one of them has the batch count and elapsed time.
+type VerifyStats struct {
+	Type    VerifyType
+	Batches uint64
+	TxCount uint64
+	Elapsed time.Duration
+}
+
 // VerifyType of validator
 type VerifyType uint8
 
@@ -18,8 +18,32 @@
 
 package neovm
 
+import (
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/core/signature"
+)
+
 func opHash(e *ExecutionEngine) (VMState, error) {
 	x := PopByteArray(e)
 	PushData(e, Hash(x, e))
 	return NONE, nil
-}
\ No newline at end of file
+}
+
+// opCheckSig verifies the signature of data with the serialized public key,
+// any key type and signature scheme supported by ontology-crypto is accepted
+func opCheckSig(e *ExecutionEngine) (VMState, error) {
+	pubKey := PopByteArray(e)
+	data := PopByteArray(e)
+	sig := PopByteArray(e)
+	PushData(e, CheckSig(pubKey, data, sig))
+	return NONE, nil
+}
+
+// CheckSig returns whether sig is the valid signature of data signed by the serialized public key
+func CheckSig(pubKey, data, sig []byte) bool {
+	key, err := keypair.DeserializePublicKey(pubKey)
+	if err != nil {
+		return false
+	}
+	return signature.Verify(key, data, sig) == nil
+}
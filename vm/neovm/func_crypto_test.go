@@ -0,0 +1,84 @@
+// Copyright 2017 The Ontology Authors
+// This file is part of the Ontology library.
+//
+// The Ontology library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Ontology library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Ontology library. If not, see <http://www.gnu.org/licenses/>.
+
+package neovm
+
+import (
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	s "github.com/ontio/ontology-crypto/signature"
+	"github.com/ontio/ontology/core/signature"
+	vtypes "github.com/ontio/ontology/vm/neovm/types"
+)
+
+type testSigner struct {
+	privKey keypair.PrivateKey
+	pubKey  keypair.PublicKey
+	scheme  s.SignatureScheme
+}
+
+func (this *testSigner) PrivKey() keypair.PrivateKey { return this.privKey }
+func (this *testSigner) PubKey() keypair.PublicKey   { return this.pubKey }
+func (this *testSigner) Scheme() s.SignatureScheme   { return this.scheme }
+
+func newTestSigner(t *testing.T, keyType keypair.KeyType, curve byte, scheme s.SignatureScheme) *testSigner {
+	pri, pub, err := keypair.GenerateKeyPair(keyType, curve)
+	if err != nil {
+		t.Fatalf("GenerateKeyPair error %s", err)
+	}
+	return &testSigner{pri, pub, scheme}
+}
+
+func checkSig(pubKey, data, sig []byte) bool {
+	var e ExecutionEngine
+	stack := NewRandAccessStack()
+	stack.Push(NewStackItem(vtypes.NewByteArray(sig)))
+	stack.Push(NewStackItem(vtypes.NewByteArray(data)))
+	stack.Push(NewStackItem(vtypes.NewByteArray(pubKey)))
+	e.EvaluationStack = stack
+
+	opCheckSig(&e)
+	return PopBoolean(&e)
+}
+
+func TestOpCheckSig(t *testing.T) {
+	signers := []*testSigner{
+		newTestSigner(t, keypair.PK_ECDSA, keypair.P256, s.SHA256withECDSA),
+		newTestSigner(t, keypair.PK_ECDSA, keypair.SECP256K1, s.SHA256withECDSA),
+		newTestSigner(t, keypair.PK_SM2, keypair.SM2P256V1, s.SM3withSM2),
+		newTestSigner(t, keypair.PK_EDDSA, keypair.ED25519, s.SHA512withEDDSA),
+	}
+	data := []byte("hello world")
+	for i, signer := range signers {
+		sig, err := signature.Sign(signer, data)
+		if err != nil {
+			t.Fatalf("Sign with scheme %d error %s", signer.scheme, err)
+		}
+		for j, other := range signers {
+			pubKey := keypair.SerializePublicKey(other.pubKey)
+			if ok := checkSig(pubKey, data, sig); ok != (i == j) {
+				t.Fatalf("NeoVM OpCheckSig test failed, signer %d, public key %d, got %v", i, j, ok)
+			}
+		}
+		if checkSig(keypair.SerializePublicKey(signer.pubKey), []byte("hello"), sig) {
+			t.Fatal("NeoVM OpCheckSig test failed, signature of other data verified")
+		}
+	}
+	if checkSig([]byte{1, 2, 3}, data, []byte{1, 2, 3}) {
+		t.Fatal("NeoVM OpCheckSig test failed, invalid public key verified")
+	}
+}
@@ -62,7 +62,11 @@ func (vm *VM) doCall(compiled compiledFunction, index int64) {
 
 		v, ok := vm.Services[compiled.name]
 		if ok {
+			vm.useGas(HOST_CALL_GAS)
 			rtn, err := v(vm.Engine)
+			if err == ErrOutOfGas {
+				panic(err)
+			}
 			if err != nil || !rtn {
 				log.Errorf("call method :%s failed\n", compiled.name)
 			}
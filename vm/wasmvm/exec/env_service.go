@@ -133,6 +133,9 @@ func calloc(engine *ExecutionEngine) (bool, error) {
 	}
 	count := int(params[0])
 	length := int(params[1])
+	if err := engine.UseGas(uint64(count*length) * MEMORY_BYTE_GAS); err != nil {
+		return false, err
+	}
 	//we don't know whats the alloc type here
 	index, err := engine.vm.memory.MallocPointer(count*length, memory.PUnkown)
 	if err != nil {
@@ -156,6 +159,9 @@ func malloc(engine *ExecutionEngine) (bool, error) {
 		return false, errors.New("parameter count error while call calloc")
 	}
 	size := int(params[0])
+	if err := engine.UseGas(uint64(size) * MEMORY_BYTE_GAS); err != nil {
+		return false, err
+	}
 	//we don't know whats the alloc type here
 	index, err := engine.vm.memory.MallocPointer(size, memory.PUnkown)
 	if err != nil {
@@ -616,6 +622,9 @@ func jsonMashal(engine *ExecutionEngine) (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	if err := engine.UseGas(uint64(len(jsonstr)) * MEMORY_BYTE_GAS); err != nil {
+		return false, err
+	}
 
 	offset, err := engine.vm.SetPointerMemory(string(jsonstr))
 	if err != nil {
@@ -91,6 +91,7 @@ type ExecutionEngine struct {
 	crypto        interfaces.Crypto
 	service       *InteropService
 	CodeContainer interfaces.CodeContainer
+	GasMeter      GasMeter
 	vm            *VM
 	backupVM      *vmstack
 }
@@ -321,6 +322,10 @@ func (e *ExecutionEngine) Call(caller common.Address,
 	defer func() {
 		if err := recover(); err != nil {
 			returnbytes = nil
+			if err == ErrOutOfGas {
+				er = ErrOutOfGas
+				return
+			}
 			er = errors.NewErr("[Call] error happened while call wasmvm")
 		}
 	}()
@@ -348,6 +353,10 @@ func (e *ExecutionEngine) InitCall(caller common.Address,
 	defer func() {
 		if err := recover(); err != nil {
 			returnbytes = nil
+			if err == ErrOutOfGas {
+				er = ErrOutOfGas
+				return
+			}
 			er = errors.NewErr("[Call] error happened while call wasmvm")
 		}
 	}()
@@ -0,0 +1,61 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package exec
+
+import (
+	"errors"
+)
+
+const (
+	INSTRUCTION_GAS  = 1  //gas of every executed instruction
+	HOST_CALL_GAS    = 10 //base gas of every call to the host service
+	MEMORY_BYTE_GAS  = 1  //gas per byte of memory allocated or returned by host service
+	STORAGE_BYTE_GAS = 10 //gas per byte of key and value put to storage
+)
+
+//ErrOutOfGas is returned by engine when the gas of execution is out
+var ErrOutOfGas = errors.New("exec: out of gas")
+
+//GasMeter consume the gas of execution.
+//The GasMeter can be shared by the engines of nested contract calls, so they are limited by the same gas
+type GasMeter interface {
+	CheckUseGas(gas uint64) bool
+}
+
+//UseGas consume gas from the GasMeter of engine, return ErrOutOfGas if the gas is not enough.
+//The gas is unlimited if the engine has no GasMeter
+func (e *ExecutionEngine) UseGas(gas uint64) error {
+	if e.GasMeter == nil {
+		return nil
+	}
+	if !e.GasMeter.CheckUseGas(gas) {
+		return ErrOutOfGas
+	}
+	return nil
+}
+
+//useGas trap the vm with ErrOutOfGas when the gas is out, the panic is recovered by engine
+func (vm *VM) useGas(gas uint64) {
+	if vm.Engine == nil {
+		return
+	}
+	if err := vm.Engine.UseGas(gas); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package exec
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+)
+
+type testGasMeter struct {
+	gas uint64
+}
+
+func (this *testGasMeter) CheckUseGas(gas uint64) bool {
+	if this.gas < gas {
+		return false
+	}
+	this.gas -= gas
+	return true
+}
+
+func TestGas(t *testing.T) {
+	code, err := ioutil.ReadFile("./test_data2/math.wasm")
+	if err != nil {
+		t.Errorf("read file error %s", err)
+		return
+	}
+	method := "add"
+	input := make([]byte, 9)
+	input[0] = byte(len(method))
+	copy(input[1:len(method)+1], []byte(method))
+	input[len(method)+1] = byte(2) //param count
+	input[len(method)+2] = byte(1) //param1 length
+	input[len(method)+3] = byte(1) //param2 length
+	input[len(method)+4] = byte(5) //param1
+	input[len(method)+5] = byte(9) //param2
+
+	meter := &testGasMeter{gas: 10000}
+	engine := NewExecutionEngine(nil, nil, nil)
+	engine.GasMeter = meter
+	res, err := engine.Call(common.Address{}, code, "", input, 0)
+	if err != nil {
+		t.Errorf("call error %s", err)
+		return
+	}
+	if binary.LittleEndian.Uint32(res) != uint32(14) {
+		t.Errorf("the result should be 14")
+		return
+	}
+	used := 10000 - meter.gas
+	if used == 0 {
+		t.Errorf("no gas used")
+		return
+	}
+
+	engine = NewExecutionEngine(nil, nil, nil)
+	engine.GasMeter = &testGasMeter{gas: used - 1}
+	_, err = engine.Call(common.Address{}, code, "", input, 0)
+	if err != ErrOutOfGas {
+		t.Errorf("call error %v, expect out of gas", err)
+		return
+	}
+}
@@ -363,6 +363,7 @@ func (vm *VM) ExecCode(insideCall bool, fnIndex int64, args ...uint64) (interfac
 func (vm *VM) execCode(isinside bool, compiled compiledFunction) uint64 {
 outer:
 	for int(vm.ctx.pc) < len(vm.ctx.code) {
+		vm.useGas(INSTRUCTION_GAS)
 		op := vm.ctx.code[vm.ctx.pc]
 		vm.ctx.pc++
 